@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"os"
 	"os/signal"
@@ -14,10 +15,14 @@ import (
 	"auth-service/internal/service"
 	http_transport "auth-service/internal/transport/http"
 	"auth-service/internal/transport/http/handlers"
+	events "auth-service/internal/transport/kafka"
 	"auth-service/internal/utils"
 	"auth-service/pkg/db/postgres"
 	"auth-service/pkg/db/redis"
 	"auth-service/pkg/logger"
+	"auth-service/pkg/mailer"
+	"auth-service/pkg/oauth"
+	"auth-service/pkg/sms"
 
 	"github.com/segmentio/kafka-go"
 	"go.uber.org/zap"
@@ -57,37 +62,179 @@ func main() {
 	}
 	defer redisClient.Close()
 
+	// Загрузка ключей подписи JWT
+	keyRing, err := loadKeyRing(cfg, log.SugaredLogger)
+	if err != nil {
+		log.Fatal("JWT key ring initialization failed: ", err)
+	}
+	go runKeyRingReloader(ctx, keyRing, cfg.JWT.KeysReloadInterval, log.SugaredLogger)
+
 	// Инициализация TokenService
 	tokenSvc, err := utils.NewTokenService(utils.TokenServiceConfig{
-		SigningKey: cfg.JWT.Secret,
-		AccessTTL:  cfg.JWT.TokenExpiry,
-		RefreshTTL: cfg.JWT.RefreshExpiry,
-		Issuer:     "auth-service",
-		Redis:      redisClient.Inner(),
-		Logger:     log.SugaredLogger,
+		KeyRing:            keyRing,
+		AccessTTL:          cfg.JWT.TokenExpiry,
+		RefreshTTL:         cfg.JWT.RefreshExpiry,
+		ServiceTTL:         cfg.JWT.ServiceTokenExpiry,
+		Issuer:             "auth-service",
+		Redis:              redisClient.Inner(),
+		Denylist:           redisClient, // через circuit breaker
+		RevocationCacheTTL: cfg.JWT.RevocationCacheTTL,
+		RevocationFailOpen: cfg.JWT.RevocationFailOpen,
+		AccessCacheTTL:     cfg.JWT.AccessCacheTTL,
+		Permissions:        repository.NewPermissionRepository(pg, log.SugaredLogger),
+		Logger:             log.SugaredLogger,
 	})
 	if err != nil {
 		log.Fatal("TokenService initialization failed: ", err)
 	}
 
+	// Инициализация почтового отправителя
+	mail, err := mailer.New(&cfg.Mailer, log.SugaredLogger)
+	if err != nil {
+		log.Fatal("Mailer initialization failed: ", err)
+	}
+
+	// Инициализация отправителя SMS
+	smsSender, err := sms.New(&cfg.SMS, log.SugaredLogger)
+	if err != nil {
+		log.Fatal("SMS sender initialization failed: ", err)
+	}
+
+	// Шифрование секретов 2FA
+	mfaCipher, err := utils.NewSecretCipher(cfg.MFA.EncryptionKey)
+	if err != nil {
+		log.Fatal("MFA cipher initialization failed: ", err)
+	}
+
+	// Внешние провайдеры входа (OAuth2/OIDC)
+	oauthManager, err := oauth.New(&cfg.OAuth, redisClient.Inner(), log.SugaredLogger)
+	if err != nil {
+		log.Fatal("OAuth initialization failed: ", err)
+	}
+
+	// Список распространённых паролей для политики паролей
+	commonPasswords, err := utils.LoadCommonPasswords(cfg.PasswordPolicy.CommonListPath)
+	if err != nil {
+		log.Fatal("Common password list loading failed: ", err)
+	}
+
 	// Инициализация репозиториев
 	userRepo := repository.NewUserRepository(pg, log.SugaredLogger)
 	outboxRepo := repository.NewOutboxRepository(pg)
+	resetRepo := repository.NewPasswordResetRepository(redisClient.Inner(), log.SugaredLogger)
+	magicLinkRepo := repository.NewMagicLinkRepository(redisClient.Inner(), log.SugaredLogger)
+	emailChangeRepo := repository.NewEmailChangeRepository(redisClient.Inner(), log.SugaredLogger)
+	otpRepo := repository.NewOTPRepository(redisClient.Inner(), log.SugaredLogger)
+	attemptRepo := repository.NewAttemptRepository(redisClient.Inner(), log.SugaredLogger)
+	mfaRepo := repository.NewMFARepository(pg, log.SugaredLogger)
+	challengeRepo := repository.NewMFAChallengeRepository(redisClient.Inner(), log.SugaredLogger)
+	identityRepo := repository.NewIdentityRepository(pg, log.SugaredLogger)
+	deletionRepo := repository.NewAccountDeletionRepository(pg, log.SugaredLogger)
+	auditRepo := repository.NewAuditRepository(pg, log.SugaredLogger)
+
+	// Журнал аутентификации пишется фоново пачками. Свой контекст: останавливается после HTTP сервера,
+	// чтобы записи из завершающихся запросов не потерялись
+	auditCtx, auditCancel := context.WithCancel(context.Background())
+	defer auditCancel()
+	auditLogger := service.NewAuditLogger(auditRepo, service.AuditConfig{
+		BufferSize:    cfg.Audit.BufferSize,
+		BatchSize:     cfg.Audit.BatchSize,
+		FlushInterval: cfg.Audit.FlushInterval,
+	}, log.SugaredLogger)
+	auditLogger.Start(auditCtx)
 
 	// Инициализация сервисов
-	authSvc := service.NewAuthService(userRepo, outboxRepo, tokenSvc, log.SugaredLogger)
+	authSvc := service.NewAuthService(userRepo, outboxRepo, resetRepo, magicLinkRepo, emailChangeRepo, otpRepo, attemptRepo, mfaRepo, challengeRepo, identityRepo, tokenSvc, mail, smsSender, mfaCipher, oauthManager, auditLogger, service.AuthServiceConfig{
+		LinkBaseURL:          cfg.Mailer.LinkBaseURL,
+		VerifyTokenTTL:       cfg.EmailVerification.TokenTTL,
+		VerifyResendInterval: cfg.EmailVerification.ResendInterval,
+		ResetTokenTTL:        cfg.PasswordReset.TokenTTL,
+		EmailChangeTokenTTL:  cfg.EmailChange.TokenTTL,
+		UserStatusCacheTTL:   cfg.JWT.UserStatusCacheTTL,
+		LoginProtection: service.LoginProtectionConfig{
+			Window:              cfg.LoginProtection.Window,
+			MaxFailuresPerEmail: cfg.LoginProtection.MaxFailuresPerEmail,
+			MaxFailuresPerIP:    cfg.LoginProtection.MaxFailuresPerIP,
+			BackoffBase:         cfg.LoginProtection.BackoffBase,
+			BackoffMax:          cfg.LoginProtection.BackoffMax,
+			LockoutDuration:     cfg.LoginProtection.LockoutDuration,
+		},
+		MFA: service.MFAConfig{
+			Issuer:        cfg.MFA.Issuer,
+			ChallengeTTL:  cfg.MFA.ChallengeTTL,
+			MaxAttempts:   cfg.MFA.MaxAttempts,
+			RecoveryCodes: cfg.MFA.RecoveryCodes,
+		},
+		PasswordPolicy: service.PasswordPolicyConfig{
+			MinLength:            cfg.PasswordPolicy.MinLength,
+			MaxLength:            cfg.PasswordPolicy.MaxLength,
+			ForbidEmailLocalPart: cfg.PasswordPolicy.ForbidEmailLocalPart,
+			CommonPasswords:      commonPasswords,
+		},
+		MagicLink: service.MagicLinkConfig{
+			TokenTTL:            cfg.MagicLink.TokenTTL,
+			Window:              cfg.MagicLink.Window,
+			MaxRequestsPerEmail: cfg.MagicLink.MaxRequestsPerEmail,
+			MaxRequestsPerIP:    cfg.MagicLink.MaxRequestsPerIP,
+		},
+		OTP: service.OTPConfig{
+			CodeTTL:          cfg.OTP.CodeTTL,
+			CodeLength:       cfg.OTP.CodeLength,
+			MaxAttempts:      cfg.OTP.MaxAttempts,
+			Window:           cfg.OTP.Window,
+			MaxSendsPerPhone: cfg.OTP.MaxSendsPerPhone,
+			MaxSendsPerIP:    cfg.OTP.MaxSendsPerIP,
+		},
+	}, log.SugaredLogger)
+	adminSvc := service.NewAdminService(userRepo, outboxRepo, tokenSvc, auditLogger, log.SugaredLogger)
+	deletionSvc := service.NewAccountDeletionService(userRepo, outboxRepo, deletionRepo, tokenSvc, service.AccountDeletionConfig{
+		RequiredAcks:  cfg.AccountDeletion.RequiredAcks,
+		RetryInterval: cfg.AccountDeletion.RetryInterval,
+	}, log.SugaredLogger)
+	auditSvc := service.NewAuditService(auditRepo, log.SugaredLogger)
+	outboxAdminSvc := service.NewOutboxAdminService(outboxRepo, log.SugaredLogger)
+	clientCredentialsSvc := service.NewClientCredentialsService(
+		repository.NewServiceClientRepository(pg, log.SugaredLogger),
+		tokenSvc,
+		log.SugaredLogger,
+	)
 
 	// Настройка Kafka Writer
 	kafkaWriter := kafka.NewWriter(kafka.WriterConfig{
 		Brokers:      cfg.Kafka.Brokers,
 		Topic:        cfg.Kafka.Topic,
+		Balancer:     &kafka.Hash{}, // Партиция по ключу (пользователю) — события пользователя упорядочены
 		BatchSize:    cfg.Kafka.BatchSize,
+		BatchTimeout: cfg.Kafka.BatchTimeout,
 		Async:        false,
 		WriteTimeout: 10 * time.Second,
 	})
 
+	// Dead-letter топик для событий, исчерпавших попытки публикации
+	var (
+		dlqWriter *kafka.Writer
+		dlq       service.MessageWriter // Остаётся nil-интерфейсом, если топик не настроен
+	)
+	if cfg.Outbox.DeadLetterTopic != "" {
+		dlqWriter = kafka.NewWriter(kafka.WriterConfig{
+			Brokers:      cfg.Kafka.Brokers,
+			Topic:        cfg.Outbox.DeadLetterTopic,
+			Balancer:     &kafka.Hash{},
+			BatchTimeout: cfg.Kafka.BatchTimeout,
+			Async:        false,
+			WriteTimeout: 10 * time.Second,
+		})
+		dlq = dlqWriter
+	}
+
 	// Инициализация Outbox Publisher
-	outboxPublisher := service.NewOutboxPublisher(outboxRepo, kafkaWriter, log.SugaredLogger)
+	outboxPublisher := service.NewOutboxPublisher(outboxRepo, kafkaWriter, dlq, service.OutboxPublisherConfig{
+		BatchSize:    cfg.Kafka.BatchSize,
+		PollInterval: time.Duration(cfg.Kafka.PollInterval) * time.Second,
+		MaxAttempts:  cfg.Outbox.MaxAttempts,
+		BaseBackoff:  cfg.Outbox.BackoffBase,
+		MaxBackoff:   cfg.Outbox.BackoffMax,
+	}, log.SugaredLogger)
 
 	// Запуск фоновых задач
 	go func() {
@@ -95,8 +242,28 @@ func main() {
 		outboxPublisher.Start(ctx)
 	}()
 
+	// Подтверждения саги удаления аккаунта
+	deletionAckConsumer := events.NewDeletionAckConsumer(
+		cfg.Kafka.Brokers,
+		cfg.Kafka.DeletionAckTopic,
+		cfg.Kafka.GroupID,
+		deletionSvc,
+		log.SugaredLogger,
+	)
+	go func() {
+		log.Infow("Starting deletion ack consumer")
+		deletionAckConsumer.Start(ctx)
+	}()
+	go runDeletionRetrier(ctx, deletionSvc, cfg.AccountDeletion.RetryInterval, log.SugaredLogger)
+
 	// Инициализация обработчиков (Handlers)
 	authHandler := handlers.NewAuthHandler(authSvc, log.SugaredLogger)
+	adminHandler := handlers.NewAdminHandler(adminSvc, log.SugaredLogger)
+	accountHandler := handlers.NewAccountHandler(deletionSvc, log.SugaredLogger)
+	jwksHandler := handlers.NewJWKSHandler(keyRing)
+	tokenHandler := handlers.NewTokenHandler(clientCredentialsSvc, log.SugaredLogger)
+	auditHandler := handlers.NewAuditHandler(auditSvc, log.SugaredLogger)
+	outboxHandler := handlers.NewOutboxHandler(outboxAdminSvc, log.SugaredLogger)
 
 	// Настройка HTTP транспорта и Middleware
 	routerCfg := http_transport.NewRouterConfig(cfg)
@@ -104,6 +271,12 @@ func main() {
 
 	// Регистрация маршрутов
 	routes.SetupAuthRoutes(router.Echo(), authHandler, tokenSvc, log.SugaredLogger)
+	routes.SetupAccountRoutes(router.Echo(), accountHandler, tokenSvc, log.SugaredLogger)
+	routes.SetupAdminRoutes(router.Echo(), adminHandler, tokenSvc, log.SugaredLogger)
+	routes.SetupJWKSRoutes(router.Echo(), jwksHandler)
+	routes.SetupTokenRoutes(router.Echo(), tokenHandler)
+	routes.SetupAuditRoutes(router.Echo(), auditHandler, tokenSvc, log.SugaredLogger)
+	routes.SetupOutboxRoutes(router.Echo(), outboxHandler, tokenSvc, log.SugaredLogger)
 
 	// Запуск HTTP сервера в отдельной горутине
 	go runServerWithRetry(router, cfg, log.SugaredLogger)
@@ -126,15 +299,82 @@ func main() {
 	if err := kafkaWriter.Close(); err != nil {
 		log.Errorw("Failed to close Kafka writer", "error", err)
 	}
+	if dlqWriter != nil {
+		if err := dlqWriter.Close(); err != nil {
+			log.Errorw("Failed to close Kafka dead-letter writer", "error", err)
+		}
+	}
+	if err := deletionAckConsumer.Close(); err != nil {
+		log.Errorw("Failed to close Kafka consumer", "error", err)
+	}
 
 	// Остановка HTTP сервера
 	if err := router.ShuttingDown(shutdownCtx); err != nil {
 		log.Errorw("Server forced to shutdown", "error", err)
 	}
 
+	// Дописываем остаток журнала аутентификации
+	auditCancel()
+	select {
+	case <-auditLogger.Done():
+	case <-shutdownCtx.Done():
+		log.Warnw("Audit log flush timed out")
+	}
+
 	log.Infow("Auth service stopped")
 }
 
+// loadKeyRing — загружает ключи JWT; при пустом каталоге может создать первый ключ
+func loadKeyRing(cfg *config.Config, log *zap.SugaredLogger) (*utils.KeyRing, error) {
+	keyRing, err := utils.LoadKeyRing(cfg.JWT.KeysDir, log)
+	if err == nil || !errors.Is(err, utils.ErrNoSigningKeys) || !cfg.JWT.AutoGenerateKey {
+		return keyRing, err
+	}
+
+	log.Warnw("No JWT signing keys found, generating a new one", "dir", cfg.JWT.KeysDir, "alg", cfg.JWT.Algorithm)
+	if _, err := utils.GenerateSigningKey(cfg.JWT.KeysDir, cfg.JWT.Algorithm); err != nil {
+		return nil, err
+	}
+	return utils.LoadKeyRing(cfg.JWT.KeysDir, log)
+}
+
+// runKeyRingReloader — периодически перечитывает ключи, чтобы подхватить ротацию
+func runKeyRingReloader(ctx context.Context, keyRing *utils.KeyRing, interval time.Duration, log *zap.SugaredLogger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := keyRing.Reload(); err != nil {
+				log.Errorw("Failed to reload JWT key ring", "error", err)
+			}
+		}
+	}
+}
+
+// runDeletionRetrier — повторно отправляет UserDeleted по сагам удаления, зависшим без подтверждений
+func runDeletionRetrier(ctx context.Context, svc service.AccountDeletionService, interval time.Duration, log *zap.SugaredLogger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := svc.RetryPending(ctx)
+			if err != nil {
+				log.Errorw("Failed to retry pending account deletions", "error", err)
+				continue
+			}
+			if n > 0 {
+				log.Infow("Re-emitted UserDeleted for pending account deletions", "count", n)
+			}
+		}
+	}
+}
+
 func runServerWithRetry(router *http_transport.Router, cfg *config.Config, log *zap.SugaredLogger) {
 	maxRetries := cfg.HTTPServer.MaxRetries
 	retryDelay := time.Duration(cfg.HTTPServer.RetryDelay) * time.Second
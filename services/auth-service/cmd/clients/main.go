@@ -0,0 +1,162 @@
+package main
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"auth-service/pkg/db/postgres"
+	"auth-service/pkg/logger"
+	"context"
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+// Управление сервисными клиентами (client-credentials):
+//
+//	clients -command create -id event-service -name "Event service" -scopes profiles:read -audiences profile-service
+//	clients -command list
+//	clients -command rotate -id event-service    — новый секрет, старый перестаёт работать
+//	clients -command disable -id event-service   — запрет выдачи новых токенов
+//	clients -command enable -id event-service
+//
+// Секрет печатается один раз при create/rotate; в БД хранится только его хэш.
+func main() {
+	var (
+		pgCfg  config.PostgresConfig
+		logCfg config.LoggerConfig
+	)
+
+	// Читаем только нужные секции: утилите не нужны Redis/Kafka/JWT
+	if err := cleanenv.ReadEnv(&pgCfg); err != nil {
+		panic(err)
+	}
+	if err := cleanenv.ReadEnv(&logCfg); err != nil {
+		panic(err)
+	}
+
+	log, err := logger.New(logCfg)
+	if err != nil {
+		panic(err)
+	}
+	defer log.Sync()
+
+	var (
+		command   string
+		clientID  string
+		name      string
+		scopes    string
+		audiences string
+	)
+
+	flag.StringVar(&command, "command", "list", "Client command: create | list | rotate | disable | enable")
+	flag.StringVar(&clientID, "id", "", "Client ID")
+	flag.StringVar(&name, "name", "", "Human-readable client name (create)")
+	flag.StringVar(&scopes, "scopes", "", "Comma-separated scopes (create)")
+	flag.StringVar(&audiences, "audiences", "", "Comma-separated services the client may get tokens for (create)")
+	flag.Parse()
+
+	if command != "list" && clientID == "" {
+		log.Fatalf("-id is required for %s", command)
+	}
+
+	pg, err := postgres.NewPostgres(&pgCfg, log.SugaredLogger)
+	if err != nil {
+		log.Fatalf("postgres connection failed: %v", err)
+	}
+	defer pg.Close()
+
+	repo := repository.NewServiceClientRepository(pg, log.SugaredLogger)
+	ctx := context.Background()
+
+	switch command {
+	case "create":
+		secret, hash, err := newSecret()
+		if err != nil {
+			log.Fatalf("failed to generate secret: %v", err)
+		}
+		if name == "" {
+			name = clientID
+		}
+		now := time.Now()
+		client := &models.ServiceClient{
+			ClientID:   clientID,
+			Name:       name,
+			SecretHash: hash,
+			Scopes:     splitList(scopes),
+			Audiences:  splitList(audiences),
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := repo.Create(ctx, client); err != nil {
+			log.Fatalf("failed to create client: %v", err)
+		}
+		log.Infow("service client created",
+			"client_id", clientID,
+			"scopes", client.Scopes,
+			"audiences", client.Audiences,
+			"client_secret", secret,
+		)
+
+	case "list":
+		clients, err := repo.List(ctx)
+		if err != nil {
+			log.Fatalf("failed to list clients: %v", err)
+		}
+		for _, c := range clients {
+			log.Infow("service client",
+				"client_id", c.ClientID,
+				"name", c.Name,
+				"scopes", c.Scopes,
+				"audiences", c.Audiences,
+				"disabled", c.Disabled,
+			)
+		}
+
+	case "rotate":
+		secret, hash, err := newSecret()
+		if err != nil {
+			log.Fatalf("failed to generate secret: %v", err)
+		}
+		if err := repo.UpdateSecret(ctx, clientID, hash); err != nil {
+			log.Fatalf("failed to rotate secret: %v", err)
+		}
+		log.Infow("client secret rotated", "client_id", clientID, "client_secret", secret)
+
+	case "disable", "enable":
+		if err := repo.SetDisabled(ctx, clientID, command == "disable"); err != nil {
+			log.Fatalf("failed to %s client: %v", command, err)
+		}
+		log.Infow("service client updated", "client_id", clientID, "disabled", command == "disable")
+
+	default:
+		log.Fatalf("unknown command: %s", command)
+	}
+}
+
+// newSecret — случайный секрет клиента и его argon2id хэш
+func newSecret() (string, string, error) {
+	secret, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	hash, err := utils.HashPassword(secret)
+	if err != nil {
+		return "", "", err
+	}
+	return secret, hash, nil
+}
+
+// splitList — "a:read, b:write" → [a:read b:write]
+func splitList(raw string) []string {
+	items := []string{}
+	for _, s := range strings.Split(raw, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			items = append(items, s)
+		}
+	}
+	return items
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/utils"
+	"auth-service/pkg/logger"
+	"flag"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+// Управление ключами подписи JWT:
+//
+//	keys -command rotate            — создать новый ключ: он публикуется в JWKS, подпись не меняется
+//	keys -command activate [-kid X] — подписывать новым ключом (по умолчанию самым свежим)
+//	keys -command list              — показать ключи и активный kid
+//	keys -command prune -keep 2     — удалить старые неактивные ключи
+//
+// Работающие экземпляры auth-service подхватывают изменения через JWT_KEYS_RELOAD_INTERVAL,
+// поэтому activate отказывается переключать ключ, добавленный раньше, чем интервал назад.
+// Предыдущие ключи остаются в JWKS и валидны для проверки, пока не удалены prune.
+func main() {
+	var (
+		jwtCfg config.JWT
+		logCfg config.LoggerConfig
+	)
+
+	// Читаем только нужные секции: утилите не нужны Postgres/Redis/Kafka
+	if err := cleanenv.ReadEnv(&jwtCfg); err != nil {
+		panic(err)
+	}
+	if err := cleanenv.ReadEnv(&logCfg); err != nil {
+		panic(err)
+	}
+
+	log, err := logger.New(logCfg)
+	if err != nil {
+		panic(err)
+	}
+	defer log.Sync()
+
+	var (
+		command string
+		dir     string
+		alg     string
+		kid     string
+		minAge  time.Duration
+		keep    int
+	)
+
+	flag.StringVar(&command, "command", "list", "Key command: rotate | activate | list | prune")
+	flag.StringVar(&dir, "dir", jwtCfg.KeysDir, "Path to JWT keys directory")
+	flag.StringVar(&alg, "alg", jwtCfg.Algorithm, "Algorithm for new keys: RS256 | EdDSA")
+	flag.StringVar(&kid, "kid", "", "Key to activate (default: newest)")
+	flag.DurationVar(&minAge, "min-age", jwtCfg.KeysReloadInterval, "Minimum key age before activation")
+	flag.IntVar(&keep, "keep", 2, "Number of newest keys to keep on prune")
+	flag.Parse()
+
+	switch command {
+	case "rotate":
+		key, err := utils.GenerateSigningKey(dir, alg)
+		if err != nil {
+			log.Fatalf("key rotation failed: %v", err)
+		}
+		log.Infow("new signing key is published, activate it after the reload interval",
+			"kid", key.KID, "alg", key.Algorithm, "dir", dir, "min_age", minAge)
+
+	case "activate":
+		activated, err := utils.ActivateSigningKey(dir, kid, minAge)
+		if err != nil {
+			log.Fatalf("key activation failed: %v", err)
+		}
+		log.Infow("signing key is active", "kid", activated, "dir", dir)
+
+	case "list":
+		kids, err := utils.ListKeyIDs(dir)
+		if err != nil {
+			log.Fatalf("failed to list keys: %v", err)
+		}
+		active, err := utils.ActiveKeyID(dir)
+		if err != nil {
+			log.Fatalf("failed to read active key: %v", err)
+		}
+		for _, kid := range kids {
+			log.Infow("signing key", "kid", kid, "active", kid == active)
+		}
+
+	case "prune":
+		if keep < 1 {
+			log.Fatal("prune requires -keep >= 1")
+		}
+		removed, err := utils.PruneSigningKeys(dir, keep)
+		if err != nil {
+			log.Fatalf("prune failed: %v", err)
+		}
+		log.Infow("old signing keys removed", "removed", removed)
+
+	default:
+		log.Fatalf("unknown command: %s", command)
+	}
+}
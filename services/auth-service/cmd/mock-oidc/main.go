@@ -0,0 +1,208 @@
+package main
+
+import (
+	"auth-service/internal/config"
+	"auth-service/pkg/logger"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+// Локальный OIDC провайдер для разработки и тестов входа через OAuth (провайдер "mock").
+// /authorize сразу "логинит" тестового пользователя и возвращает браузер на redirect_uri с кодом.
+// Email можно переопределить параметром login_hint: /api/v1/auth/oauth/mock/start → ...&login_hint=a@b.c
+//
+//	mock-oidc -addr :9000 -email mock.user@huddle.local
+func main() {
+	var logCfg config.LoggerConfig
+	if err := cleanenv.ReadEnv(&logCfg); err != nil {
+		panic(err)
+	}
+
+	log, err := logger.New(logCfg)
+	if err != nil {
+		panic(err)
+	}
+	defer log.Sync()
+
+	var (
+		addr       string
+		email      string
+		givenName  string
+		familyName string
+	)
+	flag.StringVar(&addr, "addr", ":9000", "Listen address")
+	flag.StringVar(&email, "email", "mock.user@huddle.local", "Default user email")
+	flag.StringVar(&givenName, "given-name", "Mock", "User first name")
+	flag.StringVar(&familyName, "family-name", "User", "User last name")
+	flag.Parse()
+
+	p := &provider{
+		defaultUser: mockUser{GivenName: givenName, FamilyName: familyName, Email: email},
+		codes:       make(map[string]authCode),
+		tokens:      make(map[string]mockUser),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+	mux.HandleFunc("/userinfo", p.userinfo)
+
+	log.Infow("Mock OIDC provider started", "addr", addr, "default_email", email)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Fatalf("mock oidc server failed: %v", err)
+	}
+}
+
+type mockUser struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+}
+
+type authCode struct {
+	user          mockUser
+	redirectURI   string
+	codeChallenge string
+	expiresAt     time.Time
+}
+
+type provider struct {
+	defaultUser mockUser
+
+	mu     sync.Mutex
+	codes  map[string]authCode
+	tokens map[string]mockUser
+}
+
+func (p *provider) discovery(w http.ResponseWriter, r *http.Request) {
+	base := "http://" + r.Host
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                           base,
+		"authorization_endpoint":           base + "/authorize",
+		"token_endpoint":                   base + "/token",
+		"userinfo_endpoint":                base + "/userinfo",
+		"response_types_supported":         []string{"code"},
+		"code_challenge_methods_supported": []string{"S256"},
+	})
+}
+
+func (p *provider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI := q.Get("redirect_uri")
+	if redirectURI == "" || q.Get("response_type") != "code" {
+		http.Error(w, "redirect_uri and response_type=code are required", http.StatusBadRequest)
+		return
+	}
+
+	user := p.defaultUser
+	if hint := q.Get("login_hint"); hint != "" {
+		user.Email = hint
+	}
+	// sub стабилен для email: повторный вход попадает в ту же identity
+	sum := sha256.Sum256([]byte(strings.ToLower(user.Email)))
+	user.Subject = hex.EncodeToString(sum[:8])
+	user.EmailVerified = true
+
+	code := randomToken()
+	p.mu.Lock()
+	p.codes[code] = authCode{
+		user:          user,
+		redirectURI:   redirectURI,
+		codeChallenge: q.Get("code_challenge"),
+		expiresAt:     time.Now().Add(time.Minute),
+	}
+	p.mu.Unlock()
+
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	params := target.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	target.RawQuery = params.Encode()
+
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (p *provider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	p.mu.Lock()
+	code, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mu.Unlock()
+
+	if !ok || time.Now().After(code.expiresAt) || code.redirectURI != r.PostForm.Get("redirect_uri") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	if code.codeChallenge != "" {
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != code.codeChallenge {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "pkce verification failed"})
+			return
+		}
+	}
+
+	accessToken := randomToken()
+	p.mu.Lock()
+	p.tokens[accessToken] = code.user
+	p.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+func (p *provider) userinfo(w http.ResponseWriter, r *http.Request) {
+	accessToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	p.mu.Lock()
+	user, ok := p.tokens[accessToken]
+	p.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_token"})
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func randomToken() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -12,6 +12,8 @@ type HTTPServerConfig struct {
 	Port       string `env:"HTTP_SERVER_PORT" env-default:"8080" validate:"required,numeric"`
 	MaxRetries int    `env:"HTTP_SERVER_MAX_RETRIES" env-default:"5" validate:"gte=1"`
 	RetryDelay int    `env:"HTTP_SERVER_RETRY_DELAY" env-default:"5" validate:"gte=1"`
+	// Сети прокси (nginx), которым доверяется заголовок X-Real-IP; от остальных он игнорируется
+	TrustedProxies []string `env:"HTTP_TRUSTED_PROXIES" env-default:"172.16.0.0/12,192.168.0.0/16" env-separator:"," validate:"dive,cidr"`
 }
 
 type PostgresConfig struct {
@@ -29,9 +31,17 @@ type PostgresConfig struct {
 }
 
 type JWT struct {
-	Secret        string        `env:"JWT_SECRET" validate:"required"`
-	TokenExpiry   time.Duration `env:"JWT_TOKEN_EXPIRY" env-default:"1h"`
-	RefreshExpiry time.Duration `env:"JWT_REFRESH_EXPIRY" env-default:"24h"`
+	KeysDir            string        `env:"JWT_KEYS_DIR" env-default:"keys" validate:"required"`
+	Algorithm          string        `env:"JWT_ALGORITHM" env-default:"EdDSA" validate:"oneof=RS256 EdDSA"`
+	AutoGenerateKey    bool          `env:"JWT_AUTO_GENERATE_KEY" env-default:"true"`
+	KeysReloadInterval time.Duration `env:"JWT_KEYS_RELOAD_INTERVAL" env-default:"1m"`
+	TokenExpiry        time.Duration `env:"JWT_TOKEN_EXPIRY" env-default:"1h"`
+	RefreshExpiry      time.Duration `env:"JWT_REFRESH_EXPIRY" env-default:"24h"`
+	ServiceTokenExpiry time.Duration `env:"JWT_SERVICE_TOKEN_EXPIRY" env-default:"15m"`
+	RevocationCacheTTL time.Duration `env:"JWT_REVOCATION_CACHE_TTL" env-default:"5s"`
+	RevocationFailOpen bool          `env:"JWT_REVOCATION_FAIL_OPEN" env-default:"false"`
+	AccessCacheTTL     time.Duration `env:"JWT_ACCESS_CACHE_TTL" env-default:"30s"`
+	UserStatusCacheTTL time.Duration `env:"JWT_USER_STATUS_CACHE_TTL" env-default:"10s"`
 }
 
 type LoggerConfig struct {
@@ -55,23 +65,147 @@ type RedisConfig struct {
 }
 
 type KafkaConfig struct {
-	Brokers      []string `env:"KAFKA_BROKERS" env-default:"localhost:9092" env-separator:"," validate:"required,dive,hostname_port"`
-	Topic        string   `env:"KAFKA_TOPIC" env-default:"user-events" validate:"required"`
-	GroupID      string   `env:"KAFKA_GROUP_ID" env-default:"auth-service" validate:"required"`
-	MaxAttempts  int      `env:"KAFKA_MAX_ATTEMPTS" env-default:"3" validate:"gte=1"`
-	RetryDelay   int      `env:"KAFKA_RETRY_DELAY" env-default:"2" validate:"gte=1"`
-	BatchSize    int      `env:"KAFKA_BATCH_SIZE" env-default:"100" validate:"gte=1,lte=1000"`
-	PollInterval int      `env:"KAFKA_POLL_INTERVAL" env-default:"5" validate:"gte=1"`
+	Brokers      []string      `env:"KAFKA_BROKERS" env-default:"localhost:9092" env-separator:"," validate:"required,dive,hostname_port"`
+	Topic        string        `env:"KAFKA_TOPIC" env-default:"user-events" validate:"required"`
+	GroupID      string        `env:"KAFKA_GROUP_ID" env-default:"auth-service" validate:"required"`
+	MaxAttempts  int           `env:"KAFKA_MAX_ATTEMPTS" env-default:"3" validate:"gte=1"`
+	RetryDelay   int           `env:"KAFKA_RETRY_DELAY" env-default:"2" validate:"gte=1"`
+	BatchSize    int           `env:"KAFKA_BATCH_SIZE" env-default:"100" validate:"gte=1,lte=1000"`
+	BatchTimeout time.Duration `env:"KAFKA_BATCH_TIMEOUT" env-default:"5ms" validate:"gt=0"` // Ожидание добора пачки; по умолчанию kafka-go ждёт 1с на каждую запись
+	PollInterval int           `env:"KAFKA_POLL_INTERVAL" env-default:"5" validate:"gte=1"`  // Секунды между страховочными обходами outbox; новые события публикуются по NOTIFY
+	// Топик подтверждений удаления данных пользователя от других сервисов
+	DeletionAckTopic string `env:"KAFKA_DELETION_ACK_TOPIC" env-default:"user-deletion-acks" validate:"required"`
+}
+
+// AccountDeletionConfig — сага удаления аккаунта
+type AccountDeletionConfig struct {
+	// Пользователь удаляется окончательно, когда все эти сервисы подтвердили удаление своих данных
+	RequiredAcks  []string      `env:"ACCOUNT_DELETION_REQUIRED_ACKS" env-default:"profile-service,event-service" env-separator:"," validate:"required,min=1"`
+	RetryInterval time.Duration `env:"ACCOUNT_DELETION_RETRY_INTERVAL" env-default:"1h" validate:"gt=0"`
+}
+
+type MailerConfig struct {
+	Driver       string `env:"MAIL_DRIVER" env-default:"file" validate:"oneof=smtp file"`
+	From         string `env:"MAIL_FROM" env-default:"Huddle <no-reply@huddle.local>" validate:"required"`
+	SMTPHost     string `env:"MAIL_SMTP_HOST" validate:"required_if=Driver smtp"`
+	SMTPPort     string `env:"MAIL_SMTP_PORT" env-default:"587" validate:"numeric"`
+	SMTPUser     string `env:"MAIL_SMTP_USER"`
+	SMTPPassword string `env:"MAIL_SMTP_PASSWORD"`
+	FilePath     string `env:"MAIL_FILE_PATH" env-default:"/tmp/huddle-mail.log"`
+	LinkBaseURL  string `env:"MAIL_LINK_BASE_URL" env-default:"http://localhost" validate:"required,url"`
+}
+
+type SMSConfig struct {
+	Driver   string `env:"SMS_DRIVER" env-default:"file" validate:"oneof=log file"`
+	FilePath string `env:"SMS_FILE_PATH" env-default:"/tmp/huddle-sms.log" validate:"required_if=Driver file"`
+}
+
+type OTPConfig struct {
+	CodeTTL          time.Duration `env:"OTP_CODE_TTL" env-default:"5m" validate:"gt=0"`
+	CodeLength       int           `env:"OTP_CODE_LENGTH" env-default:"6" validate:"gte=4,lte=10"`
+	MaxAttempts      int           `env:"OTP_MAX_ATTEMPTS" env-default:"5" validate:"gte=1"`
+	Window           time.Duration `env:"OTP_WINDOW" env-default:"1h" validate:"gt=0"`
+	MaxSendsPerPhone int           `env:"OTP_MAX_PER_PHONE" env-default:"5" validate:"gte=1"`
+	MaxSendsPerIP    int           `env:"OTP_MAX_PER_IP" env-default:"20" validate:"gte=1"`
+}
+
+type EmailVerificationConfig struct {
+	TokenTTL       time.Duration `env:"EMAIL_VERIFY_TOKEN_TTL" env-default:"24h"`
+	ResendInterval time.Duration `env:"EMAIL_VERIFY_RESEND_INTERVAL" env-default:"1m"`
+}
+
+type PasswordResetConfig struct {
+	TokenTTL time.Duration `env:"PASSWORD_RESET_TOKEN_TTL" env-default:"1h"`
+}
+
+type MagicLinkConfig struct {
+	TokenTTL            time.Duration `env:"MAGIC_LINK_TOKEN_TTL" env-default:"15m" validate:"gt=0"`
+	Window              time.Duration `env:"MAGIC_LINK_WINDOW" env-default:"1h" validate:"gt=0"`
+	MaxRequestsPerEmail int           `env:"MAGIC_LINK_MAX_PER_EMAIL" env-default:"3" validate:"gte=1"`
+	MaxRequestsPerIP    int           `env:"MAGIC_LINK_MAX_PER_IP" env-default:"20" validate:"gte=1"`
+}
+
+type EmailChangeConfig struct {
+	TokenTTL time.Duration `env:"EMAIL_CHANGE_TOKEN_TTL" env-default:"1h" validate:"gt=0"`
+}
+
+// OutboxConfig — повторы публикации событий outbox в Kafka
+type OutboxConfig struct {
+	MaxAttempts int           `env:"OUTBOX_MAX_ATTEMPTS" env-default:"10" validate:"gte=1"`
+	BackoffBase time.Duration `env:"OUTBOX_BACKOFF_BASE" env-default:"5s" validate:"gt=0"`
+	BackoffMax  time.Duration `env:"OUTBOX_BACKOFF_MAX" env-default:"1h" validate:"gtefield=BackoffBase"`
+	// Топик для событий, исчерпавших попытки; пустой — не дублировать в Kafka
+	DeadLetterTopic string `env:"OUTBOX_DLQ_TOPIC"`
+}
+
+type AuditConfig struct {
+	BufferSize    int           `env:"AUDIT_BUFFER_SIZE" env-default:"1000" validate:"gte=1"`
+	BatchSize     int           `env:"AUDIT_BATCH_SIZE" env-default:"100" validate:"gte=1"`
+	FlushInterval time.Duration `env:"AUDIT_FLUSH_INTERVAL" env-default:"2s" validate:"gt=0"`
+}
+
+type PasswordPolicyConfig struct {
+	MinLength            int    `env:"PASSWORD_MIN_LENGTH" env-default:"8" validate:"gte=1"`
+	MaxLength            int    `env:"PASSWORD_MAX_LENGTH" env-default:"128" validate:"gtefield=MinLength,lte=256"`
+	ForbidEmailLocalPart bool   `env:"PASSWORD_FORBID_EMAIL_LOCAL_PART" env-default:"true"`
+	CommonListPath       string `env:"PASSWORD_COMMON_LIST_PATH"` // Дополнительный список (встроенный используется всегда)
+}
+
+type LoginProtectionConfig struct {
+	Window              time.Duration `env:"LOGIN_FAILURE_WINDOW" env-default:"15m"`
+	MaxFailuresPerEmail int           `env:"LOGIN_MAX_FAILURES_PER_EMAIL" env-default:"5" validate:"gte=1"`
+	MaxFailuresPerIP    int           `env:"LOGIN_MAX_FAILURES_PER_IP" env-default:"20" validate:"gte=1"`
+	BackoffBase         time.Duration `env:"LOGIN_BACKOFF_BASE" env-default:"1s"`
+	BackoffMax          time.Duration `env:"LOGIN_BACKOFF_MAX" env-default:"1m"`
+	LockoutDuration     time.Duration `env:"LOGIN_LOCKOUT_DURATION" env-default:"15m"`
+}
+
+type MFAConfig struct {
+	EncryptionKey string        `env:"MFA_ENCRYPTION_KEY" validate:"required,base64"` // 32 байта в base64: openssl rand -base64 32
+	Issuer        string        `env:"MFA_ISSUER" env-default:"Huddle" validate:"required"`
+	ChallengeTTL  time.Duration `env:"MFA_CHALLENGE_TTL" env-default:"5m"`
+	MaxAttempts   int           `env:"MFA_MAX_ATTEMPTS" env-default:"5" validate:"gte=1"`
+	RecoveryCodes int           `env:"MFA_RECOVERY_CODES" env-default:"10" validate:"gte=1,lte=20"`
+}
+
+type OAuthClientConfig struct {
+	ClientID     string `env:"CLIENT_ID"`
+	ClientSecret string `env:"CLIENT_SECRET"`
+}
+
+type OAuthConfig struct {
+	RedirectBaseURL string            `env:"OAUTH_REDIRECT_BASE_URL" env-default:"http://localhost" validate:"required,url"`
+	StateTTL        time.Duration     `env:"OAUTH_STATE_TTL" env-default:"10m"`
+	Google          OAuthClientConfig `env-prefix:"OAUTH_GOOGLE_"`
+	Yandex          OAuthClientConfig `env-prefix:"OAUTH_YANDEX_"`
+	VK              OAuthClientConfig `env-prefix:"OAUTH_VK_"`
+	MockEnabled     bool              `env:"OAUTH_MOCK_ENABLED" env-default:"false"`
+	MockPublicURL   string            `env:"OAUTH_MOCK_PUBLIC_URL" env-default:"http://localhost:9000"`
+	MockInternalURL string            `env:"OAUTH_MOCK_INTERNAL_URL"`
 }
 
 type Config struct {
-	Env        string `env:"ENV" env-default:"development" validate:"oneof=development production"`
-	JWT        JWT
-	HTTPServer HTTPServerConfig
-	Postgres   PostgresConfig
-	Redis      RedisConfig
-	Kafka      KafkaConfig
-	Logger     LoggerConfig
+	Env               string `env:"ENV" env-default:"development" validate:"oneof=development production"`
+	JWT               JWT
+	HTTPServer        HTTPServerConfig
+	Postgres          PostgresConfig
+	Redis             RedisConfig
+	Kafka             KafkaConfig
+	Outbox            OutboxConfig
+	Logger            LoggerConfig
+	Mailer            MailerConfig
+	SMS               SMSConfig
+	EmailVerification EmailVerificationConfig
+	PasswordReset     PasswordResetConfig
+	EmailChange       EmailChangeConfig
+	PasswordPolicy    PasswordPolicyConfig
+	MagicLink         MagicLinkConfig
+	OTP               OTPConfig
+	LoginProtection   LoginProtectionConfig
+	MFA               MFAConfig
+	OAuth             OAuthConfig
+	AccountDeletion   AccountDeletionConfig
+	Audit             AuditConfig
 }
 
 func New() (*Config, error) {
@@ -2,8 +2,8 @@ package middleware
 
 import (
 	"auth-service/internal/utils"
+	"auth-service/pkg/apperror"
 	"context"
-	"net/http"
 	"strings"
 
 	"github.com/labstack/echo/v4"
@@ -18,22 +18,22 @@ func AuthMiddleware(tokenSvc utils.TokenService, logger *zap.SugaredLogger) echo
 			authHeader := c.Request().Header.Get("Authorization")
 			if authHeader == "" {
 				logger.Debug("Missing Authorization header")
-				return c.JSON(http.StatusUnauthorized, echo.Map{"error": "missing authorization header"})
+				return apperror.Unauthorized("missing authorization header")
 			}
 
 			parts := strings.SplitN(authHeader, " ", 2)
 			if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
 				logger.Debugw("Invalid Authorization header format", "header", authHeader)
-				return c.JSON(http.StatusUnauthorized, echo.Map{"error": "invalid authorization header format"})
+				return apperror.Unauthorized("invalid authorization header format")
 			}
 
 			tokenStr := parts[1]
 
 			// 2. Парсим access токен
-			claims, err := tokenSvc.ParseAccess(tokenStr)
+			claims, err := tokenSvc.ParseAccess(c.Request().Context(), tokenStr)
 			if err != nil {
 				logger.Infow("Invalid access token", "error", err, "token_prefix", tokenStr[:10]+"...")
-				return c.JSON(http.StatusUnauthorized, echo.Map{"error": "invalid or expired token"})
+				return apperror.Unauthorized("invalid or expired token")
 			}
 
 			// 3. Логируем успешную аутентификацию
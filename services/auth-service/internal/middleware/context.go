@@ -13,7 +13,6 @@ type contextKey string
 const (
 	loggerKey     contextKey = "logger"
 	requestIDKey  contextKey = "request_id"
-	userKey       contextKey = "user"
 	UserClaimsKey contextKey = "user_claims"
 )
 
@@ -33,8 +32,9 @@ func GetRequestIDFromCtx(ctx context.Context) string {
 	return ""
 }
 
+// GetUserClaims - получение claims access токена, положенных AuthMiddleware
 func GetUserClaims(ctx context.Context) (*models.AccessTokenClaims, bool) {
-	if claims, ok := ctx.Value(userKey).(*models.AccessTokenClaims); ok {
+	if claims, ok := ctx.Value(UserClaimsKey).(*models.AccessTokenClaims); ok {
 		return claims, true
 	}
 	return nil, false
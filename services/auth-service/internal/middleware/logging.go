@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"auth-service/internal/models"
+	"auth-service/internal/utils"
 	"context"
 	"time"
 
@@ -28,6 +30,24 @@ func RequestIDMiddleware() echo.MiddlewareFunc {
 	}
 }
 
+// ClientInfoMiddleware — IP, User-Agent, request_id и traceparent клиента в контексте запроса
+// (для журнала аудита и outbox в сервисном слое). Ставится после RequestIDMiddleware.
+func ClientInfoMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := utils.WithClientInfo(c.Request().Context(), models.ClientInfo{
+				IP:          c.RealIP(),
+				UserAgent:   c.Request().UserAgent(),
+				RequestID:   GetRequestIDFromCtx(c.Request().Context()),
+				Traceparent: Traceparent(c.Request()),
+			})
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
 func LoggingMiddleware(logger *zap.SugaredLogger) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -46,7 +66,11 @@ func LoggingMiddleware(logger *zap.SugaredLogger) echo.MiddlewareFunc {
 
 			requestLogger.Infow("Request started")
 
+			// Ошибку сразу отдаём в ErrorHandler, чтобы в лог попал итоговый статус
 			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
 
 			duration := time.Since(start)
 			status := c.Response().Status
@@ -59,27 +83,27 @@ func LoggingMiddleware(logger *zap.SugaredLogger) echo.MiddlewareFunc {
 			}
 
 			if err != nil {
-				requestLogger.Errorw("Request failed", append(logFields, "error", err)...)
-			} else {
-				level := "info"
-				if status >= 500 {
-					level = "error"
-				} else if status >= 400 {
-					level = "warn"
-				}
-
-				// Динамический уровень логирования в зависимости от статуса
-				switch level {
-				case "error":
-					requestLogger.Errorw("Request completed", logFields...)
-				case "warn":
-					requestLogger.Warnw("Request completed", logFields...)
-				default:
-					requestLogger.Infow("Request completed", logFields...)
-				}
+				logFields = append(logFields, "error", err)
+			}
+
+			level := "info"
+			if status >= 500 {
+				level = "error"
+			} else if status >= 400 {
+				level = "warn"
+			}
+
+			// Динамический уровень логирования в зависимости от статуса
+			switch level {
+			case "error":
+				requestLogger.Errorw("Request completed", logFields...)
+			case "warn":
+				requestLogger.Warnw("Request completed", logFields...)
+			default:
+				requestLogger.Infow("Request completed", logFields...)
 			}
 
-			return err
+			return nil
 		}
 	}
 }
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"auth-service/pkg/apperror"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireRole — пропускает только пользователей с одной из ролей.
+// Ставится после AuthMiddleware: роль берётся из claims access токена.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, ok := GetUserClaims(c.Request().Context())
+			if !ok {
+				return apperror.Unauthorized("unauthorized")
+			}
+
+			if !slices.Contains(roles, claims.Role) {
+				GetLoggerFromCtx(c.Request().Context()).Warnw("Access denied: insufficient role",
+					"user_id", claims.Sub,
+					"role", claims.Role,
+					"required", roles,
+					"path", c.Path(),
+				)
+				return apperror.Forbidden("forbidden")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RequirePermission — пропускает только токены, в которых есть все перечисленные права
+func RequirePermission(permissions ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, ok := GetUserClaims(c.Request().Context())
+			if !ok {
+				return apperror.Unauthorized("unauthorized")
+			}
+
+			for _, permission := range permissions {
+				if !slices.Contains(claims.Permissions, permission) {
+					GetLoggerFromCtx(c.Request().Context()).Warnw("Access denied: missing permission",
+						"user_id", claims.Sub,
+						"role", claims.Role,
+						"required", permission,
+						"path", c.Path(),
+					)
+					return apperror.Forbidden("forbidden")
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
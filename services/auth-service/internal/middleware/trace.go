@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// traceparentHeader — заголовок W3C Trace Context
+const traceparentHeader = "traceparent"
+
+// traceparentPattern — version-traceid-parentid-flags; у будущих версий после flags могут быть поля
+var traceparentPattern = regexp.MustCompile(`^[0-9a-f]{2}-[0-9a-f]{32}-[0-9a-f]{16}-[0-9a-f]{2}(-.*)?$`)
+
+// Traceparent — traceparent входящего запроса или "", если заголовка нет или он некорректен.
+// Значение уходит в outbox и заголовки Kafka как есть, поэтому мусор из запроса отбрасывается.
+func Traceparent(r *http.Request) string {
+	value := strings.TrimSpace(r.Header.Get(traceparentHeader))
+	if !traceparentPattern.MatchString(value) {
+		return ""
+	}
+
+	version, traceID, parentID := value[0:2], value[3:35], value[36:52]
+	if version == "ff" || (version == "00" && len(value) != 55) {
+		return ""
+	}
+	if traceID == strings.Repeat("0", 32) || parentID == strings.Repeat("0", 16) {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTraceparent(t *testing.T) {
+	const valid = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "valid", header: valid, want: valid},
+		{name: "surrounding spaces", header: "  " + valid + " ", want: valid},
+		{name: "missing", header: "", want: ""},
+		{name: "future version with extra fields", header: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", want: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"},
+		{name: "version 00 with extra fields", header: valid + "-extra", want: ""},
+		{name: "forbidden version", header: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", want: ""},
+		{name: "uppercase hex", header: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-01", want: ""},
+		{name: "zero trace id", header: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", want: ""},
+		{name: "zero parent id", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", want: ""},
+		{name: "short trace id", header: "00-4bf92f3577b34da6-00f067aa0ba902b7-01", want: ""},
+		{name: "garbage", header: "not-a-trace", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set("traceparent", tt.header)
+			}
+			if got := Traceparent(req); got != tt.want {
+				t.Errorf("Traceparent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
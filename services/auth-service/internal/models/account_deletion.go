@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountDeletion — незавершённая сага удаления аккаунта (auth.account_deletions)
+type AccountDeletion struct {
+	UserID        uuid.UUID
+	RequestedAt   time.Time
+	LastEmittedAt time.Time
+}
@@ -0,0 +1,42 @@
+package models
+
+// Статусы пользователя (auth.users.status)
+const (
+	UserStatusActive  = "active"
+	UserStatusBlocked = "blocked"
+	UserStatusBanned  = "banned"
+	UserStatusDeleted = "deleted" // Удалён самим пользователем, ждёт подтверждения сервисов
+)
+
+// Роли пользователя (auth.users.role)
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// UserFilter — фильтр списка пользователей в админке
+type UserFilter struct {
+	Query  string `query:"q" validate:"omitempty,max=255"` // Поиск по подстроке email
+	Status string `query:"status" validate:"omitempty,oneof=active blocked banned deleted"`
+	Role   string `query:"role" validate:"omitempty,oneof=user admin"`
+	Limit  int    `query:"limit" validate:"omitempty,gte=1,lte=100"`
+	Offset int    `query:"offset" validate:"omitempty,gte=0"`
+}
+
+// UserList — страница списка пользователей
+type UserList struct {
+	Users  []*User `json:"users"`
+	Total  int     `json:"total"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+}
+
+// ChangeStatusRequest — блокировка/бан/разблокировка
+type ChangeStatusRequest struct {
+	Reason string `json:"reason" validate:"omitempty,max=500"`
+}
+
+// ChangeRoleRequest — смена роли
+type ChangeRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=user admin"`
+}
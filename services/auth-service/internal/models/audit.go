@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы событий журнала аутентификации (auth.audit_log.event_type)
+const (
+	AuditRegister        = "register"
+	AuditLoginSuccess    = "login_success"
+	AuditLoginFailure    = "login_failure"
+	AuditTokenRefresh    = "token_refresh"
+	AuditLogout          = "logout"
+	AuditSessionRevoked  = "session_revoked"
+	AuditSessionsRevoked = "sessions_revoked_all"
+	AuditPasswordChanged = "password_changed"
+	AuditPasswordReset   = "password_reset"
+	AuditEmailChanged    = "email_changed"
+	AuditStatusChanged   = "status_changed"
+	AuditRoleChanged     = "role_changed"
+	AuditMFAEnabled      = "mfa_enabled"
+	AuditPhoneVerified   = "phone_verified"
+)
+
+// AuditEntry — запись журнала аутентификации
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	UserID    *uuid.UUID      `json:"userId,omitempty"`
+	Email     string          `json:"email,omitempty"`
+	EventType string          `json:"eventType"`
+	Success   bool            `json:"success"`
+	IP        string          `json:"ip,omitempty"`
+	UserAgent string          `json:"userAgent,omitempty"`
+	RequestID string          `json:"requestId,omitempty"`
+	JTI       string          `json:"jti,omitempty"`
+	Metadata  json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// AuditFilter — фильтр журнала; UserID и Email задаёт только админ,
+// для /me/activity UserID подставляется из токена
+type AuditFilter struct {
+	UserID    string    `query:"user_id" validate:"omitempty,uuid"`
+	Email     string    `query:"email" validate:"omitempty,max=255"`
+	EventType string    `query:"event_type" validate:"omitempty,max=50"`
+	IP        string    `query:"ip" validate:"omitempty,max=64"`
+	Success   *bool     `query:"success"`
+	From      time.Time `query:"from"`
+	To        time.Time `query:"to"`
+	Limit     int       `query:"limit" validate:"omitempty,gte=1,lte=100"`
+	Offset    int       `query:"offset" validate:"omitempty,gte=0"`
+}
+
+// AuditList — страница журнала
+type AuditList struct {
+	Entries []*AuditEntry `json:"entries"`
+	Total   int           `json:"total"`
+	Limit   int           `json:"limit"`
+	Offset  int           `json:"offset"`
+}
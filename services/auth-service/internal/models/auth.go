@@ -5,26 +5,69 @@ package models
 
 type UserLogin struct {
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required,max=256"`
 }
 
 type UserRegister struct {
 	Email     string  `json:"email" validate:"required,email"`
-	Password  string  `json:"password" validate:"required,min=6"`
+	Password  string  `json:"password" validate:"required,max=256"` // Остальные требования — политика паролей
 	FirstName string  `json:"firstName" validate:"required,min=1"`
 	LastName  string  `json:"lastName" validate:"required,min=1"`
 	Phone     *string `json:"phone,omitempty" validate:"omitempty,e164"`
 }
 
 type ChangePasswordRequest struct {
-	OldPassword string `json:"oldPassword" validate:"required,min=6"`
-	NewPassword string `json:"newPassword" validate:"required,min=6"`
+	OldPassword string `json:"oldPassword" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,max=256"`
 }
 
 type ResetPasswordRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
+type ResetPasswordConfirmRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,max=256"`
+}
+
 type VerifyEmailRequest struct {
 	Token string `json:"token" validate:"required"`
 }
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type MagicLinkConsumeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ChangeEmailRequest struct {
+	NewEmail string `json:"newEmail" validate:"required,email"`
+	Password string `json:"password" validate:"required,max=256"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type OTPRequest struct {
+	Phone string `json:"phone" validate:"required,e164"`
+}
+
+type OTPVerifyRequest struct {
+	Phone string `json:"phone" validate:"required,e164"`
+	Code  string `json:"code" validate:"required,numeric,min=4,max=10"`
+}
+
+type SetPhoneRequest struct {
+	Phone string `json:"phone" validate:"required,e164"`
+}
+
+type VerifyPhoneRequest struct {
+	Code string `json:"code" validate:"required,numeric,min=4,max=10"`
+}
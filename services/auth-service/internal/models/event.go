@@ -15,3 +15,54 @@ type UserRegistered struct {
 	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// UserVerified — событие подтверждения email
+type UserVerified struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Email      string    `json:"email"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// AccountLocked — аккаунт временно заблокирован после серии неудачных входов
+type AccountLocked struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Email          string    `json:"email"`
+	IP             string    `json:"ip,omitempty"`
+	FailedAttempts int       `json:"failed_attempts"`
+	LockedUntil    time.Time `json:"locked_until"`
+}
+
+// UserEmailChanged — пользователь подтвердил новый email
+type UserEmailChanged struct {
+	UserID    uuid.UUID `json:"user_id"`
+	OldEmail  string    `json:"old_email"`
+	NewEmail  string    `json:"new_email"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// UserStatusChanged — администратор изменил статус или роль пользователя
+type UserStatusChanged struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Email          string    `json:"email"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status"`
+	Role           string    `json:"role"`
+	PreviousRole   string    `json:"previous_role"`
+	Reason         string    `json:"reason,omitempty"`
+	ChangedBy      uuid.UUID `json:"changed_by"`
+	ChangedAt      time.Time `json:"changed_at"`
+}
+
+// UserDeleted — пользователь удалил аккаунт. Сервисы стирают свои данные
+// и подтверждают это сообщением UserDeletionAck в топик подтверждений.
+type UserDeleted struct {
+	UserID    uuid.UUID `json:"user_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// UserDeletionAck — подтверждение сервиса, что данные пользователя удалены
+type UserDeletionAck struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Service     string    `json:"service"`
+	CompletedAt time.Time `json:"completed_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// JWK — публичный ключ в формате RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`           // RSA | OKP
+	Kid string `json:"kid"`           // ID ключа (совпадает с заголовком kid в JWT)
+	Use string `json:"use"`           // Всегда "sig"
+	Alg string `json:"alg"`           // RS256 | EdDSA
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // Ed25519
+	X   string `json:"x,omitempty"`   // Ed25519 public key
+}
+
+// JWKSet — набор публичных ключей для /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
@@ -12,6 +12,8 @@ type TokenPair struct {
 	RefreshToken string `json:"refresh_token"` // JWT refresh token
 	ExpiresIn    int64  `json:"expires_in"`    // Время жизни access в секундах (Unix)
 	TokenType    string `json:"token_type"`    // Всегда "Bearer"
+
+	JTI string `json:"-"` // jti сессии — для журнала аудита, клиенту не отдаётся
 }
 
 // AccessTokenClaims — claims для access токена (минималистичный, но информативный)
@@ -25,6 +27,9 @@ type AccessTokenClaims struct {
 	// Прикладные поля
 	Email string `json:"email,omitempty"` // Опционально
 	Role  string `json:"role,omitempty"`  // Опционально
+
+	// Права роли на момент выдачи токена (см. auth.role_permissions)
+	Permissions []string `json:"perms,omitempty"`
 }
 
 // RefreshTokenClaims — claims для refresh токена (минималистичный!)
@@ -32,8 +37,9 @@ type RefreshTokenClaims struct {
 	jwt.RegisteredClaims
 
 	// Только то, что нужно для безопасного обновления
-	Sub string `json:"sub"` // Subject — ID пользователя
-	JTI string `json:"jti"` // JWT ID — для отзыва
+	Sub    string `json:"sub"`           // Subject — ID пользователя
+	JTI    string `json:"jti"`           // JWT ID — для отзыва
+	Family string `json:"fam,omitempty"` // Семейство: общий ID для всей цепочки ротаций
 }
 
 // RefreshTokenRequest — запрос на обновление токена
@@ -47,7 +53,7 @@ type RevokeTokenRequest struct {
 }
 
 // NewAccessTokenClaims создаёт claims для access токена
-func NewAccessTokenClaims(userID, email, role, jti string, issuer string, ttl time.Duration) AccessTokenClaims {
+func NewAccessTokenClaims(userID, email, role string, permissions []string, jti string, issuer string, ttl time.Duration) AccessTokenClaims {
 	now := time.Now()
 	exp := now.Add(ttl)
 
@@ -64,11 +70,13 @@ func NewAccessTokenClaims(userID, email, role, jti string, issuer string, ttl ti
 		Sub:   userID,
 		Email: email,
 		Role:  role,
+
+		Permissions: permissions,
 	}
 }
 
 // NewRefreshTokenClaims создаёт claims для refresh токена
-func NewRefreshTokenClaims(userID, jti string, issuer string, ttl time.Duration) RefreshTokenClaims {
+func NewRefreshTokenClaims(userID, jti, family string, issuer string, ttl time.Duration) RefreshTokenClaims {
 	now := time.Now()
 	exp := now.Add(ttl)
 
@@ -81,7 +89,8 @@ func NewRefreshTokenClaims(userID, jti string, issuer string, ttl time.Duration)
 			ExpiresAt: jwt.NewNumericDate(exp),
 			NotBefore: jwt.NewNumericDate(now),
 		},
-		JTI: jti,
-		Sub: userID,
+		JTI:    jti,
+		Sub:    userID,
+		Family: family,
 	}
 }
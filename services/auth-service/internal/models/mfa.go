@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserMFA — настройки TOTP пользователя (auth.user_mfa)
+type UserMFA struct {
+	UserID          uuid.UUID  `db:"user_id"`
+	SecretEncrypted string     `db:"secret_encrypted"`
+	Enabled         bool       `db:"enabled"`
+	EnabledAt       *time.Time `db:"enabled_at"`
+	RecoveryCodes   []string   `db:"recovery_codes"` // Хэши неиспользованных кодов
+	LastUsedStep    int64      `db:"last_used_step"`
+}
+
+// MFASetupResponse — секрет и otpauth URI для приложения-аутентификатора
+type MFASetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauthUri"`
+}
+
+// MFAEnableRequest — подтверждение включения 2FA кодом из приложения
+type MFAEnableRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// MFAVerifyRequest — второй шаг входа: challenge + TOTP или recovery код
+type MFAVerifyRequest struct {
+	ChallengeToken string `json:"challengeToken" validate:"required"`
+	Code           string `json:"code" validate:"required,min=6,max=32"`
+}
+
+// MFAChallenge — ответ Login, когда требуется второй фактор
+type MFAChallenge struct {
+	ChallengeToken string `json:"challengeToken"`
+	ExpiresIn      int64  `json:"expiresIn"` // Секунды
+}
+
+// LoginResult — результат первого шага входа: либо токены, либо MFA challenge
+type LoginResult struct {
+	Tokens    *TokenPair
+	Challenge *MFAChallenge
+}
@@ -6,12 +6,45 @@ import (
 	"github.com/google/uuid"
 )
 
+// Статусы события в auth.outbox
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusPublished = "published"
+	OutboxStatusFailed    = "failed"
+)
+
+// EventSchemaVersion — текущая версия схемы payload событий auth-service
+// (заголовок schema_version); увеличивается при несовместимом изменении payload
+const EventSchemaVersion = 1
+
 type OutboxEvent struct {
-	ID          uuid.UUID  `db:"id" json:"id"`
-	EventType   string     `db:"event_type" json:"event_type"`
-	Payload     []byte     `db:"payload" json:"-"`
-	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
-	PublishedAt *time.Time `db:"published_at" json:"published_at"`
-	Attempts    int        `db:"attempts" json:"attempts"`
-	Status      string     `db:"status" json:"status"`
+	ID            uuid.UUID  `db:"id" json:"id"`
+	AggregateID   uuid.UUID  `db:"aggregate_id" json:"aggregate_id"` // Ключ сообщения: пользователь, к которому относится событие
+	EventType     string     `db:"event_type" json:"event_type"`
+	SchemaVersion int        `db:"schema_version" json:"schema_version"`
+	Payload       []byte     `db:"payload" json:"-"`
+	RequestID     string     `db:"request_id" json:"request_id,omitempty"`   // Запрос, породивший событие (трассировка)
+	Traceparent   string     `db:"traceparent" json:"traceparent,omitempty"` // W3C traceparent того же запроса
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	PublishedAt   *time.Time `db:"published_at" json:"published_at"`
+	Attempts      int        `db:"attempts" json:"attempts"`
+	Status        string     `db:"status" json:"status"`
+	NextAttemptAt time.Time  `db:"next_attempt_at" json:"next_attempt_at"`
+	LastError     *string    `db:"last_error" json:"last_error,omitempty"`
+	FailedAt      *time.Time `db:"failed_at" json:"failed_at,omitempty"`
+}
+
+// OutboxFilter — постраничный список событий для админки
+type OutboxFilter struct {
+	EventType string `query:"event_type" validate:"omitempty,max=100"`
+	Limit     int    `query:"limit" validate:"omitempty,gte=1,lte=100"`
+	Offset    int    `query:"offset" validate:"omitempty,gte=0"`
+}
+
+// OutboxList — страница событий outbox
+type OutboxList struct {
+	Events []*OutboxEvent `json:"events"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
 }
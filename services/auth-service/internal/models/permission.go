@@ -0,0 +1,13 @@
+package models
+
+// Права доступа (auth.role_permissions.permission)
+const (
+	PermProfilesRead     = "profiles:read"
+	PermEventsModerate   = "events:moderate"
+	PermCategoriesWrite  = "categories:write"
+	PermUsersRead        = "users:read"
+	PermUsersBan         = "users:ban"
+	PermUsersManageRoles = "users:manage-roles"
+	PermAuditRead        = "audit:read"
+	PermOutboxManage     = "outbox:manage"
+)
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ServiceClient — сервис, которому разрешено получать машинные токены
+type ServiceClient struct {
+	ClientID   string    `json:"clientId"`
+	Name       string    `json:"name"`
+	SecretHash string    `json:"-"`
+	Scopes     []string  `json:"scopes"`
+	Audiences  []string  `json:"audiences"` // Сервисы, для которых выдаются токены (claim aud)
+	Disabled   bool      `json:"disabled"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// ClientCredentialsRequest — запрос токена по RFC 6749, раздел 4.4.
+// client_id/client_secret можно передать в теле или через HTTP Basic.
+type ClientCredentialsRequest struct {
+	GrantType    string `json:"grant_type" form:"grant_type" validate:"required"`
+	ClientID     string `json:"client_id" form:"client_id"`
+	ClientSecret string `json:"client_secret" form:"client_secret"`
+	Scope        string `json:"scope" form:"scope"`       // Через пробел; пусто — все scopes клиента
+	Audience     string `json:"audience" form:"audience"` // Сервис-получатель токена; пусто — единственный разрешённый клиенту
+}
+
+// ServiceToken — ответ token endpoint
+type ServiceToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"` // Всегда "Bearer"
+	ExpiresIn   int64  `json:"expires_in"` // Время жизни в секундах
+	Scope       string `json:"scope"`
+}
+
+// ServiceTokenClaims — claims машинного токена.
+// Заголовок typ = svc+jwt отличает его от access токена пользователя.
+type ServiceTokenClaims struct {
+	jwt.RegisteredClaims
+
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope"` // Через пробел, как в RFC 8693
+}
+
+// NewServiceTokenClaims создаёт claims для машинного токена, адресованного сервису audience
+func NewServiceTokenClaims(clientID, audience, scope, jti, issuer string, ttl time.Duration) ServiceTokenClaims {
+	now := time.Now()
+
+	return ServiceTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   clientID,
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+		ClientID: clientID,
+		Scope:    scope,
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ClientInfo — данные клиента, с которого выполняется вход/обновление
+type ClientInfo struct {
+	IP          string
+	UserAgent   string
+	RequestID   string
+	Traceparent string // W3C traceparent входящего запроса (пусто, если клиент его не передал)
+}
+
+// Session — активная сессия (устройство) пользователя.
+// Идентифицируется jti текущего refresh токена; при ротации метаданные переносятся на новый jti.
+type Session struct {
+	JTI           string    `json:"jti"`
+	UserAgent     string    `json:"userAgent"`
+	IP            string    `json:"ip"`
+	CreatedAt     time.Time `json:"createdAt"`
+	LastRefreshAt time.Time `json:"lastRefreshAt"`
+	Current       bool      `json:"current"`
+}
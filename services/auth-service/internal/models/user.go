@@ -13,11 +13,27 @@ type User struct {
 	Email             string     `json:"email" db:"email"`
 	PasswordHash      string     `json:"-" db:"password_hash"`
 	IsVerified        bool       `json:"isVerified" db:"is_verified"`
+	Phone             *string    `json:"phone,omitempty" db:"phone"` // E.164
+	PhoneVerified     bool       `json:"phoneVerified" db:"phone_verified"`
 	EmailVerifyToken  *string    `json:"-" db:"email_verify_token"`
 	EmailVerifySentAt *time.Time `json:"-" db:"email_verify_sent_at"`
 	Role              string     `json:"role" db:"role"`     // user, admin
-	Status            string     `json:"status" db:"status"` // active, blocked
+	Status            string     `json:"status" db:"status"` // active, blocked, banned, deleted
 	LastLoginAt       *time.Time `json:"lastLoginAt,omitempty" db:"last_login_at"`
 	CreatedAt         time.Time  `json:"createdAt" db:"created_at"`
 	UpdatedAt         time.Time  `json:"updatedAt" db:"updated_at"`
 }
+
+// EmailChange — ожидающая подтверждения смена email
+type EmailChange struct {
+	UserID    uuid.UUID `json:"user_id"`
+	OldEmail  string    `json:"old_email"`
+	NewEmail  string    `json:"new_email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OTPCode — выданный SMS-код (хранится только хэш)
+type OTPCode struct {
+	UserID   uuid.UUID
+	CodeHash string
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
+	"auth-service/pkg/db/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ErrDeletionNotFound — для пользователя не запрошено удаление
+var ErrDeletionNotFound = apperror.NotFound("account deletion not found")
+
+// AccountDeletionRepository — состояние саги удаления аккаунтов
+type AccountDeletionRepository interface {
+	CreateTx(ctx context.Context, tx Tx, userID uuid.UUID) error
+	// AckTx — фиксирует подтверждение сервиса; возвращает все подтвердившие сервисы
+	// и признак того, что сага уже завершена
+	AckTx(ctx context.Context, tx Tx, userID uuid.UUID, service string) (acked []string, completed bool, err error)
+	CompleteTx(ctx context.Context, tx Tx, userID uuid.UUID) error
+	TouchEmittedTx(ctx context.Context, tx Tx, userID uuid.UUID) error
+	ListStale(ctx context.Context, emittedBefore time.Time, limit int) ([]models.AccountDeletion, error)
+}
+
+// accountDeletionRepository — реализация
+type accountDeletionRepository struct {
+	db     *postgres.DB
+	logger *zap.SugaredLogger
+}
+
+// NewAccountDeletionRepository — конструктор
+func NewAccountDeletionRepository(db *postgres.DB, logger *zap.SugaredLogger) AccountDeletionRepository {
+	return &accountDeletionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateTx — начало саги
+func (r *accountDeletionRepository) CreateTx(ctx context.Context, tx Tx, userID uuid.UUID) error {
+	query := `
+		INSERT INTO auth.account_deletions (user_id)
+		VALUES ($1)
+		ON CONFLICT (user_id) DO UPDATE SET last_emitted_at = NOW(), completed_at = NULL
+	`
+	if err := tx.Exec(ctx, query, userID); err != nil {
+		r.logger.Errorw("Failed to create account deletion", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to create account deletion: %w", err)
+	}
+	return nil
+}
+
+// AckTx — подтверждение сервиса (повторное подтверждение игнорируется)
+func (r *accountDeletionRepository) AckTx(ctx context.Context, tx Tx, userID uuid.UUID, service string) ([]string, bool, error) {
+	// Блокируем сагу: параллельные подтверждения разных сервисов не должны разминуться
+	var completedAt *time.Time
+	err := tx.QueryRow(ctx,
+		`SELECT completed_at FROM auth.account_deletions WHERE user_id = $1 FOR UPDATE`,
+		userID,
+	).Scan(&completedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, ErrDeletionNotFound
+		}
+		return nil, false, fmt.Errorf("query failed: %w", err)
+	}
+	if completedAt != nil {
+		return nil, true, nil
+	}
+
+	insert := `
+		INSERT INTO auth.account_deletion_acks (user_id, service)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, service) DO NOTHING
+	`
+	if err := tx.Exec(ctx, insert, userID, service); err != nil {
+		r.logger.Errorw("Failed to store deletion ack", "user_id", userID, "service", service, "error", err)
+		return nil, false, fmt.Errorf("failed to store ack: %w", err)
+	}
+
+	var acked []string
+	err = tx.QueryRow(ctx,
+		`SELECT COALESCE(array_agg(service), '{}') FROM auth.account_deletion_acks WHERE user_id = $1`,
+		userID,
+	).Scan(&acked)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load acks: %w", err)
+	}
+
+	return acked, false, nil
+}
+
+// CompleteTx — сага завершена
+func (r *accountDeletionRepository) CompleteTx(ctx context.Context, tx Tx, userID uuid.UUID) error {
+	query := `UPDATE auth.account_deletions SET completed_at = NOW() WHERE user_id = $1`
+	if err := tx.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to complete account deletion: %w", err)
+	}
+	return nil
+}
+
+// TouchEmittedTx — UserDeleted отправлен повторно
+func (r *accountDeletionRepository) TouchEmittedTx(ctx context.Context, tx Tx, userID uuid.UUID) error {
+	query := `UPDATE auth.account_deletions SET last_emitted_at = NOW() WHERE user_id = $1`
+	if err := tx.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to update account deletion: %w", err)
+	}
+	return nil
+}
+
+// ListStale — незавершённые саги, по которым давно не было UserDeleted
+func (r *accountDeletionRepository) ListStale(ctx context.Context, emittedBefore time.Time, limit int) ([]models.AccountDeletion, error) {
+	query := `
+		SELECT user_id, requested_at, last_emitted_at
+		FROM auth.account_deletions
+		WHERE completed_at IS NULL AND last_emitted_at < $1
+		ORDER BY last_emitted_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, emittedBefore, limit)
+	if err != nil {
+		r.logger.Errorw("Failed to list pending account deletions", "error", err)
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var deletions []models.AccountDeletion
+	for rows.Next() {
+		var d models.AccountDeletion
+		if err := rows.Scan(&d.UserID, &d.RequestedAt, &d.LastEmittedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan account deletion: %w", err)
+		}
+		deletions = append(deletions, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return deletions, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// AttemptRepository — скользящие окна попыток и временные блокировки (Redis).
+// Ключи задаёт вызывающий код, например "login:email:<email>".
+type AttemptRepository interface {
+	// Hit — регистрирует попытку и возвращает число попыток в окне window
+	Hit(ctx context.Context, key string, window time.Duration) (int64, error)
+	// Reset — сбрасывает счётчики попыток
+	Reset(ctx context.Context, keys ...string) error
+	// Block — блокирует ключ на ttl
+	Block(ctx context.Context, key string, ttl time.Duration) error
+	// BlockedFor — оставшееся время блокировки (0, если блокировки нет)
+	BlockedFor(ctx context.Context, key string) (time.Duration, error)
+}
+
+// attemptRepository — реализация
+type attemptRepository struct {
+	redis  *redis.Client
+	logger *zap.SugaredLogger
+}
+
+// NewAttemptRepository — конструктор
+func NewAttemptRepository(redis *redis.Client, logger *zap.SugaredLogger) AttemptRepository {
+	return &attemptRepository{
+		redis:  redis,
+		logger: logger,
+	}
+}
+
+// Hit — sorted set с временем попыток: старые за пределами окна отбрасываются
+func (r *attemptRepository) Hit(ctx context.Context, key string, window time.Duration) (int64, error) {
+	now := time.Now()
+	setKey := attemptsKey(key)
+
+	pipe := r.redis.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, setKey, "-inf", strconv.FormatInt(now.Add(-window).UnixNano(), 10))
+	// uuid в member — несколько попыток в одну наносекунду не схлопываются
+	pipe.ZAdd(ctx, setKey, redis.Z{Score: float64(now.UnixNano()), Member: uuid.NewString()})
+	count := pipe.ZCard(ctx, setKey)
+	pipe.PExpire(ctx, setKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Errorw("Failed to record attempt", "key", key, "error", err)
+		return 0, fmt.Errorf("record attempt: %w", err)
+	}
+	return count.Val(), nil
+}
+
+// Reset — удаляет счётчики попыток
+func (r *attemptRepository) Reset(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	setKeys := make([]string, len(keys))
+	for i, key := range keys {
+		setKeys[i] = attemptsKey(key)
+	}
+	if err := r.redis.Del(ctx, setKeys...).Err(); err != nil {
+		r.logger.Errorw("Failed to reset attempts", "keys", keys, "error", err)
+		return fmt.Errorf("reset attempts: %w", err)
+	}
+	return nil
+}
+
+// Block — блокировка не продлевает уже действующую более длинную
+func (r *attemptRepository) Block(ctx context.Context, key string, ttl time.Duration) error {
+	current, err := r.BlockedFor(ctx, key)
+	if err != nil {
+		return err
+	}
+	if current >= ttl {
+		return nil
+	}
+	if err := r.redis.Set(ctx, blockKey(key), "1", ttl).Err(); err != nil {
+		r.logger.Errorw("Failed to set block", "key", key, "error", err)
+		return fmt.Errorf("set block: %w", err)
+	}
+	return nil
+}
+
+// BlockedFor — PTTL ключа блокировки
+func (r *attemptRepository) BlockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.redis.PTTL(ctx, blockKey(key)).Result()
+	if err != nil {
+		r.logger.Errorw("Failed to check block", "key", key, "error", err)
+		return 0, fmt.Errorf("check block: %w", err)
+	}
+	// -2: ключа нет, -1: ключ без TTL (не используется)
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func attemptsKey(key string) string {
+	return "attempts:" + key
+}
+
+func blockKey(key string) string {
+	return "block:" + key
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"auth-service/internal/models"
+	"auth-service/pkg/db/postgres"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// AuditRepository — журнал аутентификации (auth.audit_log)
+type AuditRepository interface {
+	InsertBatch(ctx context.Context, entries []*models.AuditEntry) error
+	List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, int, error)
+}
+
+// auditRepository — реализация на Postgres
+type auditRepository struct {
+	db     *postgres.DB
+	logger *zap.SugaredLogger
+}
+
+// NewAuditRepository — конструктор
+func NewAuditRepository(db *postgres.DB, logger *zap.SugaredLogger) AuditRepository {
+	return &auditRepository{db: db, logger: logger}
+}
+
+// InsertBatch — пакетная запись через COPY: журнал пишется фоново и пачками
+func (r *auditRepository) InsertBatch(ctx context.Context, entries []*models.AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	columns := []string{"user_id", "email", "event_type", "success", "ip", "user_agent", "request_id", "jti", "metadata", "created_at"}
+	rows := make([][]any, 0, len(entries))
+	for _, e := range entries {
+		var metadata []byte
+		if len(e.Metadata) > 0 {
+			metadata = e.Metadata
+		}
+		rows = append(rows, []any{
+			e.UserID, nullString(e.Email), e.EventType, e.Success, nullString(e.IP),
+			nullString(e.UserAgent), nullString(e.RequestID), nullString(e.JTI), metadata, e.CreatedAt,
+		})
+	}
+
+	if _, err := r.db.Pool.CopyFrom(ctx, pgx.Identifier{"auth", "audit_log"}, columns, pgx.CopyFromRows(rows)); err != nil {
+		return fmt.Errorf("copy audit entries: %w", err)
+	}
+	return nil
+}
+
+// List — записи журнала с фильтрами, новые сверху; второе значение — общее количество
+func (r *auditRepository) List(ctx context.Context, filter models.AuditFilter) ([]*models.AuditEntry, int, error) {
+	query := `
+		SELECT id, user_id, COALESCE(email, ''), event_type, success, COALESCE(ip, ''),
+		       COALESCE(user_agent, ''), COALESCE(request_id, ''), COALESCE(jti, ''), metadata, created_at,
+		       COUNT(*) OVER() AS total
+		FROM auth.audit_log
+		WHERE ($1::uuid IS NULL OR user_id = $1)
+		  AND ($2 = '' OR email = $2)
+		  AND ($3 = '' OR event_type = $3)
+		  AND ($4 = '' OR ip = $4)
+		  AND ($5::boolean IS NULL OR success = $5)
+		  AND ($6::timestamptz IS NULL OR created_at >= $6)
+		  AND ($7::timestamptz IS NULL OR created_at < $7)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $8 OFFSET $9
+	`
+
+	var userID *uuid.UUID
+	if filter.UserID != "" {
+		id, err := uuid.Parse(filter.UserID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid user id: %w", err)
+		}
+		userID = &id
+	}
+
+	rows, err := r.db.Pool.Query(ctx, query,
+		userID, filter.Email, filter.EventType, filter.IP, filter.Success,
+		nullTime(filter.From), nullTime(filter.To), filter.Limit, filter.Offset,
+	)
+	if err != nil {
+		r.logger.Errorw("Failed to list audit entries", "error", err)
+		return nil, 0, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*models.AuditEntry, 0, filter.Limit)
+	total := 0
+	for rows.Next() {
+		e := &models.AuditEntry{}
+		var metadata []byte
+		if err := rows.Scan(
+			&e.ID, &e.UserID, &e.Email, &e.EventType, &e.Success, &e.IP,
+			&e.UserAgent, &e.RequestID, &e.JTI, &metadata, &e.CreatedAt, &total,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		e.Metadata = metadata
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// nullString — пустая строка пишется как NULL
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nullTime — нулевое время означает «без ограничения»
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -2,19 +2,25 @@ package repository
 
 import (
 	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
 	"auth-service/pkg/db/postgres"
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
+// ErrUserNotFound — пользователь не найден
+var ErrUserNotFound = apperror.NotFound("user not found")
+
 // Tx — интерфейс для работы с транзакциями
 type Tx interface {
 	Exec(ctx context.Context, query string, args ...interface{}) error
+	QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row
 	Commit(ctx context.Context) error
 	Rollback(ctx context.Context) error
 }
@@ -26,10 +32,23 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 	Create(ctx context.Context, user *models.User) error
 	UpdateLastLogin(ctx context.Context, userID uuid.UUID) error
+	SetEmailVerifyToken(ctx context.Context, userID uuid.UUID, tokenHash string, sentAt time.Time) error
+	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	GetByPhone(ctx context.Context, phone string) (*models.User, error)
+	SetPhone(ctx context.Context, userID uuid.UUID, phone string) error
+	MarkPhoneVerified(ctx context.Context, userID uuid.UUID, phone string) (bool, error)
+	List(ctx context.Context, filter models.UserFilter) ([]*models.User, int, error)
 
 	// МЕТОДЫ ДЛЯ ТРАНЗАКЦИЙ
 	BeginTx(ctx context.Context) (Tx, error)
 	CreateTx(ctx context.Context, tx Tx, user *models.User) error
+	VerifyEmailTx(ctx context.Context, tx Tx, tokenHash string, sentAfter time.Time) (*models.User, error)
+	GetByIDForUpdateTx(ctx context.Context, tx Tx, id uuid.UUID) (*models.User, error)
+	UpdateStatusTx(ctx context.Context, tx Tx, id uuid.UUID, status string) error
+	UpdateRoleTx(ctx context.Context, tx Tx, id uuid.UUID, role string) error
+	UpdateEmailTx(ctx context.Context, tx Tx, id uuid.UUID, email string) error
+	MarkDeletedTx(ctx context.Context, tx Tx, id uuid.UUID) error
+	HardDeleteTx(ctx context.Context, tx Tx, id uuid.UUID) error
 }
 
 // userRepository — реализация
@@ -56,6 +75,10 @@ func (t *pgxTx) Exec(ctx context.Context, query string, args ...interface{}) err
 	return err
 }
 
+func (t *pgxTx) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	return t.tx.QueryRow(ctx, query, args...)
+}
+
 func (t *pgxTx) Commit(ctx context.Context) error {
 	return t.tx.Commit(ctx)
 }
@@ -83,15 +106,23 @@ func (r *userRepository) CreateTx(ctx context.Context, tx Tx, user *models.User)
 
 	query := `
 		INSERT INTO auth.users (
-			id, email, password_hash, role, status, is_verified, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			id, email, password_hash, role, status, is_verified,
+			email_verify_token, email_verify_sent_at, created_at, updated_at, phone
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	log := r.logger.With("user_id", user.ID, "email", user.Email)
 	if err := pgxTx.Exec(ctx, query,
 		user.ID, user.Email, user.PasswordHash, user.Role, user.Status, user.IsVerified,
-		user.CreatedAt, user.UpdatedAt,
+		user.EmailVerifyToken, user.EmailVerifySentAt, user.CreatedAt, user.UpdatedAt, user.Phone,
 	); err != nil {
+		if isEmailConflict(err) {
+			return ErrEmailTaken
+		}
+		// Параллельная регистрация с тем же email прошла между проверкой и вставкой
+		if isEmailConflict(err) {
+			return ErrEmailTaken
+		}
 		log.Errorw("Failed to create user in transaction", "error", err)
 		return fmt.Errorf("failed to insert user in transaction: %w", err)
 	}
@@ -104,14 +135,15 @@ func (r *userRepository) CreateTx(ctx context.Context, tx Tx, user *models.User)
 func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 	query := `
 		INSERT INTO auth.users (
-			id, email, password_hash, role, status, is_verified, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			id, email, password_hash, role, status, is_verified,
+			email_verify_token, email_verify_sent_at, created_at, updated_at, phone
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	log := r.logger.With("user_id", user.ID, "email", user.Email)
 	if err := r.db.Exec(ctx, query,
 		user.ID, user.Email, user.PasswordHash, user.Role, user.Status, user.IsVerified,
-		user.CreatedAt, user.UpdatedAt,
+		user.EmailVerifyToken, user.EmailVerifySentAt, user.CreatedAt, user.UpdatedAt, user.Phone,
 	); err != nil {
 		log.Errorw("Failed to create user", "error", err)
 		return fmt.Errorf("failed to insert user: %w", err)
@@ -124,19 +156,21 @@ func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 // GetByID — по ID
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, role, status, is_verified, last_login_at, created_at, updated_at
+		SELECT id, email, password_hash, role, status, is_verified, email_verify_sent_at,
+		       last_login_at, created_at, updated_at, phone, phone_verified
 		FROM auth.users WHERE id = $1
 	`
 
 	user := &models.User{}
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Role, &user.Status,
-		&user.IsVerified, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
+		&user.IsVerified, &user.EmailVerifySentAt, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
+		&user.Phone, &user.PhoneVerified,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			r.logger.Infow("User not found by ID", "user_id", id)
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		r.logger.Errorw("DB error on GetByID", "user_id", id, "error", err)
 		return nil, fmt.Errorf("query failed: %w", err)
@@ -148,19 +182,21 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 // GetByEmail — по email
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, role, status, is_verified, last_login_at, created_at, updated_at
+		SELECT id, email, password_hash, role, status, is_verified, email_verify_sent_at,
+		       last_login_at, created_at, updated_at, phone, phone_verified
 		FROM auth.users WHERE email = $1
 	`
 
 	user := &models.User{}
 	err := r.db.QueryRow(ctx, query, email).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Role, &user.Status,
-		&user.IsVerified, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
+		&user.IsVerified, &user.EmailVerifySentAt, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
+		&user.Phone, &user.PhoneVerified,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			r.logger.Infow("User not found by email", "email", email)
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		r.logger.Errorw("DB error on GetByEmail", "email", email, "error", err)
 		return nil, fmt.Errorf("query failed: %w", err)
@@ -189,3 +225,50 @@ func (r *userRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) erro
 	}
 	return nil
 }
+
+// SetEmailVerifyToken — сохраняет хэш нового токена подтверждения email
+func (r *userRepository) SetEmailVerifyToken(ctx context.Context, id uuid.UUID, tokenHash string, sentAt time.Time) error {
+	query := `
+		UPDATE auth.users
+		SET email_verify_token = $2, email_verify_sent_at = $3
+		WHERE id = $1 AND is_verified = FALSE
+	`
+	if err := r.db.Exec(ctx, query, id, tokenHash, sentAt); err != nil {
+		r.logger.Errorw("Failed to set email verify token", "user_id", id, "error", err)
+		return fmt.Errorf("failed to set verify token: %w", err)
+	}
+	return nil
+}
+
+// VerifyEmailTx — подтверждает email по хэшу токена и гасит токен (одноразовый)
+func (r *userRepository) VerifyEmailTx(ctx context.Context, tx Tx, tokenHash string, sentAfter time.Time) (*models.User, error) {
+	query := `
+		UPDATE auth.users
+		SET is_verified = TRUE, email_verify_token = NULL, email_verify_sent_at = NULL
+		WHERE email_verify_token = $1 AND email_verify_sent_at > $2 AND is_verified = FALSE
+		RETURNING id, email, role, status
+	`
+
+	user := &models.User{IsVerified: true}
+	err := tx.QueryRow(ctx, query, tokenHash, sentAfter).Scan(&user.ID, &user.Email, &user.Role, &user.Status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Infow("Email verify token not found or expired")
+			return nil, ErrUserNotFound
+		}
+		r.logger.Errorw("DB error on VerifyEmailTx", "error", err)
+		return nil, fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	return user, nil
+}
+
+// UpdatePassword — замена хэша пароля
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	query := `UPDATE auth.users SET password_hash = $2 WHERE id = $1`
+	if err := r.db.Exec(ctx, query, id, passwordHash); err != nil {
+		r.logger.Errorw("Failed to update password", "user_id", id, "error", err)
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ErrEmailChangeNotFound — запрос смены email не найден, истёк или уже подтверждён
+var ErrEmailChangeNotFound = apperror.NotFound("email change request not found")
+
+// EmailChangeRepository — одноразовые токены подтверждения нового email (Redis)
+type EmailChangeRepository interface {
+	Save(ctx context.Context, tokenHash string, change models.EmailChange, ttl time.Duration) error
+	Consume(ctx context.Context, tokenHash string) (*models.EmailChange, error)
+}
+
+// emailChangeRepository — реализация
+type emailChangeRepository struct {
+	redis  *redis.Client
+	logger *zap.SugaredLogger
+}
+
+// NewEmailChangeRepository — конструктор
+func NewEmailChangeRepository(redis *redis.Client, logger *zap.SugaredLogger) EmailChangeRepository {
+	return &emailChangeRepository{
+		redis:  redis,
+		logger: logger,
+	}
+}
+
+// Save — сохраняет запрос смены; предыдущий незавершённый запрос пользователя аннулируется
+func (r *emailChangeRepository) Save(ctx context.Context, tokenHash string, change models.EmailChange, ttl time.Duration) error {
+	userKey := "emailchange_user:" + change.UserID.String()
+
+	data, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("marshal email change: %w", err)
+	}
+
+	prev, err := r.redis.Get(ctx, userKey).Result()
+	if err != nil && err != redis.Nil {
+		r.logger.Errorw("Failed to get previous email change", "user_id", change.UserID, "error", err)
+		return fmt.Errorf("get previous email change: %w", err)
+	}
+
+	pipe := r.redis.TxPipeline()
+	if prev != "" {
+		pipe.Del(ctx, "emailchange:"+prev)
+	}
+	pipe.Set(ctx, "emailchange:"+tokenHash, data, ttl)
+	pipe.Set(ctx, userKey, tokenHash, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Errorw("Failed to store email change", "user_id", change.UserID, "error", err)
+		return fmt.Errorf("store email change: %w", err)
+	}
+	return nil
+}
+
+// Consume — атомарно забирает запрос (GETDEL), повторное подтверждение невозможно
+func (r *emailChangeRepository) Consume(ctx context.Context, tokenHash string) (*models.EmailChange, error) {
+	val, err := r.redis.GetDel(ctx, "emailchange:"+tokenHash).Bytes()
+	if err == redis.Nil {
+		return nil, ErrEmailChangeNotFound
+	}
+	if err != nil {
+		r.logger.Errorw("Failed to consume email change", "error", err)
+		return nil, fmt.Errorf("consume email change: %w", err)
+	}
+
+	var change models.EmailChange
+	if err := json.Unmarshal(val, &change); err != nil {
+		return nil, fmt.Errorf("invalid email change payload: %w", err)
+	}
+	if change.UserID == uuid.Nil {
+		return nil, fmt.Errorf("invalid email change payload: missing user id")
+	}
+
+	r.redis.Del(ctx, "emailchange_user:"+change.UserID.String())
+	return &change, nil
+}
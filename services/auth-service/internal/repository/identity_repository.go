@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
+	"auth-service/pkg/db/postgres"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ErrIdentityNotFound — внешний аккаунт ещё не связан с пользователем
+var ErrIdentityNotFound = apperror.NotFound("identity not found")
+
+// IdentityRepository — связи с внешними провайдерами (auth.user_identities)
+type IdentityRepository interface {
+	GetUserID(ctx context.Context, provider, subject string) (uuid.UUID, error)
+	TouchLogin(ctx context.Context, provider, subject string) error
+	CreateTx(ctx context.Context, tx Tx, identity *models.UserIdentity) error
+}
+
+// identityRepository — реализация
+type identityRepository struct {
+	db     *postgres.DB
+	logger *zap.SugaredLogger
+}
+
+// NewIdentityRepository — конструктор
+func NewIdentityRepository(db *postgres.DB, logger *zap.SugaredLogger) IdentityRepository {
+	return &identityRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetUserID — пользователь, связанный с внешним аккаунтом
+func (r *identityRepository) GetUserID(ctx context.Context, provider, subject string) (uuid.UUID, error) {
+	query := `SELECT user_id FROM auth.user_identities WHERE provider = $1 AND subject = $2`
+
+	var userID uuid.UUID
+	if err := r.db.QueryRow(ctx, query, provider, subject).Scan(&userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrIdentityNotFound
+		}
+		r.logger.Errorw("DB error on identity lookup", "provider", provider, "error", err)
+		return uuid.Nil, fmt.Errorf("query failed: %w", err)
+	}
+	return userID, nil
+}
+
+// TouchLogin — время последнего входа через провайдера
+func (r *identityRepository) TouchLogin(ctx context.Context, provider, subject string) error {
+	query := `UPDATE auth.user_identities SET last_login_at = NOW() WHERE provider = $1 AND subject = $2`
+	if err := r.db.Exec(ctx, query, provider, subject); err != nil {
+		r.logger.Errorw("Failed to update identity last login", "provider", provider, "error", err)
+		return fmt.Errorf("update failed: %w", err)
+	}
+	return nil
+}
+
+// CreateTx — связывает внешний аккаунт с пользователем в транзакции
+func (r *identityRepository) CreateTx(ctx context.Context, tx Tx, identity *models.UserIdentity) error {
+	query := `
+		INSERT INTO auth.user_identities (id, user_id, provider, subject, email, last_login_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if err := tx.Exec(ctx, query,
+		identity.ID, identity.UserID, identity.Provider, identity.Subject,
+		identity.Email, identity.LastLoginAt, identity.CreatedAt,
+	); err != nil {
+		r.logger.Errorw("Failed to create identity", "user_id", identity.UserID, "provider", identity.Provider, "error", err)
+		return fmt.Errorf("failed to insert identity: %w", err)
+	}
+
+	r.logger.Infow("Identity linked", "user_id", identity.UserID, "provider", identity.Provider)
+	return nil
+}
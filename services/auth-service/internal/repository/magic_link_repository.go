@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"auth-service/pkg/apperror"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ErrMagicLinkNotFound — ссылка для входа не найдена, истекла или уже использована
+var ErrMagicLinkNotFound = apperror.NotFound("magic link not found")
+
+// MagicLinkRepository — одноразовые ссылки для входа без пароля (Redis)
+type MagicLinkRepository interface {
+	Save(ctx context.Context, tokenHash string, userID uuid.UUID, ttl time.Duration) error
+	Consume(ctx context.Context, tokenHash string) (uuid.UUID, error)
+}
+
+// magicLinkRepository — реализация
+type magicLinkRepository struct {
+	redis  *redis.Client
+	logger *zap.SugaredLogger
+}
+
+// NewMagicLinkRepository — конструктор
+func NewMagicLinkRepository(redis *redis.Client, logger *zap.SugaredLogger) MagicLinkRepository {
+	return &magicLinkRepository{
+		redis:  redis,
+		logger: logger,
+	}
+}
+
+// Save — сохраняет хэш токена; предыдущая ссылка пользователя аннулируется
+func (r *magicLinkRepository) Save(ctx context.Context, tokenHash string, userID uuid.UUID, ttl time.Duration) error {
+	userKey := "magiclink_user:" + userID.String()
+
+	prev, err := r.redis.Get(ctx, userKey).Result()
+	if err != nil && err != redis.Nil {
+		r.logger.Errorw("Failed to get previous magic link", "user_id", userID, "error", err)
+		return fmt.Errorf("get previous magic link: %w", err)
+	}
+
+	pipe := r.redis.TxPipeline()
+	if prev != "" {
+		pipe.Del(ctx, "magiclink:"+prev)
+	}
+	pipe.Set(ctx, "magiclink:"+tokenHash, userID.String(), ttl)
+	pipe.Set(ctx, userKey, tokenHash, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Errorw("Failed to store magic link", "user_id", userID, "error", err)
+		return fmt.Errorf("store magic link: %w", err)
+	}
+	return nil
+}
+
+// Consume — атомарно забирает токен (GETDEL): повторный переход по ссылке отклоняется
+func (r *magicLinkRepository) Consume(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	val, err := r.redis.GetDel(ctx, "magiclink:"+tokenHash).Result()
+	if err == redis.Nil {
+		return uuid.Nil, ErrMagicLinkNotFound
+	}
+	if err != nil {
+		r.logger.Errorw("Failed to consume magic link", "error", err)
+		return uuid.Nil, fmt.Errorf("consume magic link: %w", err)
+	}
+
+	userID, err := uuid.Parse(val)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid user id in magic link: %w", err)
+	}
+
+	r.redis.Del(ctx, "magiclink_user:"+userID.String())
+	return userID, nil
+}
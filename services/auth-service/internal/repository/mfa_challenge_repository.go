@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"auth-service/pkg/apperror"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ErrMFAChallengeNotFound — challenge не найден, истёк или уже использован
+var ErrMFAChallengeNotFound = apperror.NotFound("mfa challenge not found")
+
+// MFAChallengeRepository — challenge токены второго шага входа (Redis)
+type MFAChallengeRepository interface {
+	Save(ctx context.Context, tokenHash string, userID uuid.UUID, ttl time.Duration) error
+	Get(ctx context.Context, tokenHash string) (uuid.UUID, error)
+	Delete(ctx context.Context, tokenHash string) error
+}
+
+// mfaChallengeRepository — реализация
+type mfaChallengeRepository struct {
+	redis  *redis.Client
+	logger *zap.SugaredLogger
+}
+
+// NewMFAChallengeRepository — конструктор
+func NewMFAChallengeRepository(redis *redis.Client, logger *zap.SugaredLogger) MFAChallengeRepository {
+	return &mfaChallengeRepository{
+		redis:  redis,
+		logger: logger,
+	}
+}
+
+// Save — сохраняет хэш challenge токена
+func (r *mfaChallengeRepository) Save(ctx context.Context, tokenHash string, userID uuid.UUID, ttl time.Duration) error {
+	if err := r.redis.Set(ctx, mfaChallengeKey(tokenHash), userID.String(), ttl).Err(); err != nil {
+		r.logger.Errorw("Failed to store MFA challenge", "user_id", userID, "error", err)
+		return fmt.Errorf("store mfa challenge: %w", err)
+	}
+	return nil
+}
+
+// Get — владелец challenge (challenge остаётся действительным до Delete или истечения TTL)
+func (r *mfaChallengeRepository) Get(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	val, err := r.redis.Get(ctx, mfaChallengeKey(tokenHash)).Result()
+	if err == redis.Nil {
+		return uuid.Nil, ErrMFAChallengeNotFound
+	}
+	if err != nil {
+		r.logger.Errorw("Failed to load MFA challenge", "error", err)
+		return uuid.Nil, fmt.Errorf("load mfa challenge: %w", err)
+	}
+
+	userID, err := uuid.Parse(val)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid user id in mfa challenge: %w", err)
+	}
+	return userID, nil
+}
+
+// Delete — гасит challenge; ErrMFAChallengeNotFound, если его уже погасил параллельный запрос
+func (r *mfaChallengeRepository) Delete(ctx context.Context, tokenHash string) error {
+	deleted, err := r.redis.Del(ctx, mfaChallengeKey(tokenHash)).Result()
+	if err != nil {
+		r.logger.Errorw("Failed to delete MFA challenge", "error", err)
+		return fmt.Errorf("delete mfa challenge: %w", err)
+	}
+	if deleted == 0 {
+		return ErrMFAChallengeNotFound
+	}
+	return nil
+}
+
+func mfaChallengeKey(tokenHash string) string {
+	return "mfa_challenge:" + tokenHash
+}
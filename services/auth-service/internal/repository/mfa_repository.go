@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
+	"auth-service/pkg/db/postgres"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrMFANotFound — у пользователя нет настроек 2FA
+	ErrMFANotFound = apperror.NotFound("mfa settings not found")
+	// ErrMFAAlreadyEnabled — 2FA уже включена
+	ErrMFAAlreadyEnabled = apperror.Conflict("mfa already enabled")
+)
+
+// MFARepository — настройки TOTP (auth.user_mfa)
+type MFARepository interface {
+	Get(ctx context.Context, userID uuid.UUID) (*models.UserMFA, error)
+	SavePending(ctx context.Context, userID uuid.UUID, secretEncrypted string) error
+	Enable(ctx context.Context, userID uuid.UUID, recoveryCodeHashes []string, step int64) error
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+}
+
+// mfaRepository — реализация
+type mfaRepository struct {
+	db     *postgres.DB
+	logger *zap.SugaredLogger
+}
+
+// NewMFARepository — конструктор
+func NewMFARepository(db *postgres.DB, logger *zap.SugaredLogger) MFARepository {
+	return &mfaRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Get — настройки 2FA пользователя
+func (r *mfaRepository) Get(ctx context.Context, userID uuid.UUID) (*models.UserMFA, error) {
+	query := `
+		SELECT user_id, secret_encrypted, enabled, enabled_at, recovery_codes, last_used_step
+		FROM auth.user_mfa WHERE user_id = $1
+	`
+
+	mfa := &models.UserMFA{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&mfa.UserID, &mfa.SecretEncrypted, &mfa.Enabled, &mfa.EnabledAt, &mfa.RecoveryCodes, &mfa.LastUsedStep,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMFANotFound
+		}
+		r.logger.Errorw("DB error on MFA Get", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	return mfa, nil
+}
+
+// SavePending — сохраняет новый (ещё не подтверждённый) секрет; включённую 2FA не перезаписывает
+func (r *mfaRepository) SavePending(ctx context.Context, userID uuid.UUID, secretEncrypted string) error {
+	query := `
+		INSERT INTO auth.user_mfa (user_id, secret_encrypted)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret_encrypted = EXCLUDED.secret_encrypted,
+		    recovery_codes = '{}',
+		    last_used_step = 0
+		WHERE auth.user_mfa.enabled = FALSE
+		RETURNING user_id
+	`
+
+	var id uuid.UUID
+	if err := r.db.QueryRow(ctx, query, userID, secretEncrypted).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrMFAAlreadyEnabled
+		}
+		r.logger.Errorw("Failed to save pending MFA secret", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to save mfa secret: %w", err)
+	}
+	return nil
+}
+
+// Enable — включает 2FA и сохраняет хэши recovery кодов
+func (r *mfaRepository) Enable(ctx context.Context, userID uuid.UUID, recoveryCodeHashes []string, step int64) error {
+	query := `
+		UPDATE auth.user_mfa
+		SET enabled = TRUE, enabled_at = NOW(), recovery_codes = $2, last_used_step = $3
+		WHERE user_id = $1 AND enabled = FALSE
+		RETURNING user_id
+	`
+
+	var id uuid.UUID
+	if err := r.db.QueryRow(ctx, query, userID, recoveryCodeHashes, step).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrMFAAlreadyEnabled
+		}
+		r.logger.Errorw("Failed to enable MFA", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to enable mfa: %w", err)
+	}
+
+	r.logger.Infow("MFA enabled", "user_id", userID)
+	return nil
+}
+
+// UseStep — фиксирует использованный шаг TOTP; false, если код этого или более позднего шага уже применялся
+func (r *mfaRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	query := `
+		UPDATE auth.user_mfa SET last_used_step = $2
+		WHERE user_id = $1 AND last_used_step < $2
+		RETURNING user_id
+	`
+
+	var id uuid.UUID
+	if err := r.db.QueryRow(ctx, query, userID, step).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		r.logger.Errorw("Failed to store TOTP step", "user_id", userID, "error", err)
+		return false, fmt.Errorf("failed to store totp step: %w", err)
+	}
+	return true, nil
+}
+
+// ConsumeRecoveryCode — атомарно удаляет использованный recovery код; false, если кода нет
+func (r *mfaRepository) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	query := `
+		UPDATE auth.user_mfa SET recovery_codes = array_remove(recovery_codes, $2)
+		WHERE user_id = $1 AND enabled = TRUE AND $2 = ANY(recovery_codes)
+		RETURNING user_id
+	`
+
+	var id uuid.UUID
+	if err := r.db.QueryRow(ctx, query, userID, codeHash).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		r.logger.Errorw("Failed to consume recovery code", "user_id", userID, "error", err)
+		return false, fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+
+	r.logger.Infow("Recovery code used", "user_id", userID)
+	return true, nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ErrOTPNotFound — код не найден, истёк или уже использован
+var ErrOTPNotFound = apperror.NotFound("otp not found")
+
+// OTPRepository — одноразовые SMS-коды (Redis). purpose разделяет вход и подтверждение номера.
+type OTPRepository interface {
+	Save(ctx context.Context, purpose, phone string, otp models.OTPCode, ttl time.Duration) error
+	Get(ctx context.Context, purpose, phone string) (*models.OTPCode, error)
+	// Delete — гасит код; false, если его уже забрал параллельный запрос
+	Delete(ctx context.Context, purpose, phone string) (bool, error)
+}
+
+// otpRepository — реализация
+type otpRepository struct {
+	redis  *redis.Client
+	logger *zap.SugaredLogger
+}
+
+// NewOTPRepository — конструктор
+func NewOTPRepository(redis *redis.Client, logger *zap.SugaredLogger) OTPRepository {
+	return &otpRepository{
+		redis:  redis,
+		logger: logger,
+	}
+}
+
+func otpKey(purpose, phone string) string {
+	return "otp:" + purpose + ":" + phone
+}
+
+// Save — новый код заменяет предыдущий
+func (r *otpRepository) Save(ctx context.Context, purpose, phone string, otp models.OTPCode, ttl time.Duration) error {
+	key := otpKey(purpose, phone)
+
+	pipe := r.redis.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, "user_id", otp.UserID.String(), "code_hash", otp.CodeHash)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Errorw("Failed to store otp", "purpose", purpose, "user_id", otp.UserID, "error", err)
+		return fmt.Errorf("store otp: %w", err)
+	}
+	return nil
+}
+
+// Get — действующий код для номера
+func (r *otpRepository) Get(ctx context.Context, purpose, phone string) (*models.OTPCode, error) {
+	fields, err := r.redis.HGetAll(ctx, otpKey(purpose, phone)).Result()
+	if err != nil {
+		r.logger.Errorw("Failed to load otp", "purpose", purpose, "error", err)
+		return nil, fmt.Errorf("load otp: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, ErrOTPNotFound
+	}
+
+	userID, err := uuid.Parse(fields["user_id"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id in otp: %w", err)
+	}
+	return &models.OTPCode{UserID: userID, CodeHash: fields["code_hash"]}, nil
+}
+
+// Delete — удаляет код
+func (r *otpRepository) Delete(ctx context.Context, purpose, phone string) (bool, error) {
+	n, err := r.redis.Del(ctx, otpKey(purpose, phone)).Result()
+	if err != nil {
+		r.logger.Errorw("Failed to delete otp", "purpose", purpose, "error", err)
+		return false, fmt.Errorf("delete otp: %w", err)
+	}
+	return n > 0, nil
+}
@@ -3,17 +3,39 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
 	"auth-service/pkg/db/postgres"
 
 	"github.com/google/uuid"
 )
 
+// outboxChannel — канал NOTIFY о новых событиях outbox
+const outboxChannel = "outbox_new"
+
+// ErrOutboxEventNotFound — нет события в статусе failed с таким id
+var ErrOutboxEventNotFound = apperror.NotFound("failed outbox event not found")
+
 type OutboxRepository interface {
+	// InsertTx — событие в транзакции изменения данных; при Commit публикатор получает NOTIFY
 	InsertTx(ctx context.Context, tx Tx, event *models.OutboxEvent) error
-	GetPendingEvents(ctx context.Context, limit int) ([]*models.OutboxEvent, error)
-	MarkAsPublished(ctx context.Context, eventID uuid.UUID) error
+	// Listen — держит отдельное соединение с LISTEN и вызывает onNotify на каждое уведомление.
+	// Возвращается при отмене ctx или потере соединения.
+	Listen(ctx context.Context, onNotify func()) error
+	// Публикация пачки: ClaimPendingTx, отправка в Kafka и отметка результатов в одной транзакции,
+	// поэтому несколько экземпляров сервиса не публикуют одно событие дважды
+	BeginTx(ctx context.Context) (Tx, error)
+	ClaimPendingTx(ctx context.Context, tx Tx, limit int) ([]*models.OutboxEvent, error)
+	MarkAsPublishedTx(ctx context.Context, tx Tx, eventIDs []uuid.UUID) error
+	// Неудачная попытка: следующая не раньше nextAttemptAt
+	MarkAttemptFailedTx(ctx context.Context, tx Tx, eventID uuid.UUID, lastError string, nextAttemptAt time.Time) error
+	// Попытки исчерпаны: событие больше не публикуется до requeue
+	MarkAsFailedTx(ctx context.Context, tx Tx, eventID uuid.UUID, lastError string) error
+	ListFailed(ctx context.Context, filter models.OutboxFilter) ([]*models.OutboxEvent, int, error)
+	Requeue(ctx context.Context, eventID uuid.UUID) error
+	RequeueAllFailed(ctx context.Context) (int64, error)
 }
 
 type outboxRepository struct {
@@ -31,23 +53,76 @@ func (r *outboxRepository) InsertTx(ctx context.Context, tx Tx, event *models.Ou
 	}
 
 	query := `
-        INSERT INTO auth.outbox (id, event_type, payload) 
-        VALUES ($1, $2, $3)
+        INSERT INTO auth.outbox (id, aggregate_id, event_type, schema_version, payload, request_id, traceparent)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
     `
-	return pgxTx.Exec(ctx, query, event.ID, event.EventType, event.Payload)
+	if err := pgxTx.Exec(ctx, query,
+		event.ID, event.AggregateID, event.EventType, event.SchemaVersion, event.Payload,
+		nullString(event.RequestID), nullString(event.Traceparent),
+	); err != nil {
+		return err
+	}
+
+	// Уведомление доставляется только после Commit; одинаковые NOTIFY одной транзакции Postgres склеивает
+	return pgxTx.Exec(ctx, "NOTIFY "+outboxChannel)
+}
+
+func (r *outboxRepository) Listen(ctx context.Context, onNotify func()) error {
+	poolConn, err := r.db.Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	// Соединение с LISTEN не возвращается в пул: иначе им воспользовались бы обычные запросы
+	conn := poolConn.Hijack()
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err := conn.Exec(ctx, "LISTEN "+outboxChannel); err != nil {
+		return fmt.Errorf("failed to listen %s: %w", outboxChannel, err)
+	}
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+		onNotify()
+	}
+}
+
+// BeginTx — транзакция публикации пачки: блокировки строк держатся до Commit
+func (r *outboxRepository) BeginTx(ctx context.Context) (Tx, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return &pgxTx{tx: tx}, nil
 }
 
-func (r *outboxRepository) GetPendingEvents(ctx context.Context, limit int) ([]*models.OutboxEvent, error) {
+// ClaimPendingTx — события, время очередной попытки которых наступило.
+// Строки блокируются до конца транзакции; строки, взятые другими экземплярами, пропускаются.
+// Берётся только самое раннее pending-событие каждого агрегата: следующее событие пользователя
+// не уйдёт, пока предыдущее публикуется другим экземпляром или ждёт повтора.
+func (r *outboxRepository) ClaimPendingTx(ctx context.Context, tx Tx, limit int) ([]*models.OutboxEvent, error) {
+	pgxTx, ok := tx.(*pgxTx)
+	if !ok {
+		return nil, fmt.Errorf("invalid transaction type")
+	}
+
 	query := `
-        SELECT id, event_type, payload, created_at, attempts 
-        FROM auth.outbox 
-        WHERE status = 'pending' 
-        ORDER BY created_at ASC 
+        SELECT o.id, o.aggregate_id, o.event_type, o.schema_version, o.payload,
+               COALESCE(o.request_id, ''), COALESCE(o.traceparent, ''),
+               o.created_at, o.attempts, o.status, o.next_attempt_at, o.last_error, o.failed_at
+        FROM auth.outbox o
+        WHERE o.status = 'pending' AND o.next_attempt_at <= NOW()
+          AND NOT EXISTS (
+              SELECT 1 FROM auth.outbox prev
+              WHERE prev.aggregate_id = o.aggregate_id AND prev.status = 'pending' AND prev.seq < o.seq
+          )
+        ORDER BY o.seq
         LIMIT $1
-        FOR UPDATE SKIP LOCKED
+        FOR UPDATE OF o SKIP LOCKED
     `
 
-	rows, err := r.db.Pool.Query(ctx, query, limit)
+	rows, err := pgxTx.tx.Query(ctx, query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query pending events: %w", err)
 	}
@@ -57,8 +132,10 @@ func (r *outboxRepository) GetPendingEvents(ctx context.Context, limit int) ([]*
 	for rows.Next() {
 		var event models.OutboxEvent
 		if err := rows.Scan(
-			&event.ID, &event.EventType, &event.Payload,
-			&event.CreatedAt, &event.Attempts,
+			&event.ID, &event.AggregateID, &event.EventType, &event.SchemaVersion, &event.Payload,
+			&event.RequestID, &event.Traceparent,
+			&event.CreatedAt, &event.Attempts, &event.Status,
+			&event.NextAttemptAt, &event.LastError, &event.FailedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
@@ -72,14 +149,120 @@ func (r *outboxRepository) GetPendingEvents(ctx context.Context, limit int) ([]*
 	return events, nil
 }
 
-func (r *outboxRepository) MarkAsPublished(ctx context.Context, eventID uuid.UUID) error {
+// MarkAsPublishedTx — опубликованные события пачки одним запросом
+func (r *outboxRepository) MarkAsPublishedTx(ctx context.Context, tx Tx, eventIDs []uuid.UUID) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
 	query := `
         UPDATE auth.outbox 
         SET status = 'published', 
             published_at = NOW(), 
             attempts = attempts + 1 
+        WHERE id = ANY($1) AND status = 'pending'
+    `
+	return tx.Exec(ctx, query, eventIDs)
+}
+
+func (r *outboxRepository) MarkAttemptFailedTx(ctx context.Context, tx Tx, eventID uuid.UUID, lastError string, nextAttemptAt time.Time) error {
+	query := `
+        UPDATE auth.outbox
+        SET attempts = attempts + 1,
+            last_error = $2,
+            next_attempt_at = $3
+        WHERE id = $1 AND status = 'pending'
+    `
+	return tx.Exec(ctx, query, eventID, lastError, nextAttemptAt)
+}
+
+func (r *outboxRepository) MarkAsFailedTx(ctx context.Context, tx Tx, eventID uuid.UUID, lastError string) error {
+	query := `
+        UPDATE auth.outbox
+        SET status = 'failed',
+            attempts = attempts + 1,
+            last_error = $2,
+            failed_at = NOW()
         WHERE id = $1 AND status = 'pending'
     `
-	_, err := r.db.Pool.Exec(ctx, query, eventID)
-	return err
+	return tx.Exec(ctx, query, eventID, lastError)
+}
+
+// ListFailed — события, исчерпавшие попытки, последние сверху; второе значение — общее количество
+func (r *outboxRepository) ListFailed(ctx context.Context, filter models.OutboxFilter) ([]*models.OutboxEvent, int, error) {
+	query := `
+        SELECT id, aggregate_id, event_type, schema_version, payload,
+               COALESCE(request_id, ''), COALESCE(traceparent, ''),
+               created_at, attempts, status, next_attempt_at, last_error, failed_at,
+               COUNT(*) OVER() AS total
+        FROM auth.outbox
+        WHERE status = 'failed'
+          AND ($1 = '' OR event_type = $1)
+        ORDER BY failed_at DESC, id
+        LIMIT $2 OFFSET $3
+    `
+
+	rows, err := r.db.Pool.Query(ctx, query, filter.EventType, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query failed events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*models.OutboxEvent, 0, filter.Limit)
+	total := 0
+	for rows.Next() {
+		var event models.OutboxEvent
+		if err := rows.Scan(
+			&event.ID, &event.AggregateID, &event.EventType, &event.SchemaVersion, &event.Payload,
+			&event.RequestID, &event.Traceparent,
+			&event.CreatedAt, &event.Attempts, &event.Status,
+			&event.NextAttemptAt, &event.LastError, &event.FailedAt, &total,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// Requeue — вернуть failed-событие в очередь с полным запасом попыток; last_error сохраняется для истории
+func (r *outboxRepository) Requeue(ctx context.Context, eventID uuid.UUID) error {
+	query := `
+        UPDATE auth.outbox
+        SET status = 'pending',
+            attempts = 0,
+            next_attempt_at = NOW(),
+            failed_at = NULL
+        WHERE id = $1 AND status = 'failed'
+    `
+	result, err := r.db.Pool.Exec(ctx, query, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to requeue event: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrOutboxEventNotFound
+	}
+	return nil
+}
+
+// RequeueAllFailed — вернуть в очередь все failed-события (например, после устранения сбоя брокера)
+func (r *outboxRepository) RequeueAllFailed(ctx context.Context) (int64, error) {
+	query := `
+        UPDATE auth.outbox
+        SET status = 'pending',
+            attempts = 0,
+            next_attempt_at = NOW(),
+            failed_at = NULL
+        WHERE status = 'failed'
+    `
+	result, err := r.db.Pool.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue events: %w", err)
+	}
+	return result.RowsAffected(), nil
 }
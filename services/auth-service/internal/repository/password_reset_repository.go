@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"auth-service/pkg/apperror"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ErrResetTokenNotFound — токен сброса не найден, истёк или уже использован
+var ErrResetTokenNotFound = apperror.NotFound("reset token not found")
+
+// PasswordResetRepository — одноразовые токены сброса пароля (Redis)
+type PasswordResetRepository interface {
+	Save(ctx context.Context, tokenHash string, userID uuid.UUID, ttl time.Duration) error
+	Peek(ctx context.Context, tokenHash string) (uuid.UUID, error)
+	Consume(ctx context.Context, tokenHash string) (uuid.UUID, error)
+}
+
+// passwordResetRepository — реализация
+type passwordResetRepository struct {
+	redis  *redis.Client
+	logger *zap.SugaredLogger
+}
+
+// NewPasswordResetRepository — конструктор
+func NewPasswordResetRepository(redis *redis.Client, logger *zap.SugaredLogger) PasswordResetRepository {
+	return &passwordResetRepository{
+		redis:  redis,
+		logger: logger,
+	}
+}
+
+// Save — сохраняет хэш токена; предыдущий токен пользователя аннулируется
+func (r *passwordResetRepository) Save(ctx context.Context, tokenHash string, userID uuid.UUID, ttl time.Duration) error {
+	userKey := "pwreset_user:" + userID.String()
+
+	prev, err := r.redis.Get(ctx, userKey).Result()
+	if err != nil && err != redis.Nil {
+		r.logger.Errorw("Failed to get previous reset token", "user_id", userID, "error", err)
+		return fmt.Errorf("get previous reset token: %w", err)
+	}
+
+	pipe := r.redis.TxPipeline()
+	if prev != "" {
+		pipe.Del(ctx, "pwreset:"+prev)
+	}
+	pipe.Set(ctx, "pwreset:"+tokenHash, userID.String(), ttl)
+	pipe.Set(ctx, userKey, tokenHash, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Errorw("Failed to store reset token", "user_id", userID, "error", err)
+		return fmt.Errorf("store reset token: %w", err)
+	}
+	return nil
+}
+
+// Peek — пользователь токена без его использования
+func (r *passwordResetRepository) Peek(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	val, err := r.redis.Get(ctx, "pwreset:"+tokenHash).Result()
+	if err == redis.Nil {
+		return uuid.Nil, ErrResetTokenNotFound
+	}
+	if err != nil {
+		r.logger.Errorw("Failed to read reset token", "error", err)
+		return uuid.Nil, fmt.Errorf("read reset token: %w", err)
+	}
+
+	userID, err := uuid.Parse(val)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid user id in reset token: %w", err)
+	}
+	return userID, nil
+}
+
+// Consume — атомарно забирает токен (GETDEL), повторное использование невозможно
+func (r *passwordResetRepository) Consume(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	val, err := r.redis.GetDel(ctx, "pwreset:"+tokenHash).Result()
+	if err == redis.Nil {
+		return uuid.Nil, ErrResetTokenNotFound
+	}
+	if err != nil {
+		r.logger.Errorw("Failed to consume reset token", "error", err)
+		return uuid.Nil, fmt.Errorf("consume reset token: %w", err)
+	}
+
+	userID, err := uuid.Parse(val)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid user id in reset token: %w", err)
+	}
+
+	r.redis.Del(ctx, "pwreset_user:"+userID.String())
+	return userID, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"auth-service/pkg/db/postgres"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// permissionsCacheTTL — права ролей меняются редко, поэтому не ходим в БД при каждой выдаче токена
+const permissionsCacheTTL = time.Minute
+
+// PermissionRepository — права ролей (auth.role_permissions)
+type PermissionRepository interface {
+	PermissionsForRole(ctx context.Context, role string) ([]string, error)
+}
+
+// permissionRepository — реализация с кэшем в памяти
+type permissionRepository struct {
+	db     *postgres.DB
+	logger *zap.SugaredLogger
+
+	mu       sync.RWMutex
+	cache    map[string][]string
+	loadedAt time.Time
+}
+
+// NewPermissionRepository — конструктор
+func NewPermissionRepository(db *postgres.DB, logger *zap.SugaredLogger) PermissionRepository {
+	return &permissionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// PermissionsForRole — права роли; неизвестная роль получает пустой список
+func (r *permissionRepository) PermissionsForRole(ctx context.Context, role string) ([]string, error) {
+	r.mu.RLock()
+	if r.cache != nil && time.Since(r.loadedAt) < permissionsCacheTTL {
+		perms := r.cache[role]
+		r.mu.RUnlock()
+		return perms, nil
+	}
+	r.mu.RUnlock()
+
+	all, err := r.loadAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache = all
+	r.loadedAt = time.Now()
+	r.mu.Unlock()
+
+	return all[role], nil
+}
+
+// loadAll — все права всех ролей
+func (r *permissionRepository) loadAll(ctx context.Context) (map[string][]string, error) {
+	query := `SELECT role, permission FROM auth.role_permissions ORDER BY role, permission`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		r.logger.Errorw("Failed to load role permissions", "error", err)
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	all := make(map[string][]string)
+	for rows.Next() {
+		var role, permission string
+		if err := rows.Scan(&role, &permission); err != nil {
+			return nil, fmt.Errorf("failed to scan permission: %w", err)
+		}
+		all[role] = append(all[role], permission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return all, nil
+}
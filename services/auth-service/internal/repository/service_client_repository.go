@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
+	"auth-service/pkg/db/postgres"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrServiceClientNotFound — клиент не зарегистрирован
+	ErrServiceClientNotFound = apperror.NotFound("service client not found")
+	// ErrServiceClientExists — клиент с таким client_id уже есть
+	ErrServiceClientExists = apperror.Conflict("service client already exists")
+)
+
+// ServiceClientRepository — сервисные клиенты (auth.service_clients)
+type ServiceClientRepository interface {
+	Create(ctx context.Context, client *models.ServiceClient) error
+	GetByID(ctx context.Context, clientID string) (*models.ServiceClient, error)
+	List(ctx context.Context) ([]models.ServiceClient, error)
+	UpdateSecret(ctx context.Context, clientID, secretHash string) error
+	SetDisabled(ctx context.Context, clientID string, disabled bool) error
+}
+
+// serviceClientRepository — реализация
+type serviceClientRepository struct {
+	db     *postgres.DB
+	logger *zap.SugaredLogger
+}
+
+// NewServiceClientRepository — конструктор
+func NewServiceClientRepository(db *postgres.DB, logger *zap.SugaredLogger) ServiceClientRepository {
+	return &serviceClientRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create — регистрирует клиента
+func (r *serviceClientRepository) Create(ctx context.Context, client *models.ServiceClient) error {
+	query := `
+		INSERT INTO auth.service_clients (client_id, name, secret_hash, scopes, audiences, disabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	if err := r.db.Exec(ctx, query,
+		client.ClientID, client.Name, client.SecretHash, client.Scopes, client.Audiences,
+		client.Disabled, client.CreatedAt, client.UpdatedAt,
+	); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrServiceClientExists
+		}
+		r.logger.Errorw("Failed to create service client", "client_id", client.ClientID, "error", err)
+		return fmt.Errorf("failed to insert service client: %w", err)
+	}
+	return nil
+}
+
+// GetByID — клиент по client_id
+func (r *serviceClientRepository) GetByID(ctx context.Context, clientID string) (*models.ServiceClient, error) {
+	query := `
+		SELECT client_id, name, secret_hash, scopes, audiences, disabled, created_at, updated_at
+		FROM auth.service_clients
+		WHERE client_id = $1
+	`
+
+	var c models.ServiceClient
+	if err := r.db.QueryRow(ctx, query, clientID).Scan(
+		&c.ClientID, &c.Name, &c.SecretHash, &c.Scopes, &c.Audiences, &c.Disabled, &c.CreatedAt, &c.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrServiceClientNotFound
+		}
+		r.logger.Errorw("DB error on service client lookup", "client_id", clientID, "error", err)
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	return &c, nil
+}
+
+// List — все клиенты
+func (r *serviceClientRepository) List(ctx context.Context) ([]models.ServiceClient, error) {
+	query := `
+		SELECT client_id, name, secret_hash, scopes, audiences, disabled, created_at, updated_at
+		FROM auth.service_clients
+		ORDER BY client_id
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		r.logger.Errorw("Failed to list service clients", "error", err)
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var clients []models.ServiceClient
+	for rows.Next() {
+		var c models.ServiceClient
+		if err := rows.Scan(
+			&c.ClientID, &c.Name, &c.SecretHash, &c.Scopes, &c.Audiences, &c.Disabled, &c.CreatedAt, &c.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan service client: %w", err)
+		}
+		clients = append(clients, c)
+	}
+	return clients, rows.Err()
+}
+
+// UpdateSecret — ротация секрета клиента
+func (r *serviceClientRepository) UpdateSecret(ctx context.Context, clientID, secretHash string) error {
+	query := `UPDATE auth.service_clients SET secret_hash = $2, updated_at = NOW() WHERE client_id = $1`
+	return r.update(ctx, query, clientID, secretHash)
+}
+
+// SetDisabled — отключает или включает клиента (уже выданные токены живут до истечения)
+func (r *serviceClientRepository) SetDisabled(ctx context.Context, clientID string, disabled bool) error {
+	query := `UPDATE auth.service_clients SET disabled = $2, updated_at = NOW() WHERE client_id = $1`
+	return r.update(ctx, query, clientID, disabled)
+}
+
+// update — UPDATE одного клиента с проверкой, что он существует
+func (r *serviceClientRepository) update(ctx context.Context, query, clientID string, value any) error {
+	tag, err := r.db.Pool.Exec(ctx, query, clientID, value)
+	if err != nil {
+		r.logger.Errorw("Failed to update service client", "client_id", clientID, "error", err)
+		return fmt.Errorf("update failed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrServiceClientNotFound
+	}
+	return nil
+}
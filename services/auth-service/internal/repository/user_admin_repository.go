@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"auth-service/internal/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// List — поиск пользователей с фильтрами и пагинацией; второе значение — общее количество
+func (r *userRepository) List(ctx context.Context, filter models.UserFilter) ([]*models.User, int, error) {
+	query := `
+		SELECT id, email, role, status, is_verified, last_login_at, created_at, updated_at,
+		       COUNT(*) OVER() AS total
+		FROM auth.users
+		WHERE ($1 = '' OR email ILIKE '%' || $1 || '%')
+		  AND ($2 = '' OR status = $2)
+		  AND ($3 = '' OR role = $3)
+		ORDER BY created_at DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, filter.Query, filter.Status, filter.Role, filter.Limit, filter.Offset)
+	if err != nil {
+		r.logger.Errorw("Failed to list users", "error", err)
+		return nil, 0, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0, filter.Limit)
+	total := 0
+	for rows.Next() {
+		user := &models.User{}
+		if err := rows.Scan(
+			&user.ID, &user.Email, &user.Role, &user.Status, &user.IsVerified,
+			&user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt, &total,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows error: %w", err)
+	}
+
+	return users, total, nil
+}
+
+// GetByIDForUpdateTx — пользователь с блокировкой строки до конца транзакции
+func (r *userRepository) GetByIDForUpdateTx(ctx context.Context, tx Tx, id uuid.UUID) (*models.User, error) {
+	query := `
+		SELECT id, email, role, status, is_verified, last_login_at, created_at, updated_at
+		FROM auth.users WHERE id = $1
+		FOR UPDATE
+	`
+
+	user := &models.User{}
+	err := tx.QueryRow(ctx, query, id).Scan(
+		&user.ID, &user.Email, &user.Role, &user.Status, &user.IsVerified,
+		&user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		r.logger.Errorw("DB error on GetByIDForUpdateTx", "user_id", id, "error", err)
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	return user, nil
+}
+
+// UpdateStatusTx — смена статуса (active, blocked, banned)
+func (r *userRepository) UpdateStatusTx(ctx context.Context, tx Tx, id uuid.UUID, status string) error {
+	query := `UPDATE auth.users SET status = $2 WHERE id = $1`
+	if err := tx.Exec(ctx, query, id, status); err != nil {
+		r.logger.Errorw("Failed to update user status", "user_id", id, "status", status, "error", err)
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	return nil
+}
+
+// UpdateRoleTx — смена роли (user, admin)
+func (r *userRepository) UpdateRoleTx(ctx context.Context, tx Tx, id uuid.UUID, role string) error {
+	query := `UPDATE auth.users SET role = $2 WHERE id = $1`
+	if err := tx.Exec(ctx, query, id, role); err != nil {
+		r.logger.Errorw("Failed to update user role", "user_id", id, "role", role, "error", err)
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// MarkDeletedTx — мягкое удаление: вход и обновление токенов запрещены, строка остаётся до конца саги
+func (r *userRepository) MarkDeletedTx(ctx context.Context, tx Tx, id uuid.UUID) error {
+	query := `UPDATE auth.users SET status = 'deleted', deleted_at = NOW() WHERE id = $1`
+	if err := tx.Exec(ctx, query, id); err != nil {
+		r.logger.Errorw("Failed to mark user as deleted", "user_id", id, "error", err)
+		return fmt.Errorf("failed to mark user as deleted: %w", err)
+	}
+	return nil
+}
+
+// HardDeleteTx — окончательное удаление (2FA и внешние аккаунты удаляются каскадом)
+func (r *userRepository) HardDeleteTx(ctx context.Context, tx Tx, id uuid.UUID) error {
+	query := `DELETE FROM auth.users WHERE id = $1 AND status = 'deleted'`
+	if err := tx.Exec(ctx, query, id); err != nil {
+		r.logger.Errorw("Failed to hard delete user", "user_id", id, "error", err)
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"auth-service/pkg/apperror"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrEmailTaken — email уже принадлежит другому пользователю
+var ErrEmailTaken = apperror.Conflict("email already in use")
+
+// isEmailConflict — нарушение уникальности users_email_key
+func isEmailConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "users_email_key"
+}
+
+// UpdateEmailTx — смена email: новый адрес подтверждён ссылкой, поэтому is_verified = TRUE.
+// Незавершённое подтверждение старого адреса сбрасывается.
+func (r *userRepository) UpdateEmailTx(ctx context.Context, tx Tx, id uuid.UUID, email string) error {
+	query := `
+		UPDATE auth.users
+		SET email = $2,
+		    is_verified = TRUE,
+		    email_verify_token = NULL,
+		    email_verify_sent_at = NULL
+		WHERE id = $1
+	`
+	if err := tx.Exec(ctx, query, id, email); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrEmailTaken
+		}
+		r.logger.Errorw("Failed to update user email", "user_id", id, "error", err)
+		return fmt.Errorf("failed to update email: %w", err)
+	}
+	return nil
+}
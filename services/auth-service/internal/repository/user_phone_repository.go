@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrPhoneTaken — номер уже подтверждён другим пользователем
+var ErrPhoneTaken = apperror.Conflict("phone already in use")
+
+// isPhoneConflict — нарушение уникальности подтверждённых номеров (users_phone_verified_key)
+func isPhoneConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "users_phone_verified_key"
+}
+
+// GetByPhone — владелец подтверждённого номера (E.164). Неподтверждённый номер
+// может быть указан у нескольких аккаунтов и никому не принадлежит.
+func (r *userRepository) GetByPhone(ctx context.Context, phone string) (*models.User, error) {
+	query := `
+		SELECT id, email, password_hash, role, status, is_verified, email_verify_sent_at,
+		       last_login_at, created_at, updated_at, phone, phone_verified
+		FROM auth.users WHERE phone = $1 AND phone_verified
+	`
+
+	user := &models.User{}
+	err := r.db.QueryRow(ctx, query, phone).Scan(
+		&user.ID, &user.Email, &user.PasswordHash, &user.Role, &user.Status,
+		&user.IsVerified, &user.EmailVerifySentAt, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
+		&user.Phone, &user.PhoneVerified,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Infow("User not found by phone")
+			return nil, ErrUserNotFound
+		}
+		r.logger.Errorw("DB error on GetByPhone", "error", err)
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	return user, nil
+}
+
+// SetPhone — новый номер; подтверждение предыдущего сбрасывается (кроме повторной установки того же номера).
+// Номер не проверяется на занятость: он закрепляется за аккаунтом только в MarkPhoneVerified.
+func (r *userRepository) SetPhone(ctx context.Context, id uuid.UUID, phone string) error {
+	query := `
+		UPDATE auth.users
+		SET phone = $2,
+		    phone_verified = phone_verified AND phone IS NOT DISTINCT FROM $2
+		WHERE id = $1
+	`
+	if err := r.db.Exec(ctx, query, id, phone); err != nil {
+		r.logger.Errorw("Failed to set user phone", "user_id", id, "error", err)
+		return fmt.Errorf("failed to set phone: %w", err)
+	}
+	return nil
+}
+
+// MarkPhoneVerified — подтверждает номер, если он не сменился с момента отправки кода,
+// и снимает его с других аккаунтов, где он указан без подтверждения.
+// false — номер у пользователя уже другой; ErrPhoneTaken — номер подтверждён другим аккаунтом.
+func (r *userRepository) MarkPhoneVerified(ctx context.Context, id uuid.UUID, phone string) (bool, error) {
+	query := `
+		WITH verified AS (
+			UPDATE auth.users SET phone_verified = TRUE
+			WHERE id = $1 AND phone = $2
+			RETURNING id
+		), released AS (
+			UPDATE auth.users SET phone = NULL
+			WHERE phone = $2 AND id <> $1 AND NOT phone_verified
+			  AND EXISTS (SELECT 1 FROM verified)
+		)
+		SELECT id FROM verified
+	`
+
+	var updated uuid.UUID
+	if err := r.db.QueryRow(ctx, query, id, phone).Scan(&updated); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		if isPhoneConflict(err) {
+			return false, ErrPhoneTaken
+		}
+		r.logger.Errorw("Failed to mark phone as verified", "user_id", id, "error", err)
+		return false, fmt.Errorf("failed to verify phone: %w", err)
+	}
+	return true, nil
+}
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"auth-service/internal/middleware"
+	"auth-service/internal/models"
 	"auth-service/internal/transport/http/handlers"
 	"auth-service/internal/utils"
 
@@ -21,6 +22,23 @@ func SetupAuthRoutes(router *echo.Echo, authHandler *handlers.AuthHandler, token
 		auth.POST("/register", authHandler.RegisterUser)
 		auth.POST("/login", authHandler.Login)
 		auth.POST("/refresh-token", authHandler.RefreshToken)
+		auth.POST("/verify-email", authHandler.VerifyEmail)
+		auth.POST("/resend-verification", authHandler.ResendVerification)
+		auth.POST("/password/forgot", authHandler.ForgotPassword)
+		auth.POST("/password/reset", authHandler.ResetPassword)
+		// Вход без пароля по одноразовой ссылке из письма
+		auth.POST("/magic-link", authHandler.RequestMagicLink)
+		auth.POST("/magic-link/consume", authHandler.ConsumeMagicLink)
+		// Вход по одноразовому коду из SMS (нужен подтверждённый номер)
+		auth.POST("/otp/request", authHandler.RequestOTP)
+		auth.POST("/otp/verify", authHandler.VerifyOTP)
+		// Подтверждение нового email по ссылке из письма
+		auth.POST("/email/confirm", authHandler.ConfirmEmailChange)
+		// Второй шаг входа при включённой 2FA
+		auth.POST("/2fa/verify", authHandler.VerifyMFA)
+		// Вход через внешних провайдеров (google, yandex, vk, mock)
+		auth.GET("/oauth/:provider/start", authHandler.StartOAuth)
+		auth.GET("/oauth/:provider/callback", authHandler.OAuthCallback)
 
 		// --- ЗАЩИЩЕННЫЕ ЭНДПОИНТЫ ---
 		// Создаем подгруппу, к которой применяем AuthMiddleware
@@ -30,8 +48,92 @@ func SetupAuthRoutes(router *echo.Echo, authHandler *handlers.AuthHandler, token
 			// POST /api/v1/auth/logout -> Требует валидный Access Token
 			protected.POST("/logout", authHandler.Logout)
 
+			// POST /api/v1/auth/password/change -> Смена пароля (остальные сессии отзываются)
+			protected.POST("/password/change", authHandler.ChangePassword)
+
+			// POST /api/v1/auth/email/change -> Смена email (требует текущий пароль, подтверждается письмом)
+			protected.POST("/email/change", authHandler.ChangeEmail)
+
+			// Привязка и подтверждение номера телефона кодом из SMS
+			protected.POST("/phone", authHandler.SetPhone)
+			protected.POST("/phone/verify", authHandler.VerifyPhone)
+
+			// Активные сессии (устройства) пользователя
+			protected.GET("/sessions", authHandler.ListSessions)
+			protected.DELETE("/sessions/:jti", authHandler.RevokeSession)
+			protected.DELETE("/sessions", authHandler.RevokeAllSessions)
+
+			// Подключение TOTP 2FA
+			protected.POST("/2fa/setup", authHandler.SetupMFA)
+			protected.POST("/2fa/enable", authHandler.EnableMFA)
+
 			// GET /api/v1/auth/validate -> Эндпоинт для Nginx (auth_request)
 			protected.GET("/validate", authHandler.Validate)
 		}
 	}
 }
+
+func SetupJWKSRoutes(router *echo.Echo, jwksHandler *handlers.JWKSHandler) {
+	// GET /.well-known/jwks.json -> Публичные ключи для проверки JWT другими сервисами
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+}
+
+func SetupTokenRoutes(router *echo.Echo, tokenHandler *handlers.TokenHandler) {
+	// POST /api/v1/auth/token -> Машинные токены для сервисов (OAuth2 client-credentials)
+	router.POST("/api/v1/auth/token", tokenHandler.IssueToken)
+}
+
+func SetupAdminRoutes(router *echo.Echo, adminHandler *handlers.AdminHandler, tokenSvc utils.TokenService, logger *zap.SugaredLogger) {
+	// Все эндпоинты админки требуют access токен с ролью admin
+	admin := router.Group("/api/v1/admin")
+	admin.Use(middleware.AuthMiddleware(tokenSvc, logger))
+	admin.Use(middleware.RequireRole(models.RoleAdmin))
+	{
+		canRead := middleware.RequirePermission(models.PermUsersRead)
+		canBan := middleware.RequirePermission(models.PermUsersBan)
+		canManageRoles := middleware.RequirePermission(models.PermUsersManageRoles)
+
+		admin.GET("/users", adminHandler.ListUsers, canRead)
+		admin.GET("/users/:id", adminHandler.GetUser, canRead)
+		admin.POST("/users/:id/block", adminHandler.BlockUser, canBan)
+		admin.POST("/users/:id/ban", adminHandler.BanUser, canBan)
+		admin.POST("/users/:id/unblock", adminHandler.UnblockUser, canBan)
+		admin.PUT("/users/:id/role", adminHandler.ChangeRole, canManageRoles)
+	}
+}
+
+func SetupAccountRoutes(router *echo.Echo, accountHandler *handlers.AccountHandler, tokenSvc utils.TokenService, logger *zap.SugaredLogger) {
+	me := router.Group("/api/v1/auth/me")
+	me.Use(middleware.AuthMiddleware(tokenSvc, logger))
+	{
+		// DELETE /api/v1/auth/me -> Удаление аккаунта (сага по всем сервисам)
+		me.DELETE("", accountHandler.DeleteAccount)
+	}
+}
+
+func SetupAuditRoutes(router *echo.Echo, auditHandler *handlers.AuditHandler, tokenSvc utils.TokenService, logger *zap.SugaredLogger) {
+	// GET /api/v1/auth/me/activity -> Собственная история входов и изменений аккаунта
+	me := router.Group("/api/v1/auth/me")
+	me.Use(middleware.AuthMiddleware(tokenSvc, logger))
+	me.GET("/activity", auditHandler.MyActivity)
+
+	// GET /api/v1/admin/audit -> Журнал аутентификации по всем пользователям
+	admin := router.Group("/api/v1/admin")
+	admin.Use(middleware.AuthMiddleware(tokenSvc, logger))
+	admin.Use(middleware.RequireRole(models.RoleAdmin))
+	admin.GET("/audit", auditHandler.ListAudit, middleware.RequirePermission(models.PermAuditRead))
+}
+
+func SetupOutboxRoutes(router *echo.Echo, outboxHandler *handlers.OutboxHandler, tokenSvc utils.TokenService, logger *zap.SugaredLogger) {
+	admin := router.Group("/api/v1/admin/outbox")
+	admin.Use(middleware.AuthMiddleware(tokenSvc, logger))
+	admin.Use(middleware.RequireRole(models.RoleAdmin))
+	admin.Use(middleware.RequirePermission(models.PermOutboxManage))
+
+	// GET /api/v1/admin/outbox/failed -> События, исчерпавшие попытки публикации
+	admin.GET("/failed", outboxHandler.ListFailed)
+	// POST /api/v1/admin/outbox/failed/requeue -> Вернуть в очередь все failed-события
+	admin.POST("/failed/requeue", outboxHandler.RequeueAllFailed)
+	// POST /api/v1/admin/outbox/:id/requeue -> Вернуть в очередь одно событие
+	admin.POST("/:id/requeue", outboxHandler.Requeue)
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// staleDeletionsBatch — сколько незавершённых саг переотправляется за один проход
+const staleDeletionsBatch = 100
+
+// AccountDeletionConfig — параметры саги удаления аккаунта
+type AccountDeletionConfig struct {
+	RequiredAcks  []string      // Сервисы, которые должны подтвердить удаление своих данных
+	RetryInterval time.Duration // Через сколько без подтверждений UserDeleted отправляется повторно
+}
+
+// AccountDeletionService — удаление аккаунта пользователем.
+// Шаги саги: мягкое удаление + UserDeleted в outbox → сервисы стирают данные и
+// присылают UserDeletionAck → после всех подтверждений пользователь удаляется окончательно.
+type AccountDeletionService interface {
+	DeleteAccount(ctx context.Context, userID uuid.UUID) error
+	ConfirmStep(ctx context.Context, ack models.UserDeletionAck) error
+	RetryPending(ctx context.Context) (int, error)
+}
+
+type accountDeletionService struct {
+	userRepo     repository.UserRepository
+	outboxRepo   repository.OutboxRepository
+	deletionRepo repository.AccountDeletionRepository
+	tokenSvc     utils.TokenService
+	cfg          AccountDeletionConfig
+	logger       *zap.SugaredLogger
+}
+
+// NewAccountDeletionService — конструктор
+func NewAccountDeletionService(
+	userRepo repository.UserRepository,
+	outboxRepo repository.OutboxRepository,
+	deletionRepo repository.AccountDeletionRepository,
+	tokenSvc utils.TokenService,
+	cfg AccountDeletionConfig,
+	logger *zap.SugaredLogger,
+) AccountDeletionService {
+	return &accountDeletionService{
+		userRepo:     userRepo,
+		outboxRepo:   outboxRepo,
+		deletionRepo: deletionRepo,
+		tokenSvc:     tokenSvc,
+		cfg:          cfg,
+		logger:       logger,
+	}
+}
+
+// DeleteAccount — мягкое удаление, отзыв всех сессий и старт саги
+func (s *accountDeletionService) DeleteAccount(ctx context.Context, userID uuid.UUID) (err error) {
+	log := s.logger.With("user_id", userID)
+
+	tx, err := s.userRepo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				log.Errorw("Failed to rollback transaction", "error", rollbackErr)
+			}
+		}
+	}()
+
+	user, err := s.userRepo.GetByIDForUpdateTx(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Status == models.UserStatusDeleted {
+		err = repository.ErrUserNotFound
+		return err
+	}
+
+	if err = s.userRepo.MarkDeletedTx(ctx, tx, userID); err != nil {
+		return err
+	}
+	if err = s.deletionRepo.CreateTx(ctx, tx, userID); err != nil {
+		return err
+	}
+	if err = s.insertUserDeletedTx(ctx, tx, userID, time.Now().UTC()); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Аккаунт уже помечен удалённым: вход и refresh отклоняются по статусу,
+	// поэтому ошибка отзыва не отменяет удаление
+	if revokeErr := s.tokenSvc.RevokeAllForUser(ctx, userID); revokeErr != nil {
+		log.Errorw("Failed to revoke sessions of deleted user", "error", revokeErr)
+	}
+
+	log.Infow("Account deletion requested", "required_acks", s.cfg.RequiredAcks)
+	return nil
+}
+
+// ConfirmStep — подтверждение от сервиса; после последнего подтверждения пользователь удаляется окончательно
+func (s *accountDeletionService) ConfirmStep(ctx context.Context, ack models.UserDeletionAck) (err error) {
+	log := s.logger.With("user_id", ack.UserID, "service", ack.Service)
+
+	if !slices.Contains(s.cfg.RequiredAcks, ack.Service) {
+		log.Warnw("Ignoring deletion ack from unexpected service")
+		return nil
+	}
+
+	tx, err := s.userRepo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				log.Errorw("Failed to rollback transaction", "error", rollbackErr)
+			}
+		}
+	}()
+
+	acked, completed, err := s.deletionRepo.AckTx(ctx, tx, ack.UserID, ack.Service)
+	if err != nil {
+		if errors.Is(err, repository.ErrDeletionNotFound) {
+			log.Warnw("Deletion ack for unknown account deletion")
+			err = nil
+			return tx.Rollback(ctx)
+		}
+		return err
+	}
+	if completed {
+		// Повторная доставка после завершения саги
+		return tx.Commit(ctx)
+	}
+
+	pending := slices.DeleteFunc(slices.Clone(s.cfg.RequiredAcks), func(svc string) bool {
+		return slices.Contains(acked, svc)
+	})
+	if len(pending) == 0 {
+		if err = s.userRepo.HardDeleteTx(ctx, tx, ack.UserID); err != nil {
+			return err
+		}
+		if err = s.deletionRepo.CompleteTx(ctx, tx, ack.UserID); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if len(pending) == 0 {
+		log.Infow("Account erased: all services confirmed")
+	} else {
+		log.Infow("Account deletion step confirmed", "pending", pending)
+	}
+	return nil
+}
+
+// RetryPending — повторно отправляет UserDeleted по сагам без подтверждений дольше RetryInterval
+// (подтверждение могло потеряться; обработчики в сервисах идемпотентны)
+func (s *accountDeletionService) RetryPending(ctx context.Context) (int, error) {
+	deletions, err := s.deletionRepo.ListStale(ctx, time.Now().Add(-s.cfg.RetryInterval), staleDeletionsBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	retried := 0
+	for _, d := range deletions {
+		if err := s.reemit(ctx, d); err != nil {
+			s.logger.Errorw("Failed to re-emit UserDeleted", "user_id", d.UserID, "error", err)
+			continue
+		}
+		retried++
+	}
+	return retried, nil
+}
+
+func (s *accountDeletionService) reemit(ctx context.Context, d models.AccountDeletion) (err error) {
+	tx, err := s.userRepo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Errorw("Failed to rollback transaction", "error", rollbackErr)
+			}
+		}
+	}()
+
+	if err = s.insertUserDeletedTx(ctx, tx, d.UserID, d.RequestedAt); err != nil {
+		return err
+	}
+	if err = s.deletionRepo.TouchEmittedTx(ctx, tx, d.UserID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// insertUserDeletedTx — событие UserDeleted в транзакции саги
+func (s *accountDeletionService) insertUserDeletedTx(ctx context.Context, tx repository.Tx, userID uuid.UUID, deletedAt time.Time) error {
+	outboxEvent, err := newOutboxEvent(ctx, "UserDeleted", userID, models.UserDeleted{UserID: userID, DeletedAt: deletedAt})
+	if err != nil {
+		return err
+	}
+	if err := s.outboxRepo.InsertTx(ctx, tx, outboxEvent); err != nil {
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"auth-service/pkg/apperror"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const defaultUserListLimit = 20
+
+var (
+	// ErrAccountDisabled — аккаунт заблокирован или забанен администратором
+	ErrAccountDisabled = apperror.Forbidden("account is disabled")
+	// ErrSelfModification — администратор пытается изменить собственный статус или роль
+	ErrSelfModification = apperror.Conflict("cannot change own status or role")
+)
+
+// AdminService — управление пользователями администраторами
+type AdminService interface {
+	ListUsers(ctx context.Context, filter models.UserFilter) (*models.UserList, error)
+	GetUser(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	SetUserStatus(ctx context.Context, adminID, userID uuid.UUID, status, reason string) (*models.User, error)
+	ChangeUserRole(ctx context.Context, adminID, userID uuid.UUID, role string) (*models.User, error)
+}
+
+type adminService struct {
+	userRepo   repository.UserRepository
+	outboxRepo repository.OutboxRepository
+	tokenSvc   utils.TokenService
+	audit      AuditRecorder
+	logger     *zap.SugaredLogger
+}
+
+// NewAdminService — конструктор
+func NewAdminService(
+	userRepo repository.UserRepository,
+	outboxRepo repository.OutboxRepository,
+	tokenSvc utils.TokenService,
+	audit AuditRecorder,
+	logger *zap.SugaredLogger,
+) AdminService {
+	return &adminService{
+		userRepo:   userRepo,
+		outboxRepo: outboxRepo,
+		tokenSvc:   tokenSvc,
+		audit:      audit,
+		logger:     logger,
+	}
+}
+
+// ListUsers — поиск пользователей с фильтрами
+func (s *adminService) ListUsers(ctx context.Context, filter models.UserFilter) (*models.UserList, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultUserListLimit
+	}
+
+	users, total, err := s.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return &models.UserList{Users: users, Total: total, Limit: filter.Limit, Offset: filter.Offset}, nil
+}
+
+// GetUser — карточка пользователя
+func (s *adminService) GetUser(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	return s.userRepo.GetByID(ctx, userID)
+}
+
+// SetUserStatus — блокировка, бан или разблокировка.
+// При блокировке все сессии пользователя отзываются, чтобы выданные токены перестали работать сразу.
+func (s *adminService) SetUserStatus(ctx context.Context, adminID, userID uuid.UUID, status, reason string) (*models.User, error) {
+	user, err := s.updateUser(ctx, adminID, userID, reason, func(tx repository.Tx, user *models.User) error {
+		if user.Status == status {
+			return nil
+		}
+		if err := s.userRepo.UpdateStatusTx(ctx, tx, user.ID, status); err != nil {
+			return err
+		}
+		user.Status = status
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if status != models.UserStatusActive {
+		if err := s.tokenSvc.RevokeAllForUser(ctx, user.ID); err != nil {
+			s.logger.Errorw("Failed to revoke sessions of disabled user", "user_id", user.ID, "error", err)
+			return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+	}
+
+	return user, nil
+}
+
+// ChangeUserRole — смена роли. Новая роль попадёт в токены при следующем обновлении.
+func (s *adminService) ChangeUserRole(ctx context.Context, adminID, userID uuid.UUID, role string) (*models.User, error) {
+	return s.updateUser(ctx, adminID, userID, "", func(tx repository.Tx, user *models.User) error {
+		if user.Role == role {
+			return nil
+		}
+		if err := s.userRepo.UpdateRoleTx(ctx, tx, user.ID, role); err != nil {
+			return err
+		}
+		user.Role = role
+		return nil
+	})
+}
+
+// updateUser — изменение пользователя под блокировкой строки + событие UserStatusChanged в той же транзакции
+func (s *adminService) updateUser(
+	ctx context.Context,
+	adminID, userID uuid.UUID,
+	reason string,
+	apply func(tx repository.Tx, user *models.User) error,
+) (user *models.User, err error) {
+	if adminID == userID {
+		return nil, ErrSelfModification
+	}
+	log := s.logger.With("admin_id", adminID, "user_id", userID)
+
+	tx, err := s.userRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				log.Errorw("Failed to rollback transaction", "error", rollbackErr)
+			}
+		}
+	}()
+
+	user, err = s.userRepo.GetByIDForUpdateTx(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == models.UserStatusDeleted {
+		// Удалённый аккаунт не восстанавливается через админку
+		err = repository.ErrUserNotFound
+		return nil, err
+	}
+	previousStatus, previousRole := user.Status, user.Role
+
+	if err = apply(tx, user); err != nil {
+		return nil, err
+	}
+
+	if user.Status == previousStatus && user.Role == previousRole {
+		// Ничего не изменилось — событие не нужно
+		err = tx.Commit(ctx)
+		return user, err
+	}
+
+	outboxEvent, err := newOutboxEvent(ctx, "UserStatusChanged", user.ID, models.UserStatusChanged{
+		UserID:         user.ID,
+		Email:          user.Email,
+		Status:         user.Status,
+		PreviousStatus: previousStatus,
+		Role:           user.Role,
+		PreviousRole:   previousRole,
+		Reason:         reason,
+		ChangedBy:      adminID,
+		ChangedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = s.outboxRepo.InsertTx(ctx, tx, outboxEvent); err != nil {
+		return nil, fmt.Errorf("failed to create outbox event: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Infow("User updated by admin",
+		"status", user.Status,
+		"previous_status", previousStatus,
+		"role", user.Role,
+		"previous_role", previousRole,
+		"outbox_event_id", outboxEvent.ID,
+	)
+
+	if user.Status != previousStatus {
+		entry := userAuditEntry(models.AuditStatusChanged, user.ID, user.Email)
+		entry.Metadata = auditMetadata(map[string]any{
+			"status": user.Status, "previous_status": previousStatus, "reason": reason, "changed_by": adminID,
+		})
+		s.audit.Record(ctx, entry)
+	}
+	if user.Role != previousRole {
+		entry := userAuditEntry(models.AuditRoleChanged, user.ID, user.Email)
+		entry.Metadata = auditMetadata(map[string]any{
+			"role": user.Role, "previous_role": previousRole, "changed_by": adminID,
+		})
+		s.audit.Record(ctx, entry)
+	}
+	return user, nil
+}
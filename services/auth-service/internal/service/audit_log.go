@@ -0,0 +1,221 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// auditFlushTimeout — сколько ждём записи остатка буфера при остановке сервиса
+const auditFlushTimeout = 5 * time.Second
+
+const defaultAuditListLimit = 50
+
+// AuditRecorder — запись событий в журнал аутентификации.
+// Record не блокирует запрос: запись уходит в буфер и сохраняется фоново.
+type AuditRecorder interface {
+	Record(ctx context.Context, entry models.AuditEntry)
+}
+
+// AuditConfig — настройки фоновой записи журнала
+type AuditConfig struct {
+	BufferSize    int           // Ёмкость очереди; при переполнении записи отбрасываются с предупреждением
+	BatchSize     int           // Максимальный размер пачки для одного COPY
+	FlushInterval time.Duration // Как часто сбрасывать неполную пачку
+}
+
+// AuditLogger — буферизованная пакетная запись журнала в Postgres
+type AuditLogger struct {
+	repo   repository.AuditRepository
+	cfg    AuditConfig
+	queue  chan models.AuditEntry
+	done   chan struct{}
+	logger *zap.SugaredLogger
+}
+
+// NewAuditLogger — конструктор
+func NewAuditLogger(repo repository.AuditRepository, cfg AuditConfig, logger *zap.SugaredLogger) *AuditLogger {
+	return &AuditLogger{
+		repo:   repo,
+		cfg:    cfg,
+		queue:  make(chan models.AuditEntry, cfg.BufferSize),
+		done:   make(chan struct{}),
+		logger: logger,
+	}
+}
+
+// Record — ставит запись в очередь; IP, User-Agent и request_id берутся из контекста, если не заданы
+func (a *AuditLogger) Record(ctx context.Context, entry models.AuditEntry) {
+	client := utils.ClientInfoFromContext(ctx)
+	if entry.IP == "" {
+		entry.IP = client.IP
+	}
+	if entry.UserAgent == "" {
+		entry.UserAgent = client.UserAgent
+	}
+	if entry.RequestID == "" {
+		entry.RequestID = client.RequestID
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	select {
+	case a.queue <- entry:
+	default:
+		a.logger.Warnw("Audit buffer is full, entry dropped",
+			"event_type", entry.EventType, "user_id", entry.UserID, "request_id", entry.RequestID)
+	}
+}
+
+// Start — запускает фоновую запись; после отмены ctx остаток буфера сохраняется и закрывается Done
+func (a *AuditLogger) Start(ctx context.Context) {
+	go a.run(ctx)
+}
+
+// Done — закрывается, когда остаток буфера записан после остановки
+func (a *AuditLogger) Done() <-chan struct{} {
+	return a.done
+}
+
+func (a *AuditLogger) run(ctx context.Context) {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*models.AuditEntry, 0, a.cfg.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			// Забираем всё, что успело попасть в очередь, и пишем уже без отменённого контекста
+		drain:
+			for {
+				select {
+				case entry := <-a.queue:
+					batch = append(batch, &entry)
+				default:
+					break drain
+				}
+			}
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditFlushTimeout)
+			a.flush(flushCtx, batch)
+			cancel()
+			return
+		case entry := <-a.queue:
+			batch = append(batch, &entry)
+			if len(batch) >= a.cfg.BatchSize {
+				a.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				a.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// flush — пишет пачку частями по BatchSize; ошибка записи не останавливает запись следующих пачек
+func (a *AuditLogger) flush(ctx context.Context, batch []*models.AuditEntry) {
+	for start := 0; start < len(batch); start += a.cfg.BatchSize {
+		end := min(start+a.cfg.BatchSize, len(batch))
+		if err := a.repo.InsertBatch(ctx, batch[start:end]); err != nil {
+			a.logger.Errorw("Failed to write audit entries", "count", end-start, "error", err)
+		}
+	}
+}
+
+// auditMetadata — подробности события в JSON (nil, если подробностей нет)
+func auditMetadata(fields map[string]any) json.RawMessage {
+	if len(fields) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// userAuditEntry — запись о событии конкретного пользователя
+func userAuditEntry(eventType string, userID uuid.UUID, email string) models.AuditEntry {
+	return models.AuditEntry{UserID: &userID, Email: email, EventType: eventType, Success: true}
+}
+
+// Способы входа (metadata.method в записях login_success)
+const (
+	loginMethodPassword  = "password"
+	loginMethodMagicLink = "magic_link"
+	loginMethodOAuth     = "oauth:" // + провайдер
+	loginMethodMFA       = "mfa"
+	loginMethodOTP       = "sms_otp"
+)
+
+// auditLoginSuccess — успешный вход с выдачей токенов
+func (s *authService) auditLoginSuccess(ctx context.Context, user *models.User, jti, method string) {
+	entry := userAuditEntry(models.AuditLoginSuccess, user.ID, user.Email)
+	entry.JTI = jti
+	entry.Metadata = auditMetadata(map[string]any{"method": method})
+	s.audit.Record(ctx, entry)
+}
+
+// auditLoginFailure — неудачный вход; user == nil, если email не зарегистрирован
+func (s *authService) auditLoginFailure(ctx context.Context, user *models.User, email, reason string) {
+	entry := models.AuditEntry{
+		Email:     email,
+		EventType: models.AuditLoginFailure,
+		Metadata:  auditMetadata(map[string]any{"reason": reason}),
+	}
+	if user != nil {
+		entry.UserID = &user.ID
+	}
+	s.audit.Record(ctx, entry)
+}
+
+// AuditService — чтение журнала аутентификации
+type AuditService interface {
+	ListForUser(ctx context.Context, userID uuid.UUID, filter models.AuditFilter) (*models.AuditList, error)
+	List(ctx context.Context, filter models.AuditFilter) (*models.AuditList, error)
+}
+
+// auditService — реализация
+type auditService struct {
+	repo   repository.AuditRepository
+	logger *zap.SugaredLogger
+}
+
+// NewAuditService — конструктор
+func NewAuditService(repo repository.AuditRepository, logger *zap.SugaredLogger) AuditService {
+	return &auditService{repo: repo, logger: logger}
+}
+
+// ListForUser — собственная активность пользователя (фильтр по email недоступен)
+func (s *auditService) ListForUser(ctx context.Context, userID uuid.UUID, filter models.AuditFilter) (*models.AuditList, error) {
+	filter.UserID = userID.String()
+	filter.Email = ""
+	return s.List(ctx, filter)
+}
+
+// List — журнал с фильтрами (админка)
+func (s *auditService) List(ctx context.Context, filter models.AuditFilter) (*models.AuditList, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditListLimit
+	}
+
+	entries, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		s.logger.Errorw("Failed to list audit entries", "error", err)
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return &models.AuditList{Entries: entries, Total: total, Limit: filter.Limit, Offset: filter.Offset}, nil
+}
@@ -4,8 +4,12 @@ import (
 	"auth-service/internal/models"
 	"auth-service/internal/repository"
 	"auth-service/internal/utils"
+	"auth-service/pkg/apperror"
+	"auth-service/pkg/mailer"
+	"auth-service/pkg/oauth"
+	"auth-service/pkg/sms"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,34 +17,118 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrInvalidRefreshToken — refresh токен не прошёл проверку, отозван или ротирован, либо пользователь удалён
+var ErrInvalidRefreshToken = apperror.Unauthorized("invalid or revoked refresh token")
+
 // AuthService — бизнес-логика аутентификации
 type AuthService interface {
 	RegisterUser(ctx context.Context, req models.UserRegister) (*models.User, error)
-	Login(ctx context.Context, req models.UserLogin) (*models.TokenPair, error)
-	RefreshToken(ctx context.Context, req models.RefreshTokenRequest) (*models.TokenPair, error)
-	RevokeByJTI(ctx context.Context, jti string) error
+	Login(ctx context.Context, req models.UserLogin, client models.ClientInfo) (*models.LoginResult, error)
+	RefreshToken(ctx context.Context, req models.RefreshTokenRequest, client models.ClientInfo) (*models.TokenPair, error)
+	RevokeByJTI(ctx context.Context, userID uuid.UUID, jti string) error
+	VerifyEmail(ctx context.Context, req models.VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, req models.ResendVerificationRequest) error
+	ForgotPassword(ctx context.Context, req models.ResetPasswordRequest) error
+	ResetPassword(ctx context.Context, req models.ResetPasswordConfirmRequest) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, currentJTI string, req models.ChangePasswordRequest) error
+	ListSessions(ctx context.Context, userID uuid.UUID, currentJTI string) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID uuid.UUID, jti string) error
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) error
+	SetupMFA(ctx context.Context, userID uuid.UUID) (*models.MFASetupResponse, error)
+	EnableMFA(ctx context.Context, userID uuid.UUID, req models.MFAEnableRequest) ([]string, error)
+	VerifyMFA(ctx context.Context, req models.MFAVerifyRequest, client models.ClientInfo) (*models.TokenPair, error)
+	StartOAuth(ctx context.Context, provider string) (*oauth.Authorization, error)
+	OAuthCallback(ctx context.Context, provider, state, stateBinding, code string, client models.ClientInfo) (*models.LoginResult, error)
+	RequestMagicLink(ctx context.Context, req models.MagicLinkRequest, client models.ClientInfo) error
+	ConsumeMagicLink(ctx context.Context, req models.MagicLinkConsumeRequest, client models.ClientInfo) (*models.LoginResult, error)
+	RequestEmailChange(ctx context.Context, userID uuid.UUID, req models.ChangeEmailRequest) error
+	ConfirmEmailChange(ctx context.Context, req models.ConfirmEmailChangeRequest) error
+	SetPhone(ctx context.Context, userID uuid.UUID, req models.SetPhoneRequest, client models.ClientInfo) error
+	VerifyPhone(ctx context.Context, userID uuid.UUID, req models.VerifyPhoneRequest) error
+	RequestOTP(ctx context.Context, req models.OTPRequest, client models.ClientInfo) error
+	VerifyOTP(ctx context.Context, req models.OTPVerifyRequest, client models.ClientInfo) (*models.LoginResult, error)
+	CheckUserActive(ctx context.Context, userID uuid.UUID) error
+}
+
+// AuthServiceConfig — настройки бизнес-логики
+type AuthServiceConfig struct {
+	LinkBaseURL          string        // База для ссылок в письмах (фронтенд)
+	VerifyTokenTTL       time.Duration // Время жизни токена подтверждения email
+	VerifyResendInterval time.Duration // Минимальный интервал между повторными письмами
+	ResetTokenTTL        time.Duration // Время жизни токена сброса пароля
+	EmailChangeTokenTTL  time.Duration // Время жизни ссылки подтверждения нового email
+	UserStatusCacheTTL   time.Duration // Локальный кэш статуса пользователя для /validate (0 — без кэша)
+	LoginProtection      LoginProtectionConfig
+	MFA                  MFAConfig
+	PasswordPolicy       PasswordPolicyConfig
+	MagicLink            MagicLinkConfig
+	OTP                  OTPConfig
 }
 
 // authService — реализация
 type authService struct {
-	userRepo   repository.UserRepository
-	outboxRepo repository.OutboxRepository
-	tokenSvc   utils.TokenService
-	logger     *zap.SugaredLogger
+	userRepo        repository.UserRepository
+	outboxRepo      repository.OutboxRepository
+	resetRepo       repository.PasswordResetRepository
+	magicLinkRepo   repository.MagicLinkRepository
+	emailChangeRepo repository.EmailChangeRepository
+	otpRepo         repository.OTPRepository
+	attempts        repository.AttemptRepository
+	mfaRepo         repository.MFARepository
+	challengeRepo   repository.MFAChallengeRepository
+	identityRepo    repository.IdentityRepository
+	tokenSvc        utils.TokenService
+	mailer          mailer.Mailer
+	sms             sms.SMSSender
+	cipher          *utils.SecretCipher
+	oauth           *oauth.Manager
+	audit           AuditRecorder
+	statusCache     *utils.TTLCache[uuid.UUID, string]
+	cfg             AuthServiceConfig
+	logger          *zap.SugaredLogger
 }
 
 // NewAuthService — конструктор
 func NewAuthService(
 	userRepo repository.UserRepository,
 	outboxRepo repository.OutboxRepository,
+	resetRepo repository.PasswordResetRepository,
+	magicLinkRepo repository.MagicLinkRepository,
+	emailChangeRepo repository.EmailChangeRepository,
+	otpRepo repository.OTPRepository,
+	attempts repository.AttemptRepository,
+	mfaRepo repository.MFARepository,
+	challengeRepo repository.MFAChallengeRepository,
+	identityRepo repository.IdentityRepository,
 	tokenSvc utils.TokenService,
+	mailer mailer.Mailer,
+	smsSender sms.SMSSender,
+	cipher *utils.SecretCipher,
+	oauthManager *oauth.Manager,
+	audit AuditRecorder,
+	cfg AuthServiceConfig,
 	logger *zap.SugaredLogger,
 ) AuthService {
 	return &authService{
-		userRepo:   userRepo,
-		outboxRepo: outboxRepo,
-		tokenSvc:   tokenSvc,
-		logger:     logger,
+		userRepo:        userRepo,
+		outboxRepo:      outboxRepo,
+		resetRepo:       resetRepo,
+		magicLinkRepo:   magicLinkRepo,
+		emailChangeRepo: emailChangeRepo,
+		otpRepo:         otpRepo,
+		attempts:        attempts,
+		mfaRepo:         mfaRepo,
+		challengeRepo:   challengeRepo,
+		identityRepo:    identityRepo,
+		tokenSvc:        tokenSvc,
+		mailer:          mailer,
+		sms:             smsSender,
+		cipher:          cipher,
+		oauth:           oauthManager,
+		audit:           audit,
+		statusCache:     newUserStatusCache(cfg.UserStatusCacheTTL),
+		cfg:             cfg,
+		logger:          logger,
 	}
 }
 
@@ -48,6 +136,11 @@ func NewAuthService(
 func (s *authService) RegisterUser(ctx context.Context, req models.UserRegister) (*models.User, error) {
 	log := s.logger.With("email", req.Email)
 
+	if err := s.checkPasswordPolicy(req.Password, req.Email); err != nil {
+		log.Infow("Registration failed: weak password", "error", err)
+		return nil, err
+	}
+
 	// Проверка: email уже занят?
 	exists, err := s.userRepo.ExistsByEmail(ctx, req.Email)
 	if err != nil {
@@ -56,7 +149,7 @@ func (s *authService) RegisterUser(ctx context.Context, req models.UserRegister)
 	}
 	if exists {
 		log.Warnw("Registration failed: email already in use")
-		return nil, fmt.Errorf("email already in use")
+		return nil, ErrEmailTaken
 	}
 
 	// НАЧИНАЕМ ТРАНЗАКЦИЮ
@@ -80,49 +173,45 @@ func (s *authService) RegisterUser(ctx context.Context, req models.UserRegister)
 		return nil, fmt.Errorf("password hashing failed: %w", err)
 	}
 
+	// Токен подтверждения email (в БД храним только хэш)
+	verifyToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		log.Errorw("Verify token generation failed", "error", err)
+		return nil, fmt.Errorf("failed to generate verify token: %w", err)
+	}
+	verifyTokenHash := utils.HashToken(verifyToken)
+	now := time.Now()
+
 	// Создаём пользователя
 	user := &models.User{
-		ID:           uuid.New(),
-		Email:        req.Email,
-		PasswordHash: hashedPassword,
-		Role:         "user",
-		Status:       "active",
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		ID:                uuid.New(),
+		Email:             req.Email,
+		PasswordHash:      hashedPassword,
+		EmailVerifyToken:  &verifyTokenHash,
+		EmailVerifySentAt: &now,
+		Phone:             req.Phone,
+		Role:              "user",
+		Status:            "active",
+		CreatedAt:         now,
+		UpdatedAt:         now,
 	}
 
 	// СОХРАНЯЕМ ПОЛЬЗОВАТЕЛЯ в транзакции
 	if err = s.userRepo.CreateTx(ctx, tx, user); err != nil {
+		if errors.Is(err, repository.ErrEmailTaken) {
+			log.Warnw("Registration failed: email already in use")
+			err = ErrEmailTaken
+			return nil, err
+		}
 		log.Errorw("Failed to create user in database", "error", err)
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	// СОЗДАЁМ СОБЫТИЕ UserRegistered
-	userRegisteredEvent := models.UserRegistered{
-		UserID:    user.ID,
-		Email:     user.Email,
-		FirstName: req.FirstName,
-		LastName:  req.LastName,
-		Role:      user.Role,
-		CreatedAt: time.Now(),
-	}
-
-	eventPayload, err := json.Marshal(userRegisteredEvent)
+	// СОЗДАЁМ СОБЫТИЕ UserRegistered в Outbox
+	outboxEvent, err := s.insertUserRegisteredTx(ctx, tx, user, req.FirstName, req.LastName)
 	if err != nil {
-		log.Errorw("Failed to marshal UserRegistered event", "error", err)
-		return nil, fmt.Errorf("failed to create event: %w", err)
-	}
-
-	outboxEvent := &models.OutboxEvent{
-		ID:        uuid.New(),
-		EventType: "UserRegistered",
-		Payload:   eventPayload,
-	}
-
-	// СОХРАНЯЕМ СОБЫТИЕ в Outbox
-	if err = s.outboxRepo.InsertTx(ctx, tx, outboxEvent); err != nil {
 		log.Errorw("Failed to insert outbox event", "error", err)
-		return nil, fmt.Errorf("failed to create outbox event: %w", err)
+		return nil, err
 	}
 
 	// COMMIT
@@ -135,20 +224,38 @@ func (s *authService) RegisterUser(ctx context.Context, req models.UserRegister)
 		"user_id", user.ID,
 		"outbox_event_id", outboxEvent.ID,
 		"event_type", outboxEvent.EventType)
+	s.audit.Record(ctx, userAuditEntry(models.AuditRegister, user.ID, user.Email))
+
+	// Письмо отправляем после коммита: при ошибке пользователь может запросить повторную отправку
+	if err := s.sendVerificationEmail(ctx, user.Email, verifyToken); err != nil {
+		log.Warnw("Failed to send verification email", "user_id", user.ID, "error", err)
+	}
 
 	return user, nil
 }
 
-// Login — вход пользователя
-func (s *authService) Login(ctx context.Context, req models.UserLogin) (*models.TokenPair, error) {
+// Login — вход пользователя (с защитой от перебора паролей).
+// При включённой 2FA вместо токенов возвращается challenge для POST /2fa/verify.
+func (s *authService) Login(ctx context.Context, req models.UserLogin, client models.ClientInfo) (*models.LoginResult, error) {
 	log := s.logger.With("email", req.Email)
 
+	// Блокировки и back-off проверяем до обращения к БД и сравнения пароля
+	if err := s.checkLoginAllowed(ctx, req.Email, client.IP); err != nil {
+		s.auditLoginFailure(ctx, nil, req.Email, "throttled")
+		return nil, err
+	}
+
 	// Находим пользователя
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		if err.Error() == "not found" {
+		if errors.Is(err, repository.ErrUserNotFound) {
 			log.Infow("Login failed: user not found")
-			return nil, fmt.Errorf("invalid email or password")
+			// Тот же argon2id, что и для существующего аккаунта: время ответа не раскрывает наличие email
+			_ = utils.ComparePassword(utils.DummyPasswordHash, req.Password)
+			// Неизвестный email считаем неудачной попыткой — иначе перебор раскрывает наличие аккаунтов
+			s.registerLoginFailure(ctx, nil, req.Email, client.IP)
+			s.auditLoginFailure(ctx, nil, req.Email, "unknown_email")
+			return nil, ErrInvalidCredentials
 		}
 		log.Errorw("Database error during login", "error", err)
 		return nil, fmt.Errorf("database error: %w", err)
@@ -157,67 +264,124 @@ func (s *authService) Login(ctx context.Context, req models.UserLogin) (*models.
 	// Проверяем пароль
 	if err := utils.ComparePassword(user.PasswordHash, req.Password); err != nil {
 		log.Infow("Login failed: invalid password")
-		return nil, fmt.Errorf("invalid email or password")
+		s.registerLoginFailure(ctx, user, req.Email, client.IP)
+		s.auditLoginFailure(ctx, user, req.Email, "invalid_password")
+		return nil, ErrInvalidCredentials
+	}
+
+	s.resetLoginFailures(ctx, req.Email)
+
+	// Старый формат хэша (bcrypt) или устаревшие параметры — пересчитываем, пока знаем пароль
+	if utils.NeedsRehash(user.PasswordHash) {
+		s.upgradePasswordHash(ctx, user, req.Password)
+	}
+
+	return s.completeLogin(ctx, user, client, loginMethodPassword)
+}
+
+// completeLogin — завершение входа после проверки первого фактора (пароль, OAuth):
+// при включённой 2FA выдаётся challenge, иначе — пара токенов.
+// method — способ входа для журнала аудита.
+func (s *authService) completeLogin(ctx context.Context, user *models.User, client models.ClientInfo, method string) (*models.LoginResult, error) {
+	log := s.logger.With("user_id", user.ID)
+
+	if user.Status != models.UserStatusActive {
+		log.Infow("Login rejected: account is not active", "status", user.Status)
+		s.auditLoginFailure(ctx, user, user.Email, "account_"+user.Status)
+		return nil, ErrAccountDisabled
+	}
+
+	// Второй фактор
+	mfa, err := s.mfaRepo.Get(ctx, user.ID)
+	if err != nil && !errors.Is(err, repository.ErrMFANotFound) {
+		log.Errorw("Failed to load MFA settings", "error", err)
+		return nil, fmt.Errorf("failed to load mfa settings: %w", err)
+	}
+	if mfa != nil && mfa.Enabled {
+		challenge, err := s.startMFAChallenge(ctx, user.ID)
+		if err != nil {
+			log.Errorw("Failed to start MFA challenge", "error", err)
+			return nil, err
+		}
+		log.Infow("First factor accepted, MFA required")
+		return &models.LoginResult{Challenge: challenge}, nil
 	}
 
 	// Обновляем last_login_at
 	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
-		log.Warnw("Failed to update last login", "user_id", user.ID, "error", err)
+		log.Warnw("Failed to update last login", "error", err)
 	}
 
 	// Генерируем токены
-	pair, err := s.tokenSvc.GeneratePair(ctx, user.ID, user.Email, user.Role)
+	pair, err := s.tokenSvc.GeneratePair(ctx, user.ID, user.Email, user.Role, client)
 	if err != nil {
-		log.Errorw("Token generation failed", "user_id", user.ID, "error", err)
+		log.Errorw("Token generation failed", "error", err)
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
-	log.Infow("Login successful", "user_id", user.ID)
-	return pair, nil
+	log.Infow("Login successful")
+	s.auditLoginSuccess(ctx, user, pair.JTI, method)
+	return &models.LoginResult{Tokens: pair}, nil
 }
 
 // RefreshToken — ротация токенов
-func (s *authService) RefreshToken(ctx context.Context, req models.RefreshTokenRequest) (*models.TokenPair, error) {
+func (s *authService) RefreshToken(ctx context.Context, req models.RefreshTokenRequest, client models.ClientInfo) (*models.TokenPair, error) {
 	log := s.logger
 
 	// Парсим refresh токен
 	claims, err := s.tokenSvc.ParseRefresh(req.RefreshToken)
 	if err != nil {
 		log.Warnw("Invalid refresh token", "error", err)
-		return nil, fmt.Errorf("invalid or revoked refresh token: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRefreshToken, err)
 	}
 
 	userID, err := uuid.Parse(claims.Sub)
 	if err != nil {
 		log.Warnw("Invalid user ID in token", "sub", claims.Sub)
-		return nil, fmt.Errorf("invalid user ID in token")
+		return nil, ErrInvalidRefreshToken
 	}
 
 	// Проверяем пользователя
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		if err.Error() == "not found" {
+		if errors.Is(err, repository.ErrUserNotFound) {
 			log.Warnw("User not found during refresh", "user_id", userID)
-			return nil, fmt.Errorf("user not found")
+			return nil, ErrInvalidRefreshToken
 		}
 		log.Errorw("Database error during refresh", "error", err)
 		return nil, fmt.Errorf("database error: %w", err)
 	}
+	if user.Status != models.UserStatusActive {
+		log.Infow("Refresh rejected: account is not active", "user_id", user.ID, "status", user.Status)
+		return nil, ErrAccountDisabled
+	}
 
 	// Ротируем токены
-	newPair, err := s.tokenSvc.RotateRefresh(ctx, req.RefreshToken, user.ID, user.Email, user.Role)
+	newPair, err := s.tokenSvc.RotateRefresh(ctx, req.RefreshToken, user.ID, user.Email, user.Role, client)
 	if err != nil {
 		log.Errorw("Token rotation failed", "old_jti", claims.JTI, "error", err)
+		entry := userAuditEntry(models.AuditTokenRefresh, user.ID, user.Email)
+		entry.Success = false
+		entry.JTI = claims.JTI
+		entry.Metadata = auditMetadata(map[string]any{"error": err.Error()})
+		s.audit.Record(ctx, entry)
+		if errors.Is(err, utils.ErrRefreshTokenRevoked) || errors.Is(err, utils.ErrRefreshTokenReused) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRefreshToken, err)
+		}
 		return nil, fmt.Errorf("token rotation failed: %w", err)
 	}
 
 	log.Infow("Token refreshed", "user_id", user.ID, "old_jti", claims.JTI)
+	entry := userAuditEntry(models.AuditTokenRefresh, user.ID, user.Email)
+	entry.JTI = newPair.JTI
+	entry.Metadata = auditMetadata(map[string]any{"old_jti": claims.JTI})
+	s.audit.Record(ctx, entry)
 	return newPair, nil
 }
 
-// RevokeByJTI — отзыв токена по jti
-func (s *authService) RevokeByJTI(ctx context.Context, jti string) error {
-	log := s.logger.With("jti", jti)
+// RevokeByJTI — отзыв токена по jti (выход с текущего устройства)
+func (s *authService) RevokeByJTI(ctx context.Context, userID uuid.UUID, jti string) error {
+	log := s.logger.With("jti", jti, "user_id", userID)
 
 	if err := s.tokenSvc.RevokeRefresh(ctx, jti); err != nil {
 		log.Errorw("Failed to revoke token", "error", err)
@@ -225,5 +389,8 @@ func (s *authService) RevokeByJTI(ctx context.Context, jti string) error {
 	}
 
 	log.Infow("Token revoked successfully")
+	entry := userAuditEntry(models.AuditLogout, userID, "")
+	entry.JTI = jti
+	s.audit.Record(ctx, entry)
 	return nil
 }
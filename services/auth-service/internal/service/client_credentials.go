@@ -0,0 +1,112 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// GrantTypeClientCredentials — единственный grant_type token endpoint
+const GrantTypeClientCredentials = "client_credentials"
+
+var (
+	// ErrUnsupportedGrantType — grant_type отличается от client_credentials
+	ErrUnsupportedGrantType = errors.New("unsupported grant type")
+	// ErrInvalidClient — клиент не найден, отключён или неверный секрет
+	ErrInvalidClient = errors.New("invalid client credentials")
+	// ErrInvalidScope — запрошен scope, не выданный клиенту
+	ErrInvalidScope = errors.New("requested scope is not allowed for this client")
+	// ErrInvalidTarget — audience не разрешён клиенту или не указан, когда разрешённых несколько
+	ErrInvalidTarget = errors.New("requested audience is not allowed for this client")
+)
+
+// ClientCredentialsService — выдача машинных токенов сервисным клиентам
+type ClientCredentialsService interface {
+	IssueToken(ctx context.Context, req models.ClientCredentialsRequest) (*models.ServiceToken, error)
+}
+
+type clientCredentialsService struct {
+	clientRepo repository.ServiceClientRepository
+	tokenSvc   utils.TokenService
+	logger     *zap.SugaredLogger
+}
+
+// NewClientCredentialsService — конструктор
+func NewClientCredentialsService(
+	clientRepo repository.ServiceClientRepository,
+	tokenSvc utils.TokenService,
+	logger *zap.SugaredLogger,
+) ClientCredentialsService {
+	return &clientCredentialsService{
+		clientRepo: clientRepo,
+		tokenSvc:   tokenSvc,
+		logger:     logger,
+	}
+}
+
+// IssueToken — проверяет секрет клиента и выдаёт токен с запрошенными scopes
+// (без scope — со всеми scopes клиента) для одного сервиса-получателя
+func (s *clientCredentialsService) IssueToken(ctx context.Context, req models.ClientCredentialsRequest) (*models.ServiceToken, error) {
+	log := s.logger.With("client_id", req.ClientID)
+
+	if req.GrantType != GrantTypeClientCredentials {
+		log.Infow("Token request rejected: unsupported grant type", "grant_type", req.GrantType)
+		return nil, ErrUnsupportedGrantType
+	}
+	if req.ClientID == "" || req.ClientSecret == "" {
+		return nil, ErrInvalidClient
+	}
+
+	client, err := s.clientRepo.GetByID(ctx, req.ClientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceClientNotFound) {
+			log.Infow("Token request rejected: unknown client")
+			return nil, ErrInvalidClient
+		}
+		log.Errorw("Failed to load service client", "error", err)
+		return nil, fmt.Errorf("failed to load service client: %w", err)
+	}
+
+	if err := utils.ComparePassword(client.SecretHash, req.ClientSecret); err != nil {
+		log.Warnw("Token request rejected: invalid client secret")
+		return nil, ErrInvalidClient
+	}
+	if client.Disabled {
+		log.Infow("Token request rejected: client is disabled")
+		return nil, ErrInvalidClient
+	}
+
+	scopes := client.Scopes
+	if req.Scope != "" {
+		scopes = strings.Fields(req.Scope)
+		for _, scope := range scopes {
+			if !slices.Contains(client.Scopes, scope) {
+				log.Infow("Token request rejected: scope not allowed", "scope", scope)
+				return nil, ErrInvalidScope
+			}
+		}
+	}
+
+	audience := req.Audience
+	if audience == "" && len(client.Audiences) == 1 {
+		audience = client.Audiences[0]
+	}
+	if !slices.Contains(client.Audiences, audience) {
+		log.Infow("Token request rejected: audience not allowed", "audience", req.Audience)
+		return nil, ErrInvalidTarget
+	}
+
+	token, err := s.tokenSvc.GenerateServiceToken(client.ClientID, audience, scopes)
+	if err != nil {
+		log.Errorw("Service token generation failed", "error", err)
+		return nil, fmt.Errorf("failed to generate service token: %w", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fakeClientRepo — один зарегистрированный клиент
+type fakeClientRepo struct {
+	repository.ServiceClientRepository
+	client *models.ServiceClient
+}
+
+func (f *fakeClientRepo) GetByID(ctx context.Context, clientID string) (*models.ServiceClient, error) {
+	if f.client == nil || f.client.ClientID != clientID {
+		return nil, repository.ErrServiceClientNotFound
+	}
+	return f.client, nil
+}
+
+// fakeServiceTokens — запоминает, для какого audience выдан токен
+type fakeServiceTokens struct {
+	utils.TokenService
+	audience string
+}
+
+func (f *fakeServiceTokens) GenerateServiceToken(clientID, audience string, scopes []string) (*models.ServiceToken, error) {
+	f.audience = audience
+	return &models.ServiceToken{AccessToken: "token", TokenType: "Bearer"}, nil
+}
+
+func TestIssueToken_Audience(t *testing.T) {
+	const secret = "client-secret"
+	hash, err := utils.HashPassword(secret)
+	if err != nil {
+		t.Fatalf("hash secret: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		audiences []string
+		requested string
+		want      string
+		wantErr   error
+	}{
+		{name: "requested allowed audience", audiences: []string{"event-service", "profile-service"}, requested: "profile-service", want: "profile-service"},
+		{name: "single audience is the default", audiences: []string{"event-service"}, want: "event-service"},
+		{name: "audience required when several allowed", audiences: []string{"event-service", "profile-service"}, wantErr: ErrInvalidTarget},
+		{name: "audience not allowed", audiences: []string{"event-service"}, requested: "profile-service", wantErr: ErrInvalidTarget},
+		{name: "client without audiences", audiences: []string{}, wantErr: ErrInvalidTarget},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &fakeServiceTokens{}
+			svc := NewClientCredentialsService(&fakeClientRepo{client: &models.ServiceClient{
+				ClientID:   "billing",
+				SecretHash: hash,
+				Scopes:     []string{"events:read"},
+				Audiences:  tt.audiences,
+			}}, tokens, zap.NewNop().Sugar())
+
+			_, err := svc.IssueToken(context.Background(), models.ClientCredentialsRequest{
+				GrantType:    GrantTypeClientCredentials,
+				ClientID:     "billing",
+				ClientSecret: secret,
+				Audience:     tt.requested,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IssueToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if tokens.audience != tt.want {
+				t.Errorf("token audience = %q, want %q", tokens.audience, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"auth-service/pkg/apperror"
+	"auth-service/pkg/mailer"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidEmailChangeToken — ссылка подтверждения нового email недействительна или уже использована
+	ErrInvalidEmailChangeToken = apperror.Validation("invalid or expired email change token")
+	// ErrSameEmail — новый email совпадает с текущим
+	ErrSameEmail = apperror.Validation("new email must differ from the current one")
+	// ErrEmailTaken — email уже занят другим пользователем
+	ErrEmailTaken = apperror.Conflict("email already in use")
+)
+
+// RequestEmailChange — проверяет пароль и отправляет ссылку подтверждения на новый адрес.
+// Email меняется только после перехода по ссылке.
+func (s *authService) RequestEmailChange(ctx context.Context, userID uuid.UUID, req models.ChangeEmailRequest) error {
+	log := s.logger.With("user_id", userID)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Errorw("Failed to load user for email change", "error", err)
+		return fmt.Errorf("failed to load user: %w", err)
+	}
+
+	if err := utils.ComparePassword(user.PasswordHash, req.Password); err != nil {
+		log.Infow("Email change rejected: wrong password")
+		return ErrWrongPassword
+	}
+
+	newEmail := strings.TrimSpace(req.NewEmail)
+	if strings.EqualFold(newEmail, user.Email) {
+		return ErrSameEmail
+	}
+
+	exists, err := s.userRepo.ExistsByEmail(ctx, newEmail)
+	if err != nil {
+		log.Errorw("Database error: email check failed", "error", err)
+		return fmt.Errorf("failed to check email: %w", err)
+	}
+	if exists {
+		log.Infow("Email change rejected: email already in use")
+		return ErrEmailTaken
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate email change token: %w", err)
+	}
+
+	change := models.EmailChange{
+		UserID:    user.ID,
+		OldEmail:  user.Email,
+		NewEmail:  newEmail,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.emailChangeRepo.Save(ctx, utils.HashToken(token), change, s.cfg.EmailChangeTokenTTL); err != nil {
+		log.Errorw("Failed to store email change", "error", err)
+		return fmt.Errorf("failed to store email change: %w", err)
+	}
+
+	if err := s.sendEmailChangeConfirmation(ctx, newEmail, token); err != nil {
+		log.Errorw("Failed to send email change confirmation", "error", err)
+		return fmt.Errorf("failed to send confirmation email: %w", err)
+	}
+
+	log.Infow("Email change requested")
+	return nil
+}
+
+// ConfirmEmailChange — подтверждение по ссылке: атомарная смена email + событие UserEmailChanged.
+// Access токены со старым email действуют до истечения; при refresh выдаются токены с новым email.
+func (s *authService) ConfirmEmailChange(ctx context.Context, req models.ConfirmEmailChangeRequest) (err error) {
+	log := s.logger
+
+	change, err := s.emailChangeRepo.Consume(ctx, utils.HashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailChangeNotFound) {
+			log.Infow("Email change confirmation failed: invalid token")
+			return ErrInvalidEmailChangeToken
+		}
+		log.Errorw("Failed to consume email change", "error", err)
+		return fmt.Errorf("failed to consume email change: %w", err)
+	}
+	log = log.With("user_id", change.UserID)
+
+	tx, err := s.userRepo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				log.Errorw("Failed to rollback transaction", "error", rollbackErr)
+			}
+		}
+	}()
+
+	user, err := s.userRepo.GetByIDForUpdateTx(ctx, tx, change.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			err = ErrInvalidEmailChangeToken
+		}
+		return err
+	}
+	// Аккаунт удалён или email успели сменить другим запросом — ссылка устарела
+	if user.Status == models.UserStatusDeleted || user.Email != change.OldEmail {
+		log.Infow("Email change confirmation failed: stale request", "status", user.Status)
+		err = ErrInvalidEmailChangeToken
+		return err
+	}
+
+	if err = s.userRepo.UpdateEmailTx(ctx, tx, user.ID, change.NewEmail); err != nil {
+		if errors.Is(err, repository.ErrEmailTaken) {
+			log.Infow("Email change confirmation failed: email taken meanwhile")
+			err = ErrEmailTaken
+		}
+		return err
+	}
+
+	outboxEvent, err := newOutboxEvent(ctx, "UserEmailChanged", user.ID, models.UserEmailChanged{
+		UserID:    user.ID,
+		OldEmail:  change.OldEmail,
+		NewEmail:  change.NewEmail,
+		ChangedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	if err = s.outboxRepo.InsertTx(ctx, tx, outboxEvent); err != nil {
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Уведомление на старый адрес: владелец узнает, если смену сделал не он
+	go func(ctx context.Context) {
+		if err := s.sendEmailChangedNotice(ctx, change.OldEmail, change.NewEmail); err != nil {
+			log.Errorw("Failed to notify old email address", "error", err)
+		}
+	}(context.WithoutCancel(ctx))
+
+	log.Infow("Email changed", "outbox_event_id", outboxEvent.ID)
+	entry := userAuditEntry(models.AuditEmailChanged, user.ID, change.NewEmail)
+	entry.Metadata = auditMetadata(map[string]any{"old_email": change.OldEmail})
+	s.audit.Record(ctx, entry)
+	return nil
+}
+
+// sendEmailChangeConfirmation — письмо со ссылкой подтверждения на новый адрес
+func (s *authService) sendEmailChangeConfirmation(ctx context.Context, email, token string) error {
+	link := fmt.Sprintf("%s/confirm-email-change?token=%s", s.cfg.LinkBaseURL, token)
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Подтвердите новый email в Huddle",
+		Body: fmt.Sprintf(
+			"Здравствуйте!\n\nЧтобы сделать этот адрес основным для вашего аккаунта Huddle, перейдите по ссылке:\n%s\n\nСсылка одноразовая и действительна %s. Если вы не меняли email, просто проигнорируйте это письмо.\n",
+			link, s.cfg.EmailChangeTokenTTL,
+		),
+	})
+}
+
+// sendEmailChangedNotice — уведомление старого адреса о смене email
+func (s *authService) sendEmailChangedNotice(ctx context.Context, oldEmail, newEmail string) error {
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      oldEmail,
+		Subject: "Email вашего аккаунта Huddle изменён",
+		Body: fmt.Sprintf(
+			"Здравствуйте!\n\nEmail вашего аккаунта Huddle изменён на %s. Если это сделали не вы, срочно восстановите доступ через сброс пароля и обратитесь в поддержку.\n",
+			newEmail,
+		),
+	})
+}
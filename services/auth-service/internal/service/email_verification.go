@@ -0,0 +1,132 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"auth-service/pkg/apperror"
+	"auth-service/pkg/mailer"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidVerificationToken — токен не найден, уже использован или истёк
+	ErrInvalidVerificationToken = apperror.Validation("invalid or expired verification token")
+)
+
+// VerifyEmail — подтверждение email по одноразовому токену + событие UserVerified
+func (s *authService) VerifyEmail(ctx context.Context, req models.VerifyEmailRequest) error {
+	log := s.logger
+
+	tx, err := s.userRepo.BeginTx(ctx)
+	if err != nil {
+		log.Errorw("Failed to begin transaction", "error", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				log.Errorw("Failed to rollback transaction", "error", rollbackErr)
+			}
+		}
+	}()
+
+	// Токен гасится тем же UPDATE, поэтому повторное использование невозможно
+	user, err := s.userRepo.VerifyEmailTx(ctx, tx, utils.HashToken(req.Token), time.Now().Add(-s.cfg.VerifyTokenTTL))
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Infow("Email verification failed: invalid token")
+			return ErrInvalidVerificationToken
+		}
+		log.Errorw("Database error during email verification", "error", err)
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	outboxEvent, err := newOutboxEvent(ctx, "UserVerified", user.ID, models.UserVerified{
+		UserID:     user.ID,
+		Email:      user.Email,
+		VerifiedAt: time.Now(),
+	})
+	if err != nil {
+		log.Errorw("Failed to marshal UserVerified event", "error", err)
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+
+	if err = s.outboxRepo.InsertTx(ctx, tx, outboxEvent); err != nil {
+		log.Errorw("Failed to insert outbox event", "error", err)
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Errorw("Failed to commit transaction", "error", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Infow("Email verified", "user_id", user.ID, "outbox_event_id", outboxEvent.ID)
+	return nil
+}
+
+// ResendVerification — повторная отправка письма с троттлингом.
+// Для несуществующих, уже подтверждённых аккаунтов и слишком частых запросов молча ничего
+// не делает: ответ и его время не должны выдавать, зарегистрирован ли email.
+func (s *authService) ResendVerification(ctx context.Context, req models.ResendVerificationRequest) error {
+	log := s.logger.With("email", req.Email)
+
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Infow("Resend verification skipped: user not found")
+			return nil
+		}
+		log.Errorw("Database error during resend verification", "error", err)
+		return fmt.Errorf("database error: %w", err)
+	}
+	if user.IsVerified {
+		log.Infow("Resend verification skipped: already verified", "user_id", user.ID)
+		return nil
+	}
+
+	now := time.Now()
+	if user.EmailVerifySentAt != nil && now.Sub(*user.EmailVerifySentAt) < s.cfg.VerifyResendInterval {
+		log.Infow("Resend verification throttled", "user_id", user.ID)
+		return nil
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate verify token: %w", err)
+	}
+
+	// Новый токен заменяет старый — предыдущая ссылка перестаёт работать
+	if err := s.userRepo.SetEmailVerifyToken(ctx, user.ID, utils.HashToken(token), now); err != nil {
+		log.Errorw("Failed to store verify token", "user_id", user.ID, "error", err)
+		return fmt.Errorf("failed to store verify token: %w", err)
+	}
+
+	// Письмо уходит в фоне: время ответа не должно выдавать существование аккаунта
+	go func(ctx context.Context) {
+		if err := s.sendVerificationEmail(ctx, user.Email, token); err != nil {
+			log.Errorw("Failed to send verification email", "user_id", user.ID, "error", err)
+		}
+	}(context.WithoutCancel(ctx))
+
+	log.Infow("Verification email resent", "user_id", user.ID)
+	return nil
+}
+
+// sendVerificationEmail — письмо со ссылкой подтверждения
+func (s *authService) sendVerificationEmail(ctx context.Context, email, token string) error {
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.cfg.LinkBaseURL, token)
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Подтвердите email в Huddle",
+		Body: fmt.Sprintf(
+			"Здравствуйте!\n\nЧтобы подтвердить адрес электронной почты, перейдите по ссылке:\n%s\n\nСсылка действительна %s. Если вы не регистрировались в Huddle, просто проигнорируйте это письмо.\n",
+			link, s.cfg.VerifyTokenTTL,
+		),
+	})
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newOutboxEvent — событие для outbox: payload в JSON, ключ-агрегат, request_id и traceparent текущего запроса
+func newOutboxEvent(ctx context.Context, eventType string, aggregateID uuid.UUID, payload any) (*models.OutboxEvent, error) {
+	eventPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event: %w", eventType, err)
+	}
+
+	client := utils.ClientInfoFromContext(ctx)
+	return &models.OutboxEvent{
+		ID:            uuid.New(),
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		SchemaVersion: models.EventSchemaVersion,
+		Payload:       eventPayload,
+		RequestID:     client.RequestID,
+		Traceparent:   client.Traceparent,
+	}, nil
+}
+
+// emitEvent — пишет событие пользователя в outbox отдельной транзакцией.
+// Для событий, не связанных с изменением данных пользователя в той же транзакции.
+func (s *authService) emitEvent(ctx context.Context, eventType string, userID uuid.UUID, payload any) (err error) {
+	outboxEvent, err := newOutboxEvent(ctx, eventType, userID, payload)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.userRepo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Errorw("Failed to rollback transaction", "error", rollbackErr)
+			}
+		}
+	}()
+
+	if err = s.outboxRepo.InsertTx(ctx, tx, outboxEvent); err != nil {
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Infow("Outbox event created", "outbox_event_id", outboxEvent.ID, "event_type", eventType)
+	return nil
+}
+
+// insertUserRegisteredTx — событие UserRegistered в транзакции создания пользователя
+// (по нему profile-service создаёт профиль)
+func (s *authService) insertUserRegisteredTx(ctx context.Context, tx repository.Tx, user *models.User, firstName, lastName string) (*models.OutboxEvent, error) {
+	outboxEvent, err := newOutboxEvent(ctx, "UserRegistered", user.ID, models.UserRegistered{
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: firstName,
+		LastName:  lastName,
+		Role:      user.Role,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event: %w", err)
+	}
+
+	if err := s.outboxRepo.InsertTx(ctx, tx, outboxEvent); err != nil {
+		return nil, fmt.Errorf("failed to create outbox event: %w", err)
+	}
+	return outboxEvent, nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCredentials — неверный email или пароль
+var ErrInvalidCredentials = apperror.Unauthorized("invalid email or password")
+
+// loginThrottled — вход временно запрещён (back-off или блокировка)
+func loginThrottled(retryAfter time.Duration) error {
+	return apperror.TooManyRequests("too many failed login attempts, try again later", retryAfter)
+}
+
+// LoginProtectionConfig — пороги защиты от перебора паролей
+type LoginProtectionConfig struct {
+	Window              time.Duration // Скользящее окно подсчёта неудачных попыток
+	MaxFailuresPerEmail int           // После стольких неудач аккаунт блокируется на LockoutDuration
+	MaxFailuresPerIP    int           // После стольких неудач с одного IP блокируется IP
+	BackoffBase         time.Duration // Задержка после первой неудачи, удваивается с каждой следующей
+	BackoffMax          time.Duration // Верхняя граница задержки
+	LockoutDuration     time.Duration // Длительность блокировки
+}
+
+func loginEmailKey(email string) string {
+	return "login:email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func loginIPKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+// mfaUserKey — неверные коды 2FA пользователя; в отличие от счётчика по email
+// не сбрасывается успешным вводом пароля
+func mfaUserKey(userID uuid.UUID) string {
+	return "mfa:user:" + userID.String()
+}
+
+// checkLoginAllowed — действует ли блокировка по email или IP.
+// При недоступности Redis вход не блокируется: защита не должна ломать аутентификацию.
+func (s *authService) checkLoginAllowed(ctx context.Context, email, ip string) error {
+	keys := []string{loginEmailKey(email)}
+	if ip != "" {
+		keys = append(keys, loginIPKey(ip))
+	}
+
+	var retryAfter time.Duration
+	for _, key := range keys {
+		blocked, err := s.attempts.BlockedFor(ctx, key)
+		if err != nil {
+			s.logger.Warnw("Login throttle check failed", "key", key, "error", err)
+			continue
+		}
+		retryAfter = max(retryAfter, blocked)
+	}
+
+	if retryAfter > 0 {
+		s.logger.Infow("Login throttled", "email", email, "ip", ip, "retry_after", retryAfter)
+		return loginThrottled(retryAfter)
+	}
+	return nil
+}
+
+// registerLoginFailure — учитывает неудачную попытку: back-off, блокировка, событие AccountLocked.
+// user == nil, если email не зарегистрирован.
+func (s *authService) registerLoginFailure(ctx context.Context, user *models.User, email, ip string) {
+	cfg := s.cfg.LoginProtection
+	log := s.logger.With("email", email, "ip", ip)
+
+	emailKey := loginEmailKey(email)
+	failures, err := s.attempts.Hit(ctx, emailKey, cfg.Window)
+	if err != nil {
+		log.Warnw("Failed to record login failure", "error", err)
+	} else if failures >= int64(cfg.MaxFailuresPerEmail) {
+		lockedUntil := time.Now().Add(cfg.LockoutDuration)
+		if err := s.attempts.Block(ctx, emailKey, cfg.LockoutDuration); err != nil {
+			log.Warnw("Failed to lock account", "error", err)
+		}
+		// Следующий цикл попыток после блокировки начинается с нуля
+		if err := s.attempts.Reset(ctx, emailKey); err != nil {
+			log.Warnw("Failed to reset login failures", "error", err)
+		}
+		log.Warnw("Account locked after repeated login failures", "failures", failures, "locked_until", lockedUntil)
+
+		if user != nil {
+			if err := s.emitEvent(ctx, "AccountLocked", user.ID, models.AccountLocked{
+				UserID:         user.ID,
+				Email:          user.Email,
+				IP:             ip,
+				FailedAttempts: int(failures),
+				LockedUntil:    lockedUntil,
+			}); err != nil {
+				log.Errorw("Failed to emit AccountLocked event", "user_id", user.ID, "error", err)
+			}
+		}
+	} else if delay := loginBackoff(cfg, failures); delay > 0 {
+		if err := s.attempts.Block(ctx, emailKey, delay); err != nil {
+			log.Warnw("Failed to apply login back-off", "error", err)
+		}
+	}
+
+	if ip == "" {
+		return
+	}
+	ipKey := loginIPKey(ip)
+	ipFailures, err := s.attempts.Hit(ctx, ipKey, cfg.Window)
+	if err != nil {
+		log.Warnw("Failed to record login failure for IP", "error", err)
+		return
+	}
+	if ipFailures >= int64(cfg.MaxFailuresPerIP) {
+		if err := s.attempts.Block(ctx, ipKey, cfg.LockoutDuration); err != nil {
+			log.Warnw("Failed to block IP", "error", err)
+		}
+		if err := s.attempts.Reset(ctx, ipKey); err != nil {
+			log.Warnw("Failed to reset IP login failures", "error", err)
+		}
+		log.Warnw("IP blocked after repeated login failures", "failures", ipFailures)
+	}
+}
+
+// registerMFAFailure — неверный код 2FA. Считается неудачным входом (back-off, лимит по IP),
+// а отдельный счётчик по пользователю переживает новые challenge: пароль выдаёт свежий challenge
+// на каждый вход и сбрасывает счётчик по email, поэтому без него код можно перебирать бесконечно.
+// После MaxFailuresPerEmail неверных кодов блокируются и 2FA, и вход по паролю.
+func (s *authService) registerMFAFailure(ctx context.Context, user *models.User, ip string) {
+	cfg := s.cfg.LoginProtection
+	log := s.logger.With("user_id", user.ID, "ip", ip)
+
+	s.registerLoginFailure(ctx, user, user.Email, ip)
+
+	mfaKey := mfaUserKey(user.ID)
+	failures, err := s.attempts.Hit(ctx, mfaKey, cfg.Window)
+	if err != nil {
+		log.Warnw("Failed to record MFA failure", "error", err)
+		return
+	}
+	if failures < int64(cfg.MaxFailuresPerEmail) {
+		return
+	}
+
+	for _, key := range []string{mfaKey, loginEmailKey(user.Email)} {
+		if err := s.attempts.Block(ctx, key, cfg.LockoutDuration); err != nil {
+			log.Warnw("Failed to lock account after MFA failures", "key", key, "error", err)
+		}
+	}
+	if err := s.attempts.Reset(ctx, mfaKey); err != nil {
+		log.Warnw("Failed to reset MFA failures", "error", err)
+	}
+	log.Warnw("Account locked after repeated MFA failures", "failures", failures, "locked_until", time.Now().Add(cfg.LockoutDuration))
+}
+
+// resetLoginFailures — успешный вход обнуляет счётчик по email (счётчик IP не трогаем)
+func (s *authService) resetLoginFailures(ctx context.Context, email string) {
+	if err := s.attempts.Reset(ctx, loginEmailKey(email)); err != nil {
+		s.logger.Warnw("Failed to reset login failures", "email", email, "error", err)
+	}
+}
+
+// loginBackoff — base * 2^(failures-1), не больше BackoffMax
+func loginBackoff(cfg LoginProtectionConfig, failures int64) time.Duration {
+	if failures < 1 || cfg.BackoffBase <= 0 {
+		return 0
+	}
+	delay := float64(cfg.BackoffBase) * math.Pow(2, float64(failures-1))
+	if cfg.BackoffMax > 0 && delay > float64(cfg.BackoffMax) {
+		return cfg.BackoffMax
+	}
+	return time.Duration(delay)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"auth-service/pkg/apperror"
+	"auth-service/pkg/mailer"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidMagicLink — ссылка для входа недействительна, истекла или уже использована
+var ErrInvalidMagicLink = apperror.Unauthorized("invalid or expired magic link")
+
+// MagicLinkConfig — вход по ссылке из письма
+type MagicLinkConfig struct {
+	TokenTTL            time.Duration // Время жизни ссылки
+	Window              time.Duration // Окно подсчёта запросов ссылки
+	MaxRequestsPerEmail int           // Запросов на один email за окно
+	MaxRequestsPerIP    int           // Запросов с одного IP за окно
+}
+
+func magicLinkEmailKey(email string) string {
+	return "magiclink:email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func magicLinkIPKey(ip string) string {
+	return "magiclink:ip:" + ip
+}
+
+// RequestMagicLink — отправляет одноразовую ссылку для входа без пароля.
+// Для неизвестных и неактивных аккаунтов молча ничего не делает.
+func (s *authService) RequestMagicLink(ctx context.Context, req models.MagicLinkRequest, client models.ClientInfo) error {
+	log := s.logger.With("email", req.Email, "ip", client.IP)
+
+	// Лимит считается до поиска пользователя: ответ не должен зависеть от существования аккаунта
+	if err := s.checkMagicLinkRate(ctx, req.Email, client.IP); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Infow("Magic link requested for unknown email")
+			return nil
+		}
+		log.Errorw("Database error during magic link request", "error", err)
+		return fmt.Errorf("database error: %w", err)
+	}
+	if user.Status != models.UserStatusActive {
+		log.Infow("Magic link skipped: account is not active", "user_id", user.ID, "status", user.Status)
+		return nil
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate magic link token: %w", err)
+	}
+
+	// Новая ссылка заменяет предыдущую
+	if err := s.magicLinkRepo.Save(ctx, utils.HashToken(token), user.ID, s.cfg.MagicLink.TokenTTL); err != nil {
+		log.Errorw("Failed to store magic link", "user_id", user.ID, "error", err)
+		return fmt.Errorf("failed to store magic link: %w", err)
+	}
+
+	// Письмо уходит в фоне: время ответа не должно выдавать существование аккаунта
+	go func(ctx context.Context) {
+		if err := s.sendMagicLinkEmail(ctx, user.Email, token); err != nil {
+			log.Errorw("Failed to send magic link email", "user_id", user.ID, "error", err)
+		}
+	}(context.WithoutCancel(ctx))
+
+	log.Infow("Magic link issued", "user_id", user.ID)
+	return nil
+}
+
+// ConsumeMagicLink — обменивает ссылку на пару токенов (или challenge, если включена 2FA).
+// Ссылка забирается атомарно, повторное использование отклоняется.
+func (s *authService) ConsumeMagicLink(ctx context.Context, req models.MagicLinkConsumeRequest, client models.ClientInfo) (*models.LoginResult, error) {
+	log := s.logger.With("ip", client.IP)
+
+	userID, err := s.magicLinkRepo.Consume(ctx, utils.HashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, repository.ErrMagicLinkNotFound) {
+			log.Infow("Magic link login failed: invalid, expired or reused link")
+			return nil, ErrInvalidMagicLink
+		}
+		log.Errorw("Failed to consume magic link", "error", err)
+		return nil, fmt.Errorf("failed to consume magic link: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Infow("Magic link login failed: user not found", "user_id", userID)
+			return nil, ErrInvalidMagicLink
+		}
+		log.Errorw("Failed to load user for magic link login", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	log.Infow("Magic link accepted", "user_id", user.ID)
+	return s.completeLogin(ctx, user, client, loginMethodMagicLink)
+}
+
+// checkMagicLinkRate — учитывает запрос ссылки и проверяет лимиты по email и IP.
+// При недоступности Redis запрос не блокируется.
+func (s *authService) checkMagicLinkRate(ctx context.Context, email, ip string) error {
+	cfg := s.cfg.MagicLink
+
+	limits := []rateLimit{{magicLinkEmailKey(email), cfg.MaxRequestsPerEmail}}
+	if ip != "" {
+		limits = append(limits, rateLimit{magicLinkIPKey(ip), cfg.MaxRequestsPerIP})
+	}
+
+	if s.checkRateLimits(ctx, limits, cfg.Window) {
+		return apperror.TooManyRequests("too many magic link requests, try again later", cfg.Window)
+	}
+	return nil
+}
+
+// sendMagicLinkEmail — письмо со ссылкой для входа
+func (s *authService) sendMagicLinkEmail(ctx context.Context, email, token string) error {
+	link := fmt.Sprintf("%s/magic-link?token=%s", s.cfg.LinkBaseURL, token)
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Вход в Huddle",
+		Body: fmt.Sprintf(
+			"Здравствуйте!\n\nЧтобы войти в Huddle без пароля, перейдите по ссылке:\n%s\n\nСсылка одноразовая и действительна %s. Если вы не запрашивали вход, просто проигнорируйте это письмо.\n",
+			link, s.cfg.MagicLink.TokenTTL,
+		),
+	})
+}
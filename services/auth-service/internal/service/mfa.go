@@ -0,0 +1,270 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"auth-service/pkg/apperror"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrMFAAlreadyEnabled — 2FA уже включена
+	ErrMFAAlreadyEnabled = apperror.Conflict("two-factor authentication is already enabled")
+	// ErrMFANotSetUp — перед включением нужно вызвать setup
+	ErrMFANotSetUp = apperror.Validation("two-factor authentication is not set up")
+	// ErrInvalidMFACode — неверный или уже использованный код при входе
+	ErrInvalidMFACode = apperror.Unauthorized("invalid two-factor authentication code")
+	// ErrInvalidMFASetupCode — неверный код при включении 2FA: пользователь уже вошёл, поэтому не 401
+	ErrInvalidMFASetupCode = apperror.Validation("invalid two-factor authentication code")
+	// ErrInvalidMFAChallenge — challenge не найден, истёк или исчерпаны попытки
+	ErrInvalidMFAChallenge = apperror.Unauthorized("invalid or expired mfa challenge")
+)
+
+// MFAConfig — настройки двухфакторной аутентификации
+type MFAConfig struct {
+	Issuer        string        // Название в приложении-аутентификаторе
+	ChallengeTTL  time.Duration // Время на ввод кода после пароля
+	MaxAttempts   int           // Попыток ввода кода на один challenge (общий лимит на пользователя — LoginProtection)
+	RecoveryCodes int           // Количество recovery кодов
+}
+
+// SetupMFA — генерирует новый TOTP секрет; 2FA включается только после подтверждения кодом
+func (s *authService) SetupMFA(ctx context.Context, userID uuid.UUID) (*models.MFASetupResponse, error) {
+	log := s.logger.With("user_id", userID)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Errorw("Failed to load user for MFA setup", "error", err)
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.cipher.Encrypt([]byte(secret), userID[:])
+	if err != nil {
+		log.Errorw("Failed to encrypt MFA secret", "error", err)
+		return nil, fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	if err := s.mfaRepo.SavePending(ctx, userID, encrypted); err != nil {
+		if errors.Is(err, repository.ErrMFAAlreadyEnabled) {
+			return nil, ErrMFAAlreadyEnabled
+		}
+		return nil, fmt.Errorf("failed to save mfa secret: %w", err)
+	}
+
+	log.Infow("MFA setup started")
+	return &models.MFASetupResponse{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(s.cfg.MFA.Issuer, user.Email, secret),
+	}, nil
+}
+
+// EnableMFA — включает 2FA после проверки кода; возвращает recovery коды (показываются один раз)
+func (s *authService) EnableMFA(ctx context.Context, userID uuid.UUID, req models.MFAEnableRequest) ([]string, error) {
+	log := s.logger.With("user_id", userID)
+
+	mfa, err := s.mfaRepo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMFANotFound) {
+			return nil, ErrMFANotSetUp
+		}
+		return nil, fmt.Errorf("failed to load mfa settings: %w", err)
+	}
+	if mfa.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := s.cipher.Decrypt(mfa.SecretEncrypted, userID[:])
+	if err != nil {
+		log.Errorw("Failed to decrypt MFA secret", "error", err)
+		return nil, fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+
+	step, ok := utils.ValidateTOTP(string(secret), req.Code, time.Now())
+	if !ok {
+		log.Infow("MFA enable failed: invalid code")
+		return nil, ErrInvalidMFASetupCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes(s.cfg.MFA.RecoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepo.Enable(ctx, userID, hashes, step); err != nil {
+		if errors.Is(err, repository.ErrMFAAlreadyEnabled) {
+			return nil, ErrMFAAlreadyEnabled
+		}
+		return nil, fmt.Errorf("failed to enable mfa: %w", err)
+	}
+
+	log.Infow("MFA enabled")
+	s.audit.Record(ctx, userAuditEntry(models.AuditMFAEnabled, userID, ""))
+	return codes, nil
+}
+
+// VerifyMFA — второй шаг входа: challenge + TOTP или recovery код → пара токенов
+func (s *authService) VerifyMFA(ctx context.Context, req models.MFAVerifyRequest, client models.ClientInfo) (*models.TokenPair, error) {
+	challengeHash := utils.HashToken(req.ChallengeToken)
+
+	userID, err := s.challengeRepo.Get(ctx, challengeHash)
+	if err != nil {
+		if errors.Is(err, repository.ErrMFAChallengeNotFound) {
+			return nil, ErrInvalidMFAChallenge
+		}
+		return nil, fmt.Errorf("failed to load mfa challenge: %w", err)
+	}
+	log := s.logger.With("user_id", userID)
+
+	// Блокировка после серии неверных кодов действует на все challenge пользователя
+	if blocked, err := s.attempts.BlockedFor(ctx, mfaUserKey(userID)); err != nil {
+		log.Warnw("MFA throttle check failed", "error", err)
+	} else if blocked > 0 {
+		log.Infow("MFA verification throttled", "retry_after", blocked)
+		return nil, loginThrottled(blocked)
+	}
+
+	// Ограничиваем перебор кодов в рамках одного challenge
+	attemptsKey := "mfa:challenge:" + challengeHash
+	attempts, err := s.attempts.Hit(ctx, attemptsKey, s.cfg.MFA.ChallengeTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record mfa attempt: %w", err)
+	}
+	if attempts > int64(s.cfg.MFA.MaxAttempts) {
+		log.Warnw("MFA challenge attempts exhausted")
+		if err := s.challengeRepo.Delete(ctx, challengeHash); err != nil && !errors.Is(err, repository.ErrMFAChallengeNotFound) {
+			log.Warnw("Failed to delete MFA challenge", "error", err)
+		}
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	mfa, err := s.mfaRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mfa settings: %w", err)
+	}
+	if !mfa.Enabled {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	if err := s.checkMFACode(ctx, mfa, req.Code); err != nil {
+		log.Infow("MFA verification failed", "attempt", attempts, "error", err)
+		s.registerMFAFailure(ctx, user, client.IP)
+		s.auditLoginFailure(ctx, user, user.Email, "invalid_mfa_code")
+		return nil, err
+	}
+
+	// Challenge одноразовый: параллельный запрос с тем же challenge получит отказ
+	if err := s.challengeRepo.Delete(ctx, challengeHash); err != nil {
+		if errors.Is(err, repository.ErrMFAChallengeNotFound) {
+			return nil, ErrInvalidMFAChallenge
+		}
+		return nil, fmt.Errorf("failed to consume mfa challenge: %w", err)
+	}
+	if err := s.attempts.Reset(ctx, attemptsKey, mfaUserKey(userID)); err != nil {
+		log.Warnw("Failed to reset MFA attempts", "error", err)
+	}
+	if user.Status != models.UserStatusActive {
+		log.Infow("Login rejected: account is not active", "status", user.Status)
+		s.auditLoginFailure(ctx, user, user.Email, "account_"+user.Status)
+		return nil, ErrAccountDisabled
+	}
+
+	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
+		log.Warnw("Failed to update last login", "error", err)
+	}
+
+	pair, err := s.tokenSvc.GeneratePair(ctx, user.ID, user.Email, user.Role, client)
+	if err != nil {
+		log.Errorw("Token generation failed", "error", err)
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	log.Infow("Login successful (MFA)")
+	s.auditLoginSuccess(ctx, user, pair.JTI, loginMethodMFA)
+	return pair, nil
+}
+
+// startMFAChallenge — создаёт challenge для второго шага входа
+func (s *authService) startMFAChallenge(ctx context.Context, userID uuid.UUID) (*models.MFAChallenge, error) {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+	if err := s.challengeRepo.Save(ctx, utils.HashToken(token), userID, s.cfg.MFA.ChallengeTTL); err != nil {
+		return nil, fmt.Errorf("failed to save challenge: %w", err)
+	}
+	return &models.MFAChallenge{
+		ChallengeToken: token,
+		ExpiresIn:      int64(s.cfg.MFA.ChallengeTTL.Seconds()),
+	}, nil
+}
+
+// checkMFACode — TOTP код (с защитой от повторного использования) или одноразовый recovery код
+func (s *authService) checkMFACode(ctx context.Context, mfa *models.UserMFA, code string) error {
+	secret, err := s.cipher.Decrypt(mfa.SecretEncrypted, mfa.UserID[:])
+	if err != nil {
+		s.logger.Errorw("Failed to decrypt MFA secret", "user_id", mfa.UserID, "error", err)
+		return fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+
+	if step, ok := utils.ValidateTOTP(string(secret), code, time.Now()); ok {
+		fresh, err := s.mfaRepo.UseStep(ctx, mfa.UserID, step)
+		if err != nil {
+			return fmt.Errorf("failed to store totp step: %w", err)
+		}
+		if !fresh {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	used, err := s.mfaRepo.ConsumeRecoveryCode(ctx, mfa.UserID, utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return fmt.Errorf("failed to check recovery code: %w", err)
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCodes — коды вида xxxxx-xxxxx и их хэши для хранения
+func generateRecoveryCodes(n int) (codes, hashes []string, err error) {
+	codes = make([]string, n)
+	hashes = make([]string, n)
+	for i := range codes {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(raw))[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = utils.HashToken(code)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode — регистр, дефисы и пробелы при вводе не важны
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
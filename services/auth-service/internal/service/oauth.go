@@ -0,0 +1,196 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"auth-service/pkg/apperror"
+	"auth-service/pkg/oauth"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrOAuthEmailRequired — провайдер не отдал email, а без него аккаунт не создать
+	ErrOAuthEmailRequired = apperror.Validation("oauth provider did not return an email address")
+	// ErrOAuthAccountExists — email занят, но провайдер не подтвердил владение им
+	ErrOAuthAccountExists = apperror.Conflict("an account with this email already exists, sign in with your password")
+	// ErrOAuthAccountUnverified — email занят аккаунтом, который его так и не подтвердил
+	ErrOAuthAccountUnverified = apperror.Conflict("an account with this email exists but is not verified, reset its password and verify the email first")
+)
+
+// StartOAuth — URL авторизации у провайдера и привязка state к браузеру
+func (s *authService) StartOAuth(ctx context.Context, provider string) (*oauth.Authorization, error) {
+	authorization, err := s.oauth.Start(ctx, provider)
+	if err != nil {
+		s.logger.Warnw("OAuth start failed", "provider", provider, "error", err)
+		return nil, err
+	}
+	return authorization, nil
+}
+
+// OAuthCallback — вход через внешний провайдер.
+// Существующая связь → вход; email, подтверждённый и провайдером, и существующим аккаунтом, → связывание;
+// иначе создаётся новый пользователь через тот же outbox UserRegistered, что и при регистрации.
+func (s *authService) OAuthCallback(ctx context.Context, provider, state, stateBinding, code string, client models.ClientInfo) (*models.LoginResult, error) {
+	log := s.logger.With("provider", provider)
+
+	ext, err := s.oauth.Complete(ctx, provider, state, stateBinding, code)
+	if err != nil {
+		log.Warnw("OAuth callback failed", "error", err)
+		return nil, err
+	}
+	log = log.With("subject", ext.Subject)
+
+	var user *models.User
+	userID, err := s.identityRepo.GetUserID(ctx, ext.Provider, ext.Subject)
+	switch {
+	case err == nil:
+		user, err = s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			log.Errorw("Failed to load user for identity", "user_id", userID, "error", err)
+			return nil, fmt.Errorf("failed to load user: %w", err)
+		}
+		if err := s.identityRepo.TouchLogin(ctx, ext.Provider, ext.Subject); err != nil {
+			log.Warnw("Failed to update identity last login", "error", err)
+		}
+	case errors.Is(err, repository.ErrIdentityNotFound):
+		user, err = s.linkOrCreateOAuthUser(ctx, ext)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		log.Errorw("Identity lookup failed", "error", err)
+		return nil, fmt.Errorf("failed to look up identity: %w", err)
+	}
+
+	log.Infow("OAuth first factor accepted", "user_id", user.ID)
+	return s.completeLogin(ctx, user, client, loginMethodOAuth+provider)
+}
+
+// linkOrCreateOAuthUser — первый вход через провайдера
+func (s *authService) linkOrCreateOAuthUser(ctx context.Context, ext *oauth.ExternalUser) (user *models.User, err error) {
+	log := s.logger.With("provider", ext.Provider, "subject", ext.Subject)
+
+	if ext.Email == "" {
+		return nil, ErrOAuthEmailRequired
+	}
+
+	existing, err := s.userRepo.GetByEmail(ctx, ext.Email)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		log.Errorw("Database error during OAuth login", "error", err)
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if existing != nil && !ext.EmailVerified {
+		log.Warnw("OAuth login rejected: email belongs to another account and is not verified by provider")
+		return nil, ErrOAuthAccountExists
+	}
+	// Аккаунт мог зарегистрировать кто угодно, не владея почтой: после связывания его пароль
+	// и сессии остались бы у того, кто его создал (pre-account hijacking). Владелец почты
+	// сбрасывает пароль (сессии отзываются), подтверждает email и затем входит через провайдера.
+	if existing != nil && !existing.IsVerified {
+		log.Warnw("OAuth login rejected: existing account has not verified its email", "user_id", existing.ID)
+		return nil, ErrOAuthAccountUnverified
+	}
+
+	tx, err := s.userRepo.BeginTx(ctx)
+	if err != nil {
+		log.Errorw("Failed to begin transaction", "error", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				log.Errorw("Failed to rollback transaction", "error", rollbackErr)
+			}
+		}
+	}()
+
+	now := time.Now()
+	var verifyToken string
+	if existing != nil {
+		user = existing
+	} else {
+		if user, verifyToken, err = s.newOAuthUser(ext, now); err != nil {
+			return nil, err
+		}
+		if err = s.userRepo.CreateTx(ctx, tx, user); err != nil {
+			log.Errorw("Failed to create user in database", "error", err)
+			return nil, fmt.Errorf("failed to create user: %w", err)
+		}
+		if _, err = s.insertUserRegisteredTx(ctx, tx, user, ext.FirstName, ext.LastName); err != nil {
+			log.Errorw("Failed to insert outbox event", "error", err)
+			return nil, err
+		}
+	}
+
+	if err = s.identityRepo.CreateTx(ctx, tx, &models.UserIdentity{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		Provider:    ext.Provider,
+		Subject:     ext.Subject,
+		Email:       ext.Email,
+		LastLoginAt: &now,
+		CreatedAt:   now,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Errorw("Failed to commit transaction", "error", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if existing != nil {
+		log.Infow("OAuth identity linked to existing user", "user_id", user.ID)
+		return user, nil
+	}
+
+	log.Infow("User registered via OAuth", "user_id", user.ID)
+	if verifyToken != "" {
+		if err := s.sendVerificationEmail(ctx, user.Email, verifyToken); err != nil {
+			log.Warnw("Failed to send verification email", "user_id", user.ID, "error", err)
+		}
+	}
+	return user, nil
+}
+
+// newOAuthUser — пользователь без пароля (случайный хэш; пароль можно задать через сброс).
+// Если провайдер не подтвердил email, возвращается токен для письма подтверждения.
+func (s *authService) newOAuthUser(ext *oauth.ExternalUser, now time.Time) (*models.User, string, error) {
+	randomPassword, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	hashedPassword, err := utils.HashPassword(randomPassword)
+	if err != nil {
+		return nil, "", fmt.Errorf("password hashing failed: %w", err)
+	}
+
+	user := &models.User{
+		ID:           uuid.New(),
+		Email:        ext.Email,
+		PasswordHash: hashedPassword,
+		IsVerified:   ext.EmailVerified,
+		Role:         "user",
+		Status:       "active",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if ext.EmailVerified {
+		return user, "", nil
+	}
+
+	verifyToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate verify token: %w", err)
+	}
+	verifyTokenHash := utils.HashToken(verifyToken)
+	user.EmailVerifyToken = &verifyTokenHash
+	user.EmailVerifySentAt = &now
+	return user, verifyToken, nil
+}
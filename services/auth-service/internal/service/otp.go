@@ -0,0 +1,281 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"auth-service/pkg/apperror"
+	"auth-service/pkg/sms"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidOTP — код неверный, истёк, уже использован или исчерпаны попытки
+	ErrInvalidOTP = apperror.Unauthorized("invalid or expired code")
+	// ErrInvalidPhoneCode — неверный код подтверждения номера: пользователь уже вошёл, поэтому не 401
+	ErrInvalidPhoneCode = apperror.Validation("invalid or expired code")
+	// ErrPhoneTaken — номер подтверждён другим аккаунтом
+	ErrPhoneTaken = apperror.Conflict("phone already in use")
+	// ErrPhoneNotSet — у пользователя нет номера для подтверждения
+	ErrPhoneNotSet = apperror.Validation("phone number is not set")
+)
+
+// Назначение SMS-кода: коды входа и подтверждения номера не взаимозаменяемы
+const (
+	otpPurposeLogin  = "login"
+	otpPurposeVerify = "verify"
+)
+
+// OTPConfig — SMS-коды для входа и подтверждения телефона
+type OTPConfig struct {
+	CodeTTL          time.Duration // Время жизни кода
+	CodeLength       int           // Количество цифр
+	MaxAttempts      int           // Попыток ввода на один код
+	Window           time.Duration // Окно подсчёта отправок
+	MaxSendsPerPhone int           // Отправок на один номер за окно
+	MaxSendsPerIP    int           // Отправок с одного IP за окно
+}
+
+func otpPhoneKey(phone string) string {
+	return "otp:phone:" + phone
+}
+
+func otpIPKey(ip string) string {
+	return "otp:ip:" + ip
+}
+
+func otpAttemptsKey(purpose, phone string) string {
+	return "otp:attempts:" + purpose + ":" + phone
+}
+
+// SetPhone — привязывает номер к аккаунту и отправляет код подтверждения.
+// Повторный вызов с тем же номером отправляет новый код.
+func (s *authService) SetPhone(ctx context.Context, userID uuid.UUID, req models.SetPhoneRequest, client models.ClientInfo) error {
+	log := s.logger.With("user_id", userID)
+
+	if err := s.checkOTPRate(ctx, req.Phone, client.IP); err != nil {
+		return err
+	}
+
+	// Занятость номера здесь не проверяется: иначе по ответу можно перебирать чужие номера.
+	// Номер закрепляется за аккаунтом только после ввода кода (VerifyPhone)
+	if err := s.userRepo.SetPhone(ctx, userID, req.Phone); err != nil {
+		return fmt.Errorf("failed to set phone: %w", err)
+	}
+
+	code, err := s.issueOTP(ctx, otpPurposeVerify, req.Phone, userID)
+	if err != nil {
+		return err
+	}
+
+	// Пользователь авторизован — отправляем синхронно, чтобы сообщить об ошибке доставки
+	if err := s.sendOTP(ctx, req.Phone, code, "подтверждения номера"); err != nil {
+		log.Errorw("Failed to send phone verification code", "error", err)
+		return fmt.Errorf("failed to send code: %w", err)
+	}
+
+	log.Infow("Phone verification code sent")
+	return nil
+}
+
+// VerifyPhone — подтверждение номера кодом из SMS
+func (s *authService) VerifyPhone(ctx context.Context, userID uuid.UUID, req models.VerifyPhoneRequest) error {
+	log := s.logger.With("user_id", userID)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load user: %w", err)
+	}
+	if user.Phone == nil {
+		return ErrPhoneNotSet
+	}
+	phone := *user.Phone
+
+	codeUserID, err := s.consumeOTP(ctx, otpPurposeVerify, phone, req.Code)
+	if err != nil {
+		if errors.Is(err, ErrInvalidOTP) {
+			return ErrInvalidPhoneCode
+		}
+		return err
+	}
+	if codeUserID != userID {
+		return ErrInvalidPhoneCode
+	}
+
+	// Номер могли сменить между отправкой кода и подтверждением
+	verified, err := s.userRepo.MarkPhoneVerified(ctx, userID, phone)
+	if err != nil {
+		// Код дошёл до владельца телефона, поэтому сообщить о занятости уже безопасно
+		if errors.Is(err, repository.ErrPhoneTaken) {
+			log.Infow("Phone verification rejected: phone verified by another account")
+			return ErrPhoneTaken
+		}
+		return fmt.Errorf("failed to verify phone: %w", err)
+	}
+	if !verified {
+		return ErrInvalidPhoneCode
+	}
+
+	log.Infow("Phone verified")
+	s.audit.Record(ctx, userAuditEntry(models.AuditPhoneVerified, userID, user.Email))
+	return nil
+}
+
+// RequestOTP — отправляет код для входа по SMS.
+// Для неизвестных, неподтверждённых номеров и неактивных аккаунтов молча ничего не делает.
+func (s *authService) RequestOTP(ctx context.Context, req models.OTPRequest, client models.ClientInfo) error {
+	log := s.logger.With("ip", client.IP)
+
+	// Лимит считается до поиска пользователя: ответ не должен зависеть от существования аккаунта
+	if err := s.checkOTPRate(ctx, req.Phone, client.IP); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByPhone(ctx, req.Phone)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Infow("Login code requested for unknown phone")
+			return nil
+		}
+		log.Errorw("Database error during login code request", "error", err)
+		return fmt.Errorf("database error: %w", err)
+	}
+	if !user.PhoneVerified || user.Status != models.UserStatusActive {
+		log.Infow("Login code skipped", "user_id", user.ID, "phone_verified", user.PhoneVerified, "status", user.Status)
+		return nil
+	}
+
+	code, err := s.issueOTP(ctx, otpPurposeLogin, req.Phone, user.ID)
+	if err != nil {
+		return err
+	}
+
+	// SMS уходит в фоне: время ответа не должно выдавать существование аккаунта
+	go func(ctx context.Context) {
+		if err := s.sendOTP(ctx, req.Phone, code, "входа"); err != nil {
+			log.Errorw("Failed to send login code", "user_id", user.ID, "error", err)
+		}
+	}(context.WithoutCancel(ctx))
+
+	log.Infow("Login code issued", "user_id", user.ID)
+	return nil
+}
+
+// VerifyOTP — вход по коду из SMS: пара токенов (или challenge, если включена 2FA)
+func (s *authService) VerifyOTP(ctx context.Context, req models.OTPVerifyRequest, client models.ClientInfo) (*models.LoginResult, error) {
+	log := s.logger.With("ip", client.IP)
+
+	userID, err := s.consumeOTP(ctx, otpPurposeLogin, req.Phone, req.Code)
+	if err != nil {
+		if errors.Is(err, ErrInvalidOTP) {
+			log.Infow("OTP login failed: invalid code")
+			s.auditLoginFailure(ctx, nil, "", "invalid_otp")
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrInvalidOTP
+		}
+		log.Errorw("Failed to load user for OTP login", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	// Номер отвязали или сменили после отправки кода
+	if user.Phone == nil || *user.Phone != req.Phone || !user.PhoneVerified {
+		log.Infow("OTP login failed: phone no longer belongs to user", "user_id", user.ID)
+		return nil, ErrInvalidOTP
+	}
+
+	log.Infow("OTP accepted", "user_id", user.ID)
+	return s.completeLogin(ctx, user, client, loginMethodOTP)
+}
+
+// issueOTP — новый код для номера; счётчик неверных попыток сбрасывается
+func (s *authService) issueOTP(ctx context.Context, purpose, phone string, userID uuid.UUID) (string, error) {
+	code, err := utils.GenerateNumericCode(s.cfg.OTP.CodeLength)
+	if err != nil {
+		return "", err
+	}
+
+	otp := models.OTPCode{UserID: userID, CodeHash: utils.HashToken(code)}
+	if err := s.otpRepo.Save(ctx, purpose, phone, otp, s.cfg.OTP.CodeTTL); err != nil {
+		return "", fmt.Errorf("failed to store code: %w", err)
+	}
+	if err := s.attempts.Reset(ctx, otpAttemptsKey(purpose, phone)); err != nil {
+		s.logger.Warnw("Failed to reset OTP attempts", "user_id", userID, "error", err)
+	}
+	return code, nil
+}
+
+// consumeOTP — проверяет и гасит код. После MaxAttempts неверных попыток код аннулируется.
+func (s *authService) consumeOTP(ctx context.Context, purpose, phone, code string) (uuid.UUID, error) {
+	attemptsKey := otpAttemptsKey(purpose, phone)
+	attempts, err := s.attempts.Hit(ctx, attemptsKey, s.cfg.OTP.CodeTTL)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to record code attempt: %w", err)
+	}
+	if attempts > int64(s.cfg.OTP.MaxAttempts) {
+		s.logger.Warnw("OTP attempts exhausted", "purpose", purpose)
+		if _, err := s.otpRepo.Delete(ctx, purpose, phone); err != nil {
+			s.logger.Warnw("Failed to delete OTP", "purpose", purpose, "error", err)
+		}
+		return uuid.Nil, ErrInvalidOTP
+	}
+
+	otp, err := s.otpRepo.Get(ctx, purpose, phone)
+	if err != nil {
+		if errors.Is(err, repository.ErrOTPNotFound) {
+			return uuid.Nil, ErrInvalidOTP
+		}
+		return uuid.Nil, fmt.Errorf("failed to load code: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(code)), []byte(otp.CodeHash)) != 1 {
+		return uuid.Nil, ErrInvalidOTP
+	}
+
+	// Код одноразовый: параллельный запрос с тем же кодом получит отказ
+	deleted, err := s.otpRepo.Delete(ctx, purpose, phone)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to consume code: %w", err)
+	}
+	if !deleted {
+		return uuid.Nil, ErrInvalidOTP
+	}
+	if err := s.attempts.Reset(ctx, attemptsKey); err != nil {
+		s.logger.Warnw("Failed to reset OTP attempts", "error", err)
+	}
+	return otp.UserID, nil
+}
+
+// checkOTPRate — учитывает отправку кода и проверяет лимиты по номеру и IP.
+// При недоступности Redis запрос не блокируется.
+func (s *authService) checkOTPRate(ctx context.Context, phone, ip string) error {
+	cfg := s.cfg.OTP
+
+	limits := []rateLimit{{otpPhoneKey(phone), cfg.MaxSendsPerPhone}}
+	if ip != "" {
+		limits = append(limits, rateLimit{otpIPKey(ip), cfg.MaxSendsPerIP})
+	}
+
+	if s.checkRateLimits(ctx, limits, cfg.Window) {
+		return apperror.TooManyRequests("too many code requests, try again later", cfg.Window)
+	}
+	return nil
+}
+
+// sendOTP — SMS с кодом
+func (s *authService) sendOTP(ctx context.Context, phone, code, action string) error {
+	return s.sms.Send(ctx, sms.Message{
+		To: phone,
+		Text: fmt.Sprintf("Huddle: код %s для %s. Действителен %s. Никому его не сообщайте.",
+			code, action, s.cfg.OTP.CodeTTL),
+	})
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const defaultOutboxListLimit = 50
+
+// OutboxAdminService — разбор событий outbox, которые не удалось опубликовать
+type OutboxAdminService interface {
+	ListFailed(ctx context.Context, filter models.OutboxFilter) (*models.OutboxList, error)
+	Requeue(ctx context.Context, adminID, eventID uuid.UUID) error
+	RequeueAllFailed(ctx context.Context, adminID uuid.UUID) (int64, error)
+}
+
+// outboxAdminService — реализация
+type outboxAdminService struct {
+	repo   repository.OutboxRepository
+	logger *zap.SugaredLogger
+}
+
+// NewOutboxAdminService — конструктор
+func NewOutboxAdminService(repo repository.OutboxRepository, logger *zap.SugaredLogger) OutboxAdminService {
+	return &outboxAdminService{repo: repo, logger: logger}
+}
+
+// ListFailed — события в статусе failed
+func (s *outboxAdminService) ListFailed(ctx context.Context, filter models.OutboxFilter) (*models.OutboxList, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultOutboxListLimit
+	}
+
+	events, total, err := s.repo.ListFailed(ctx, filter)
+	if err != nil {
+		s.logger.Errorw("Failed to list failed outbox events", "error", err)
+		return nil, fmt.Errorf("failed to list failed events: %w", err)
+	}
+
+	return &models.OutboxList{Events: events, Total: total, Limit: filter.Limit, Offset: filter.Offset}, nil
+}
+
+// Requeue — вернуть событие в очередь публикации
+func (s *outboxAdminService) Requeue(ctx context.Context, adminID, eventID uuid.UUID) error {
+	if err := s.repo.Requeue(ctx, eventID); err != nil {
+		return err
+	}
+	s.logger.Infow("Outbox event requeued", "event_id", eventID, "admin_id", adminID)
+	return nil
+}
+
+// RequeueAllFailed — вернуть в очередь все failed-события
+func (s *outboxAdminService) RequeueAllFailed(ctx context.Context, adminID uuid.UUID) (int64, error) {
+	count, err := s.repo.RequeueAllFailed(ctx)
+	if err != nil {
+		s.logger.Errorw("Failed to requeue outbox events", "admin_id", adminID, "error", err)
+		return 0, err
+	}
+	s.logger.Infow("Failed outbox events requeued", "count", count, "admin_id", adminID)
+	return count, nil
+}
@@ -2,31 +2,70 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
+	"auth-service/internal/models"
 	"auth-service/internal/repository"
 
+	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 	"go.uber.org/zap"
 )
 
+// Заголовки Kafka-сообщений outbox
+const (
+	HeaderEventType     = "event_type"
+	HeaderEventID       = "event_id"
+	HeaderSchemaVersion = "schema_version"
+	HeaderOccurredAt    = "occurred_at"
+	// request_id запроса, породившего событие, — связывает логи auth-service и потребителей
+	HeaderRequestID = "request_id"
+	// W3C traceparent запроса — потребитель продолжает трассу, начатую в HTTP-запросе
+	HeaderTraceparent = "traceparent"
+)
+
+// maxOutboxErrorLength — сколько текста ошибки сохраняется в auth.outbox.last_error
+const maxOutboxErrorLength = 1000
+
+// OutboxPublisherConfig — публикация событий outbox и её повторы
+type OutboxPublisherConfig struct {
+	BatchSize    int           // Событий в одной пачке (одной транзакции)
+	PollInterval time.Duration // Страховочный обход: повторы по next_attempt_at и пропущенные NOTIFY
+	MaxAttempts  int           // После стольких неудач событие переходит в failed
+	BaseBackoff  time.Duration // Задержка после первой неудачи, далее удваивается
+	MaxBackoff   time.Duration // Верхняя граница задержки
+}
+
+// MessageWriter — запись сообщений в Kafka; реализуется *kafka.Writer
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
 type OutboxPublisher struct {
 	repo   repository.OutboxRepository
-	writer *kafka.Writer
+	writer MessageWriter
+	dlq    MessageWriter // nil, если dead-letter топик не настроен
+	cfg    OutboxPublisherConfig
 	logger *zap.SugaredLogger
-	ticker *time.Ticker
 }
 
+// NewOutboxPublisher — конструктор; dlq может быть nil
 func NewOutboxPublisher(
 	repo repository.OutboxRepository,
-	writer *kafka.Writer,
+	writer MessageWriter,
+	dlq MessageWriter,
+	cfg OutboxPublisherConfig,
 	logger *zap.SugaredLogger,
 ) *OutboxPublisher {
 	return &OutboxPublisher{
 		repo:   repo,
 		writer: writer,
+		dlq:    dlq,
+		cfg:    cfg,
 		logger: logger,
-		ticker: time.NewTicker(5 * time.Second),
 	}
 }
 
@@ -34,48 +73,224 @@ func (p *OutboxPublisher) Start(ctx context.Context) {
 	go p.run(ctx)
 }
 
+// run — публикация по NOTIFY outbox_new сразу после Commit транзакции с событием;
+// ticker — страховка на случай потери уведомлений и для повторов по next_attempt_at
 func (p *OutboxPublisher) run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.PollInterval)
+	defer ticker.Stop()
+
+	wake := make(chan struct{}, 1)
+	go p.listen(ctx, wake)
+
+	// События, накопившиеся, пока сервис не работал
+	p.publishPending(ctx)
 	for {
 		select {
 		case <-ctx.Done():
-			p.ticker.Stop()
 			return
-		case <-p.ticker.C:
-			if err := p.publishBatch(ctx); err != nil {
+		case <-wake:
+		case <-ticker.C:
+		}
+		p.publishPending(ctx)
+	}
+}
+
+// publishPending — публикует пачки, пока есть готовые события. Пачка берёт только первое
+// событие каждого пользователя, поэтому следующие его события уходят в следующих пачках.
+func (p *OutboxPublisher) publishPending(ctx context.Context) {
+	for {
+		n, err := p.PublishBatch(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
 				p.logger.Errorw("Failed to publish batch", "error", err)
 			}
+			return
+		}
+		if n == 0 {
+			return
 		}
 	}
 }
 
-func (p *OutboxPublisher) publishBatch(ctx context.Context) error {
-	events, err := p.repo.GetPendingEvents(ctx, 100)
+// listen — пробуждение по NOTIFY. При потере соединения переподключается через PollInterval;
+// до тех пор события публикуются по ticker.
+func (p *OutboxPublisher) listen(ctx context.Context, wake chan<- struct{}) {
+	for {
+		err := p.repo.Listen(ctx, func() {
+			// Пробуждение уже запланировано — следующая пачка заберёт и это событие
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		p.logger.Warnw("Outbox listener disconnected, falling back to polling", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.cfg.PollInterval):
+		}
+	}
+}
+
+// PublishBatch — публикует одну пачку готовых событий; возвращает размер взятой пачки.
+// Пачка берётся в транзакции с FOR UPDATE SKIP LOCKED и отмечается в той же транзакции:
+// параллельные экземпляры получают непересекающиеся пачки. Повторная отправка возможна,
+// только если Commit не прошёл после записи в Kafka (at-least-once).
+func (p *OutboxPublisher) PublishBatch(ctx context.Context) (n int, err error) {
+	tx, err := p.repo.BeginTx(ctx)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			// Контекст мог быть уже отменён — откат не должен от него зависеть
+			if rollbackErr := tx.Rollback(context.WithoutCancel(ctx)); rollbackErr != nil {
+				p.logger.Errorw("Failed to rollback outbox transaction", "error", rollbackErr)
+			}
+		}
+	}()
+
+	events, err := p.repo.ClaimPendingTx(ctx, tx, p.cfg.BatchSize)
+	if err != nil {
+		return 0, err
 	}
 	if len(events) == 0 {
-		return nil
+		return 0, tx.Commit(ctx)
 	}
 
-	for _, event := range events {
-		msg := kafka.Message{
-			Key:   []byte(event.ID.String()),
-			Value: event.Payload,
-		}
+	msgs := make([]kafka.Message, len(events))
+	for i, event := range events {
+		msgs[i] = outboxMessage(event)
+	}
 
-		if err := p.writer.WriteMessages(ctx, msg); err != nil {
-			p.logger.Errorw("Failed to publish event", "event_id", event.ID, "error", err)
+	writeErr := p.writer.WriteMessages(ctx, msgs...)
+	// Остановка сервиса — не вина событий: откатываемся, попытки не засчитываем
+	if writeErr != nil && ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	published := make([]*models.OutboxEvent, 0, len(events))
+	for i, event := range events {
+		if msgErr := messageError(writeErr, i); msgErr != nil {
+			if err = p.handleFailure(ctx, tx, event, msgs[i], msgErr); err != nil {
+				return 0, err
+			}
 			continue
 		}
+		published = append(published, event)
+	}
 
-		if err := p.repo.MarkAsPublished(ctx, event.ID); err != nil {
-			p.logger.Errorw("Failed to mark event as published", "event_id", event.ID, "error", err)
-		}
+	publishedIDs := make([]uuid.UUID, len(published))
+	for i, event := range published {
+		publishedIDs[i] = event.ID
+	}
+	if err = p.repo.MarkAsPublishedTx(ctx, tx, publishedIDs); err != nil {
+		return 0, fmt.Errorf("failed to mark events as published: %w", err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox batch: %w", err)
+	}
 
+	for _, event := range published {
 		p.logger.Infow("Event published successfully",
 			"event_id", event.ID,
 			"event_type", event.EventType)
 	}
+	return len(events), nil
+}
 
+// outboxMessage — Kafka-сообщение события. Ключ — агрегат (пользователь): его события
+// попадают в одну партицию и читаются по порядку. Заголовки описывают событие,
+// чтобы потребителю не нужно было разбирать payload для выбора обработчика.
+func outboxMessage(event *models.OutboxEvent) kafka.Message {
+	headers := []kafka.Header{
+		{Key: HeaderEventType, Value: []byte(event.EventType)},
+		{Key: HeaderEventID, Value: []byte(event.ID.String())},
+		{Key: HeaderSchemaVersion, Value: []byte(strconv.Itoa(event.SchemaVersion))},
+		{Key: HeaderOccurredAt, Value: []byte(event.CreatedAt.UTC().Format(time.RFC3339Nano))},
+	}
+	if event.RequestID != "" {
+		headers = append(headers, kafka.Header{Key: HeaderRequestID, Value: []byte(event.RequestID)})
+	}
+	if event.Traceparent != "" {
+		headers = append(headers, kafka.Header{Key: HeaderTraceparent, Value: []byte(event.Traceparent)})
+	}
+
+	return kafka.Message{
+		Key:     []byte(event.AggregateID.String()),
+		Value:   event.Payload,
+		Headers: headers,
+	}
+}
+
+// messageError — ошибка записи i-го сообщения пачки. kafka.WriteErrors содержит
+// ошибку по каждому сообщению; любая другая ошибка относится ко всей пачке.
+func messageError(err error, i int) error {
+	if err == nil {
+		return nil
+	}
+	var writeErrs kafka.WriteErrors
+	if errors.As(err, &writeErrs) && i < len(writeErrs) {
+		return writeErrs[i]
+	}
+	return err
+}
+
+// handleFailure — неудачная попытка: повтор с экспоненциальной задержкой,
+// после MaxAttempts — статус failed и копия в dead-letter топик
+func (p *OutboxPublisher) handleFailure(ctx context.Context, tx repository.Tx, event *models.OutboxEvent, msg kafka.Message, publishErr error) error {
+	attempt := event.Attempts + 1
+	lastError := publishErr.Error()
+	if len(lastError) > maxOutboxErrorLength {
+		lastError = lastError[:maxOutboxErrorLength]
+	}
+	log := p.logger.With("event_id", event.ID, "event_type", event.EventType, "attempt", attempt)
+
+	if attempt < p.cfg.MaxAttempts {
+		nextAttemptAt := time.Now().Add(p.backoff(attempt))
+		if err := p.repo.MarkAttemptFailedTx(ctx, tx, event.ID, lastError, nextAttemptAt); err != nil {
+			return fmt.Errorf("failed to record publish attempt: %w", err)
+		}
+		log.Warnw("Failed to publish event, will retry", "next_attempt_at", nextAttemptAt, "error", publishErr)
+		return nil
+	}
+
+	p.deadLetter(ctx, event, msg, lastError, attempt)
+	if err := p.repo.MarkAsFailedTx(ctx, tx, event.ID, lastError); err != nil {
+		return fmt.Errorf("failed to mark event as failed: %w", err)
+	}
+	log.Errorw("Event moved to failed after max attempts", "error", publishErr)
 	return nil
 }
+
+// deadLetter — копия события в dead-letter топик для разбора; источник истины — auth.outbox,
+// поэтому ошибка записи в DLQ только логируется
+func (p *OutboxPublisher) deadLetter(ctx context.Context, event *models.OutboxEvent, msg kafka.Message, lastError string, attempts int) {
+	if p.dlq == nil {
+		return
+	}
+
+	msg.Headers = append(msg.Headers,
+		kafka.Header{Key: "error", Value: []byte(lastError)},
+		kafka.Header{Key: "attempts", Value: []byte(strconv.Itoa(attempts))},
+	)
+	if err := p.dlq.WriteMessages(ctx, msg); err != nil {
+		p.logger.Errorw("Failed to write event to dead-letter topic", "event_id", event.ID, "error", err)
+	}
+}
+
+// backoff — задержка после attempt-й неудачи: BaseBackoff·2^(attempt-1), не больше MaxBackoff
+func (p *OutboxPublisher) backoff(attempt int) time.Duration {
+	delay := p.cfg.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= p.cfg.MaxBackoff {
+			return p.cfg.MaxBackoff
+		}
+	}
+	return min(delay, p.cfg.MaxBackoff)
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+	"auth-service/pkg/apperror"
+	"auth-service/pkg/mailer"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidResetToken — токен сброса не найден, истёк или уже использован
+	ErrInvalidResetToken = apperror.Validation("invalid or expired reset token")
+	// ErrWrongPassword — текущий пароль указан неверно
+	ErrWrongPassword = apperror.Validation("current password is incorrect")
+)
+
+// ForgotPassword — выдаёт одноразовый токен сброса и отправляет его на почту.
+// Результат не зависит от существования email, чтобы не раскрывать базу пользователей.
+func (s *authService) ForgotPassword(ctx context.Context, req models.ResetPasswordRequest) error {
+	log := s.logger.With("email", req.Email)
+
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Infow("Password reset requested for unknown email")
+			return nil
+		}
+		log.Errorw("Database error during password reset request", "error", err)
+		return fmt.Errorf("database error: %w", err)
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	if err := s.resetRepo.Save(ctx, utils.HashToken(token), user.ID, s.cfg.ResetTokenTTL); err != nil {
+		log.Errorw("Failed to store reset token", "user_id", user.ID, "error", err)
+		return fmt.Errorf("failed to store reset token: %w", err)
+	}
+
+	// Письмо уходит в фоне: время ответа не должно выдавать существование аккаунта
+	go func(ctx context.Context) {
+		if err := s.sendPasswordResetEmail(ctx, user.Email, token); err != nil {
+			log.Errorw("Failed to send password reset email", "user_id", user.ID, "error", err)
+		}
+	}(context.WithoutCancel(ctx))
+
+	log.Infow("Password reset token issued", "user_id", user.ID)
+	return nil
+}
+
+// ResetPassword — устанавливает новый пароль по токену и отзывает все сессии пользователя
+func (s *authService) ResetPassword(ctx context.Context, req models.ResetPasswordConfirmRequest) error {
+	log := s.logger
+	tokenHash := utils.HashToken(req.Token)
+
+	// Сначала только читаем токен: при слабом пароле он должен остаться действительным
+	userID, err := s.resetRepo.Peek(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, repository.ErrResetTokenNotFound) {
+			log.Infow("Password reset failed: invalid token")
+			return ErrInvalidResetToken
+		}
+		log.Errorw("Failed to read reset token", "error", err)
+		return fmt.Errorf("failed to read reset token: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Errorw("Failed to load user for password reset", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to load user: %w", err)
+	}
+	if err := s.checkPasswordPolicy(req.NewPassword, user.Email); err != nil {
+		log.Infow("Password reset failed: weak password", "user_id", userID, "error", err)
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		log.Errorw("Password hashing failed", "error", err)
+		return fmt.Errorf("password hashing failed: %w", err)
+	}
+
+	// Токен одноразовый: параллельный запрос с тем же токеном получит отказ
+	if _, err := s.resetRepo.Consume(ctx, tokenHash); err != nil {
+		if errors.Is(err, repository.ErrResetTokenNotFound) {
+			log.Infow("Password reset failed: token already used")
+			return ErrInvalidResetToken
+		}
+		log.Errorw("Failed to consume reset token", "error", err)
+		return fmt.Errorf("failed to consume reset token: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		log.Errorw("Failed to update password", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.tokenSvc.RevokeAllForUser(ctx, userID); err != nil {
+		log.Errorw("Failed to revoke sessions after password reset", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	log.Infow("Password reset completed", "user_id", userID)
+	s.audit.Record(ctx, userAuditEntry(models.AuditPasswordReset, userID, user.Email))
+	return nil
+}
+
+// ChangePassword — смена пароля авторизованным пользователем.
+// Все сессии, кроме текущей (currentJTI), отзываются.
+func (s *authService) ChangePassword(ctx context.Context, userID uuid.UUID, currentJTI string, req models.ChangePasswordRequest) error {
+	log := s.logger.With("user_id", userID)
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Errorw("Failed to load user for password change", "error", err)
+		return fmt.Errorf("failed to load user: %w", err)
+	}
+
+	if err := utils.ComparePassword(user.PasswordHash, req.OldPassword); err != nil {
+		log.Infow("Password change failed: wrong current password")
+		return ErrWrongPassword
+	}
+
+	if err := s.checkPasswordPolicy(req.NewPassword, user.Email); err != nil {
+		log.Infow("Password change failed: weak password", "error", err)
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		log.Errorw("Password hashing failed", "error", err)
+		return fmt.Errorf("password hashing failed: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		log.Errorw("Failed to update password", "error", err)
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.tokenSvc.RevokeAllExcept(ctx, user.ID, currentJTI); err != nil {
+		log.Errorw("Failed to revoke other sessions after password change", "error", err)
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	log.Infow("Password changed", "kept_jti", currentJTI)
+	entry := userAuditEntry(models.AuditPasswordChanged, user.ID, user.Email)
+	entry.JTI = currentJTI
+	s.audit.Record(ctx, entry)
+	return nil
+}
+
+// upgradePasswordHash — перехэширование пароля текущим алгоритмом после успешного входа.
+// Ошибка не мешает входу: попробуем при следующем.
+func (s *authService) upgradePasswordHash(ctx context.Context, user *models.User, password string) {
+	log := s.logger.With("user_id", user.ID)
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		log.Warnw("Password rehash failed", "error", err)
+		return
+	}
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		log.Warnw("Failed to store upgraded password hash", "error", err)
+		return
+	}
+
+	user.PasswordHash = hashedPassword
+	log.Infow("Password hash upgraded")
+}
+
+// sendPasswordResetEmail — письмо со ссылкой сброса пароля
+func (s *authService) sendPasswordResetEmail(ctx context.Context, email, token string) error {
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.cfg.LinkBaseURL, token)
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Сброс пароля в Huddle",
+		Body: fmt.Sprintf(
+			"Здравствуйте!\n\nМы получили запрос на сброс пароля. Чтобы задать новый пароль, перейдите по ссылке:\n%s\n\nСсылка одноразовая и действительна %s. Если вы не запрашивали сброс, просто проигнорируйте это письмо.\n",
+			link, s.cfg.ResetTokenTTL,
+		),
+	})
+}
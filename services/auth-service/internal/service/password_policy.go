@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// minEmailLocalPartLength — слишком короткую часть email (например "a") не ищем в пароле
+const minEmailLocalPartLength = 3
+
+// PasswordPolicyConfig — требования к новым паролям
+type PasswordPolicyConfig struct {
+	MinLength            int
+	MaxLength            int
+	ForbidEmailLocalPart bool                // Пароль не должен содержать часть email до @
+	CommonPasswords      map[string]struct{} // Распространённые/утёкшие пароли в нижнем регистре
+}
+
+// PasswordPolicyError — пароль не соответствует политике; Violations — понятные пользователю причины
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password does not meet requirements: " + strings.Join(e.Violations, "; ")
+}
+
+// checkPasswordPolicy — проверка нового пароля (регистрация, сброс, смена)
+func (s *authService) checkPasswordPolicy(password, email string) error {
+	policy := s.cfg.PasswordPolicy
+	var violations []string
+
+	length := utf8.RuneCountInString(password)
+	if policy.MinLength > 0 && length < policy.MinLength {
+		violations = append(violations, fmt.Sprintf("password must be at least %d characters long", policy.MinLength))
+	}
+	if policy.MaxLength > 0 && length > policy.MaxLength {
+		violations = append(violations, fmt.Sprintf("password must be at most %d characters long", policy.MaxLength))
+	}
+
+	lower := strings.ToLower(password)
+	if _, common := policy.CommonPasswords[lower]; common {
+		violations = append(violations, "password is too common, choose a less predictable one")
+	}
+
+	if policy.ForbidEmailLocalPart {
+		local, _, _ := strings.Cut(strings.ToLower(email), "@")
+		if utf8.RuneCountInString(local) >= minEmailLocalPartLength && strings.Contains(lower, local) {
+			violations = append(violations, "password must not contain your email name")
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCheckPasswordPolicy(t *testing.T) {
+	policy := PasswordPolicyConfig{
+		MinLength:            8,
+		MaxLength:            64,
+		ForbidEmailLocalPart: true,
+		CommonPasswords:      map[string]struct{}{"password123": {}, "qwertyuiop": {}},
+	}
+
+	const (
+		tooShort   = "password must be at least 8 characters long"
+		tooLong    = "password must be at most 64 characters long"
+		common     = "password is too common, choose a less predictable one"
+		emailLocal = "password must not contain your email name"
+	)
+
+	tests := []struct {
+		name     string
+		policy   PasswordPolicyConfig
+		password string
+		email    string
+		want     []string // Ожидаемые нарушения; nil — пароль принят
+	}{
+		{name: "strong password", policy: policy, password: "v3ry-unpredictable", email: "alice@example.com"},
+		{name: "exactly min length", policy: policy, password: "k9#mQ2!z", email: "alice@example.com"},
+		{name: "too short", policy: policy, password: "k9#mQ2", email: "alice@example.com", want: []string{tooShort}},
+		{name: "too long", policy: policy, password: string(make([]byte, 65)), email: "alice@example.com", want: []string{tooLong}},
+		// Длина считается в символах, а не в байтах
+		{name: "multibyte counts runes", policy: policy, password: "пароль", email: "alice@example.com", want: []string{tooShort}},
+		{name: "multibyte long enough", policy: policy, password: "надёжныйпароль", email: "alice@example.com"},
+		{name: "common password", policy: policy, password: "password123", email: "alice@example.com", want: []string{common}},
+		{name: "common password any case", policy: policy, password: "QwertyUIOP", email: "alice@example.com", want: []string{common}},
+		{name: "contains email name", policy: policy, password: "xxAlice2024", email: "alice@example.com", want: []string{emailLocal}},
+		{name: "contains email name any case", policy: policy, password: "my-ALICE-pass", email: "Alice@Example.com", want: []string{emailLocal}},
+		{name: "short email name is ignored", policy: policy, password: "bobcat-rocks", email: "bo@example.com"},
+		{name: "email without at", policy: policy, password: "hello-alice-x", email: "alice", want: []string{emailLocal}},
+		{name: "several violations", policy: policy, password: "alice", email: "alice@example.com", want: []string{tooShort, emailLocal}},
+		{
+			name:     "email check disabled",
+			policy:   PasswordPolicyConfig{MinLength: 8, ForbidEmailLocalPart: false},
+			password: "alice-wonderland",
+			email:    "alice@example.com",
+		},
+		{name: "empty policy accepts anything", policy: PasswordPolicyConfig{}, password: "a", email: "a@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &authService{cfg: AuthServiceConfig{PasswordPolicy: tt.policy}}
+
+			err := s.checkPasswordPolicy(tt.password, tt.email)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("checkPasswordPolicy() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var policyErr *PasswordPolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("checkPasswordPolicy() error = %v, want *PasswordPolicyError", err)
+			}
+			if !slices.Equal(policyErr.Violations, tt.want) {
+				t.Errorf("violations = %q, want %q", policyErr.Violations, tt.want)
+			}
+		})
+	}
+}
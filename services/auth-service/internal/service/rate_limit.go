@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// rateLimit — ключ счётчика в AttemptRepository и допустимое число запросов в окне
+type rateLimit struct {
+	key string
+	max int
+}
+
+// checkRateLimits — учитывает запрос во всех счётчиках и сообщает, превышен ли хотя бы один лимит.
+// Счётчики увеличиваются все, даже если первый уже превышен: иначе перебор по второму ключу
+// не учитывался бы. При недоступности Redis запрос не блокируется.
+func (s *authService) checkRateLimits(ctx context.Context, limits []rateLimit, window time.Duration) bool {
+	throttled := false
+	for _, l := range limits {
+		count, err := s.attempts.Hit(ctx, l.key, window)
+		if err != nil {
+			s.logger.Warnw("Rate limit check failed", "key", l.key, "error", err)
+			continue
+		}
+		if count > int64(l.max) {
+			s.logger.Infow("Request throttled", "key", l.key, "count", count)
+			throttled = true
+		}
+	}
+	return throttled
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"auth-service/internal/repository"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newRateLimitTestService(t *testing.T) (*authService, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	// Без повторов: тест с остановленным Redis не ждёт back-off клиента
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+
+	logger := zap.NewNop().Sugar()
+	return &authService{
+		attempts: repository.NewAttemptRepository(rdb, logger),
+		logger:   logger,
+	}, mr
+}
+
+func TestCheckRateLimits(t *testing.T) {
+	const window = time.Minute
+
+	tests := []struct {
+		name     string
+		limits   []rateLimit
+		requests int  // Сколько раз вызывается checkRateLimits
+		want     bool // Результат последнего вызова
+	}{
+		{name: "under both limits", limits: []rateLimit{{"a", 3}, {"b", 5}}, requests: 3, want: false},
+		{name: "first limit exceeded", limits: []rateLimit{{"a", 2}, {"b", 5}}, requests: 3, want: true},
+		{name: "second limit exceeded", limits: []rateLimit{{"a", 5}, {"b", 2}}, requests: 3, want: true},
+		{name: "no limits", limits: nil, requests: 10, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newRateLimitTestService(t)
+			ctx := context.Background()
+
+			var got bool
+			for range tt.requests {
+				got = s.checkRateLimits(ctx, tt.limits, window)
+			}
+			if got != tt.want {
+				t.Errorf("checkRateLimits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRateLimits_CountsEveryKey(t *testing.T) {
+	s, _ := newRateLimitTestService(t)
+	ctx := context.Background()
+
+	// Первый лимит превышен сразу, но второй счётчик всё равно растёт
+	limits := []rateLimit{{"a", 0}, {"b", 2}}
+	for range 3 {
+		s.checkRateLimits(ctx, limits, time.Minute)
+	}
+
+	if !s.checkRateLimits(ctx, []rateLimit{{"b", 3}}, time.Minute) {
+		t.Errorf("second key was not counted while the first was throttled")
+	}
+}
+
+func TestCheckRateLimits_RedisDown(t *testing.T) {
+	s, mr := newRateLimitTestService(t)
+	mr.Close()
+
+	if s.checkRateLimits(context.Background(), []rateLimit{{"a", 0}}, time.Minute) {
+		t.Errorf("checkRateLimits() = true with Redis down, want fail-open")
+	}
+}
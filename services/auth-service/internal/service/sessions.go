@@ -0,0 +1,48 @@
+package service
+
+import (
+	"auth-service/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ListSessions — активные сессии пользователя; текущая помечается флагом Current
+func (s *authService) ListSessions(ctx context.Context, userID uuid.UUID, currentJTI string) ([]models.Session, error) {
+	sessions, err := s.tokenSvc.ListSessions(ctx, userID)
+	if err != nil {
+		s.logger.Errorw("Failed to list sessions", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].JTI == currentJTI
+	}
+	return sessions, nil
+}
+
+// RevokeSession — выход на конкретном устройстве
+func (s *authService) RevokeSession(ctx context.Context, userID uuid.UUID, jti string) error {
+	if err := s.tokenSvc.RevokeSession(ctx, userID, jti); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	s.logger.Infow("Session revoked", "user_id", userID, "jti", jti)
+	entry := userAuditEntry(models.AuditSessionRevoked, userID, "")
+	entry.JTI = jti
+	s.audit.Record(ctx, entry)
+	return nil
+}
+
+// RevokeAllSessions — выход на всех устройствах
+func (s *authService) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	if err := s.tokenSvc.RevokeAllForUser(ctx, userID); err != nil {
+		s.logger.Errorw("Failed to revoke all sessions", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to revoke all sessions: %w", err)
+	}
+
+	s.logger.Infow("All sessions revoked", "user_id", userID)
+	s.audit.Record(ctx, userAuditEntry(models.AuditSessionsRevoked, userID, ""))
+	return nil
+}
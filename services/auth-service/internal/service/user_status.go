@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+	"auth-service/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// userStatusCacheMaxEntries — предельный размер кэша статусов
+const userStatusCacheMaxEntries = 10000
+
+// newUserStatusCache — локальный кэш статуса пользователей для nginx auth_request:
+// без него каждый проксируемый запрос читал бы пользователя из Postgres
+func newUserStatusCache(ttl time.Duration) *utils.TTLCache[uuid.UUID, string] {
+	return utils.NewTTLCache[uuid.UUID, string](ttl, userStatusCacheMaxEntries)
+}
+
+// CheckUserActive — пользователь не заблокирован и не удалён. Access токен остаётся валидным
+// до exp, даже если отзыв при блокировке не прошёл, поэтому /validate проверяет статус сам.
+func (s *authService) CheckUserActive(ctx context.Context, userID uuid.UUID) error {
+	status, ok := s.statusCache.Get(userID)
+	if !ok {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				status = models.UserStatusDeleted
+			} else {
+				return fmt.Errorf("failed to load user status: %w", err)
+			}
+		} else {
+			status = user.Status
+		}
+		s.statusCache.Set(userID, status)
+	}
+
+	if status != models.UserStatusActive {
+		s.logger.Infow("Access rejected: account is not active", "user_id", userID, "status", status)
+		return ErrAccountDisabled
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/service"
+	"auth-service/pkg/apperror"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// AccountHandler — HTTP-обёртка над AccountDeletionService
+type AccountHandler struct {
+	service service.AccountDeletionService
+	logger  *zap.SugaredLogger
+}
+
+// NewAccountHandler — конструктор
+func NewAccountHandler(service service.AccountDeletionService, logger *zap.SugaredLogger) *AccountHandler {
+	return &AccountHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// DeleteAccount — DELETE /api/v1/auth/me: удаление своего аккаунта.
+// Данные в других сервисах стираются асинхронно, поэтому ответ — 202.
+func (h *AccountHandler) DeleteAccount(c echo.Context) error {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		return apperror.Unauthorized("invalid user id in token")
+	}
+
+	if err := h.service.DeleteAccount(c.Request().Context(), userID); err != nil {
+		log.Errorw("Account deletion failed", "user_id", userID, "error", err)
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, echo.Map{"message": "account deletion scheduled"})
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/models"
+	"auth-service/internal/service"
+	"auth-service/pkg/apperror"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// AdminHandler — HTTP-обёртка над AdminService
+type AdminHandler struct {
+	service   service.AdminService
+	logger    *zap.SugaredLogger
+	validator *validator.Validate
+}
+
+// NewAdminHandler — конструктор
+func NewAdminHandler(service service.AdminService, logger *zap.SugaredLogger) *AdminHandler {
+	return &AdminHandler{
+		service:   service,
+		logger:    logger,
+		validator: validator.New(),
+	}
+}
+
+// ListUsers — GET /admin/users?q=&status=&role=&limit=&offset=
+func (h *AdminHandler) ListUsers(c echo.Context) error {
+	var filter models.UserFilter
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &filter); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := h.validator.Struct(filter); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	list, err := h.service.ListUsers(c.Request().Context(), filter)
+	if err != nil {
+		log.Errorw("Failed to list users", "error", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+// GetUser — GET /admin/users/:id
+func (h *AdminHandler) GetUser(c echo.Context) error {
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid user id")
+	}
+
+	user, err := h.service.GetUser(c.Request().Context(), userID)
+	if err != nil {
+		return h.userError(c, log, "Failed to get user", err)
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// BlockUser — POST /admin/users/:id/block
+func (h *AdminHandler) BlockUser(c echo.Context) error {
+	return h.setStatus(c, models.UserStatusBlocked)
+}
+
+// BanUser — POST /admin/users/:id/ban
+func (h *AdminHandler) BanUser(c echo.Context) error {
+	return h.setStatus(c, models.UserStatusBanned)
+}
+
+// UnblockUser — POST /admin/users/:id/unblock
+func (h *AdminHandler) UnblockUser(c echo.Context) error {
+	return h.setStatus(c, models.UserStatusActive)
+}
+
+// ChangeRole — PUT /admin/users/:id/role
+func (h *AdminHandler) ChangeRole(c echo.Context) error {
+	var req models.ChangeRoleRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	adminID, userID, httpErr := adminAndTarget(c)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	user, err := h.service.ChangeUserRole(c.Request().Context(), adminID, userID, req.Role)
+	if err != nil {
+		return h.userError(c, log, "Failed to change user role", err)
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "role changed",
+		"user":    user,
+	})
+}
+
+// setStatus — общий обработчик block/ban/unblock
+func (h *AdminHandler) setStatus(c echo.Context, status string) error {
+	var req models.ChangeStatusRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	adminID, userID, httpErr := adminAndTarget(c)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	// Тело необязательно: причина может не указываться
+	if c.Request().ContentLength > 0 {
+		if err := c.Bind(&req); err != nil {
+			log.Warnw("Bind failed", "error", err)
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	user, err := h.service.SetUserStatus(c.Request().Context(), adminID, userID, status, req.Reason)
+	if err != nil {
+		return h.userError(c, log, "Failed to change user status", err)
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "status changed",
+		"user":    user,
+	})
+}
+
+// adminAndTarget — ID администратора из токена и ID пользователя из пути
+func adminAndTarget(c echo.Context) (uuid.UUID, uuid.UUID, *echo.HTTPError) {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return uuid.Nil, uuid.Nil, echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+	}
+	adminID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, echo.NewHTTPError(http.StatusUnauthorized, "invalid user id in token")
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, "invalid user id")
+	}
+	return adminID, userID, nil
+}
+
+// userError — ошибки домена (нет пользователя, изменение самого себя) отдаёт middleware.ErrorHandler,
+// в лог попадают только непредвиденные
+func (h *AdminHandler) userError(c echo.Context, log *zap.SugaredLogger, msg string, err error) error {
+	if apperror.HTTPStatus(err) == http.StatusInternalServerError {
+		log.Errorw(msg, "user_id", c.Param("id"), "error", err)
+	}
+	return err
+}
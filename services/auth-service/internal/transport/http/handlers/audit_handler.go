@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/models"
+	"auth-service/internal/service"
+	"auth-service/pkg/apperror"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// AuditHandler — HTTP-обёртка над журналом аутентификации
+type AuditHandler struct {
+	service   service.AuditService
+	logger    *zap.SugaredLogger
+	validator *validator.Validate
+}
+
+// NewAuditHandler — конструктор
+func NewAuditHandler(service service.AuditService, logger *zap.SugaredLogger) *AuditHandler {
+	return &AuditHandler{
+		service:   service,
+		logger:    logger,
+		validator: validator.New(),
+	}
+}
+
+// MyActivity — GET /auth/me/activity?event_type=&success=&from=&to=&limit=&offset=
+func (h *AuditHandler) MyActivity(c echo.Context) error {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		log.Warnw("Invalid user ID in token", "sub", claims.Sub)
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	filter, err := h.bindFilter(c)
+	if err != nil {
+		return err
+	}
+
+	list, err := h.service.ListForUser(c.Request().Context(), userID, filter)
+	if err != nil {
+		log.Errorw("Failed to list user activity", "user_id", claims.Sub, "error", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+// ListAudit — GET /admin/audit?user_id=&email=&event_type=&ip=&success=&from=&to=&limit=&offset=
+func (h *AuditHandler) ListAudit(c echo.Context) error {
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	filter, err := h.bindFilter(c)
+	if err != nil {
+		return err
+	}
+
+	list, err := h.service.List(c.Request().Context(), filter)
+	if err != nil {
+		log.Errorw("Failed to list audit log", "error", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+// bindFilter — разбор и проверка query-параметров
+func (h *AuditHandler) bindFilter(c echo.Context) (models.AuditFilter, error) {
+	var filter models.AuditFilter
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &filter); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return filter, echo.NewHTTPError(http.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := h.validator.Struct(filter); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return filter, apperror.Validation(err.Error())
+	}
+
+	return filter, nil
+}
@@ -5,9 +5,14 @@ import (
 	"auth-service/internal/middleware"
 	"auth-service/internal/models"
 	"auth-service/internal/service"
+	"auth-service/pkg/apperror"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 )
@@ -35,18 +40,22 @@ func (h *AuthHandler) RegisterUser(c echo.Context) error {
 
 	if err := c.Bind(&req); err != nil {
 		log.Warnw("Bind failed", "error", err)
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request body"})
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
 	}
 
 	if err := h.validator.Struct(req); err != nil {
 		log.Warnw("Validation failed", "error", err)
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return apperror.Validation(err.Error())
 	}
 
 	user, err := h.service.RegisterUser(c.Request().Context(), req)
 	if err != nil {
-		log.Errorw("Register failed", "email", req.Email, "error", err)
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "registration failed"})
+		if handled, resp := passwordPolicyResponse(c, err); handled {
+			return resp
+		}
+		// Занятый email или телефон — 409 (middleware.ErrorHandler)
+		log.Warnw("Register failed", "email", req.Email, "error", err)
+		return err
 	}
 
 	log.Infow("User registered", "user_id", user.ID, "email", user.Email)
@@ -63,27 +72,68 @@ func (h *AuthHandler) Login(c echo.Context) error {
 
 	if err := c.Bind(&req); err != nil {
 		log.Warnw("Bind failed", "error", err)
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request body"})
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
 	}
 
 	if err := h.validator.Struct(req); err != nil {
 		log.Warnw("Validation failed", "error", err)
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return apperror.Validation(err.Error())
 	}
 
-	tokens, err := h.service.Login(c.Request().Context(), req)
+	result, err := h.service.Login(c.Request().Context(), req, clientInfo(c))
 	if err != nil {
-		log.Infow("Login failed", "email", req.Email, "error", err)
-		return c.JSON(http.StatusUnauthorized, echo.Map{"error": "invalid credentials"})
+		// Неверный пароль — 401, back-off — 429 с Retry-After, заблокированный аккаунт — 403,
+		// сбой БД — 500/503 (middleware.ErrorHandler)
+		logError(log, "Login failed", err, "email", req.Email)
+		return err
+	}
+
+	log.Infow("Login first step completed", "email", req.Email, "mfa_required", result.Challenge != nil)
+	return loginResponse(c, result)
+}
+
+// loginResponse — токены или, при включённой 2FA, challenge для POST /2fa/verify
+func loginResponse(c echo.Context, result *models.LoginResult) error {
+	if result.Challenge != nil {
+		return c.JSON(http.StatusOK, echo.Map{
+			"message":     "two-factor authentication required",
+			"mfaRequired": true,
+			"challenge":   result.Challenge,
+		})
 	}
 
-	log.Infow("Login successful", "email", req.Email)
 	return c.JSON(http.StatusOK, echo.Map{
 		"message": "login successful",
-		"tokens":  tokens,
+		"tokens":  result.Tokens,
+	})
+}
+
+// passwordPolicyResponse — 422 со списком нарушений, если пароль не прошёл политику.
+// Тело как у middleware.ErrorHandler, плюс violations.
+func passwordPolicyResponse(c echo.Context, err error) (bool, error) {
+	var policyErr *service.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return false, nil
+	}
+	return true, c.JSON(http.StatusUnprocessableEntity, echo.Map{
+		"error":      "password does not meet requirements",
+		"code":       "validation_failed",
+		"request_id": middleware.GetRequestIDFromCtx(c.Request().Context()),
+		"violations": policyErr.Violations,
 	})
 }
 
+// logError — ошибки домена (неверный код, лимит запросов) пишутся как Info,
+// непредвиденные — как Error; ответ в обоих случаях формирует middleware.ErrorHandler
+func logError(log *zap.SugaredLogger, msg string, err error, keysAndValues ...any) {
+	keysAndValues = append(keysAndValues, "error", err)
+	if apperror.HTTPStatus(err) >= http.StatusInternalServerError {
+		log.Errorw(msg, keysAndValues...)
+		return
+	}
+	log.Infow(msg, keysAndValues...)
+}
+
 // RefreshToken — обновление пары токенов
 func (h *AuthHandler) RefreshToken(c echo.Context) error {
 	var req models.RefreshTokenRequest
@@ -91,18 +141,18 @@ func (h *AuthHandler) RefreshToken(c echo.Context) error {
 
 	if err := c.Bind(&req); err != nil {
 		log.Warnw("Bind failed", "error", err)
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request body"})
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
 	}
 
 	if err := h.validator.Struct(req); err != nil {
 		log.Warnw("Validation failed", "error", err)
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return apperror.Validation(err.Error())
 	}
 
-	newTokens, err := h.service.RefreshToken(c.Request().Context(), req)
+	newTokens, err := h.service.RefreshToken(c.Request().Context(), req, clientInfo(c))
 	if err != nil {
 		log.Warnw("Refresh failed", "error", err)
-		return c.JSON(http.StatusUnauthorized, echo.Map{"error": "invalid or revoked refresh token"})
+		return err
 	}
 
 	log.Infow("Token refreshed successfully")
@@ -116,21 +166,267 @@ func (h *AuthHandler) RefreshToken(c echo.Context) error {
 func (h *AuthHandler) Logout(c echo.Context) error {
 	claims, ok := middleware.GetUserClaims(c.Request().Context())
 	if !ok {
-		return c.JSON(http.StatusUnauthorized, echo.Map{"error": "unauthorized"})
+		return apperror.Unauthorized("unauthorized")
 	}
 
 	log := middleware.GetLoggerFromCtx(c.Request().Context())
 
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		log.Warnw("Invalid user ID in token", "sub", claims.Sub)
+		return apperror.Unauthorized("unauthorized")
+	}
+
 	// Отзываем по jti из access токена
-	if err := h.service.RevokeByJTI(c.Request().Context(), claims.JTI); err != nil {
+	if err := h.service.RevokeByJTI(c.Request().Context(), userID, claims.JTI); err != nil {
 		log.Errorw("Logout failed", "jti", claims.JTI, "user_id", claims.Sub, "error", err)
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "logout failed"})
+		return err
 	}
 
 	log.Infow("User logged out", "user_id", claims.Sub, "jti", claims.JTI)
 	return c.JSON(http.StatusOK, echo.Map{"message": "logged out successfully"})
 }
 
+// VerifyEmail — подтверждение email по токену из письма
+func (h *AuthHandler) VerifyEmail(c echo.Context) error {
+	var req models.VerifyEmailRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	if err := h.service.VerifyEmail(c.Request().Context(), req); err != nil {
+		logError(log, "Email verification failed", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"message": "email verified successfully"})
+}
+
+// ResendVerification — повторная отправка письма подтверждения
+func (h *AuthHandler) ResendVerification(c echo.Context) error {
+	var req models.ResendVerificationRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	if err := h.service.ResendVerification(c.Request().Context(), req); err != nil {
+		logError(log, "Resend verification failed", err, "email", req.Email)
+		return err
+	}
+
+	// Ответ одинаковый, чтобы не раскрывать, зарегистрирован ли email
+	return c.JSON(http.StatusAccepted, echo.Map{"message": "if the account exists and is not verified, a verification email has been sent"})
+}
+
+// ForgotPassword — запрос письма для сброса пароля
+func (h *AuthHandler) ForgotPassword(c echo.Context) error {
+	var req models.ResetPasswordRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	if err := h.service.ForgotPassword(c.Request().Context(), req); err != nil {
+		log.Errorw("Forgot password failed", "error", err)
+		return err
+	}
+
+	// Ответ одинаковый, чтобы не раскрывать, зарегистрирован ли email
+	return c.JSON(http.StatusAccepted, echo.Map{"message": "if the account exists, a password reset email has been sent"})
+}
+
+// RequestMagicLink — запрос письма со ссылкой для входа без пароля
+func (h *AuthHandler) RequestMagicLink(c echo.Context) error {
+	var req models.MagicLinkRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	if err := h.service.RequestMagicLink(c.Request().Context(), req, clientInfo(c)); err != nil {
+		logError(log, "Magic link request failed", err)
+		return err
+	}
+
+	// Ответ одинаковый, чтобы не раскрывать, зарегистрирован ли email
+	return c.JSON(http.StatusAccepted, echo.Map{"message": "if the account exists, a sign-in link has been sent"})
+}
+
+// ConsumeMagicLink — вход по токену из письма
+func (h *AuthHandler) ConsumeMagicLink(c echo.Context) error {
+	var req models.MagicLinkConsumeRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	result, err := h.service.ConsumeMagicLink(c.Request().Context(), req, clientInfo(c))
+	if err != nil {
+		logError(log, "Magic link login failed", err)
+		return err
+	}
+
+	return loginResponse(c, result)
+}
+
+// ResetPassword — установка нового пароля по токену из письма
+func (h *AuthHandler) ResetPassword(c echo.Context) error {
+	var req models.ResetPasswordConfirmRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	if err := h.service.ResetPassword(c.Request().Context(), req); err != nil {
+		if handled, resp := passwordPolicyResponse(c, err); handled {
+			return resp
+		}
+		logError(log, "Password reset failed", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"message": "password has been reset"})
+}
+
+// ChangePassword — смена пароля; остальные сессии пользователя завершаются
+func (h *AuthHandler) ChangePassword(c echo.Context) error {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	var req models.ChangePasswordRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		log.Warnw("Invalid user ID in token", "sub", claims.Sub)
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	if err := h.service.ChangePassword(c.Request().Context(), userID, claims.JTI, req); err != nil {
+		if handled, resp := passwordPolicyResponse(c, err); handled {
+			return resp
+		}
+		logError(log, "Password change failed", err, "user_id", claims.Sub)
+		return err
+	}
+
+	log.Infow("Password changed", "user_id", claims.Sub)
+	return c.JSON(http.StatusOK, echo.Map{"message": "password changed, other sessions have been signed out"})
+}
+
+// ChangeEmail — запрос смены email: письмо с подтверждением уходит на новый адрес
+func (h *AuthHandler) ChangeEmail(c echo.Context) error {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	var req models.ChangeEmailRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		log.Warnw("Invalid user ID in token", "sub", claims.Sub)
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	if err := h.service.RequestEmailChange(c.Request().Context(), userID, req); err != nil {
+		logError(log, "Email change request failed", err, "user_id", claims.Sub)
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, echo.Map{"message": "confirmation email has been sent to the new address"})
+}
+
+// ConfirmEmailChange — подтверждение нового email по токену из письма
+func (h *AuthHandler) ConfirmEmailChange(c echo.Context) error {
+	var req models.ConfirmEmailChangeRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	if err := h.service.ConfirmEmailChange(c.Request().Context(), req); err != nil {
+		logError(log, "Email change confirmation failed", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"message": "email has been changed"})
+}
+
 func (h *AuthHandler) Validate(c echo.Context) error {
 	// 1. Достаем claims из контекста ЗАПРОСА (так как middleware положил их туда через context.WithValue)
 	// Важно: тип должен точно совпадать с тем, что возвращает tokenSvc.ParseAccess
@@ -139,13 +435,31 @@ func (h *AuthHandler) Validate(c echo.Context) error {
 	if !ok {
 		// Если claims нет, значит что-то пошло не так в цепочке middleware
 		// Nginx получит 401 и заблокирует запрос
-		return c.NoContent(http.StatusUnauthorized)
+		return apperror.Unauthorized("unauthorized")
 	}
 
-	// 2. Устанавливаем заголовок, который Nginx перехватит и пробросит в profile-service
-	// В твоем middleware используется claims.Sub (судя по логам),
-	// убедись, что в модели это поле называется так же.
-	c.Response().Header().Set("X-User-ID", claims.Sub)
+	// Токен жив до exp и после блокировки: статус проверяется на каждом запросе (с коротким кэшем).
+	// 403 Nginx отдаёт клиенту как есть.
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		return apperror.Unauthorized("unauthorized")
+	}
+	if err := h.service.CheckUserActive(c.Request().Context(), userID); err != nil {
+		return err
+	}
+
+	// 2. Устанавливаем заголовки, которые Nginx перехватит и пробросит в profile-service и event-service
+	header := c.Response().Header()
+	header.Set("X-User-ID", claims.Sub)
+	header.Set("X-User-Role", claims.Role)
+	header.Set("X-User-Email", claims.Email)
+	header.Set("X-Token-JTI", claims.JTI)
+	// Срок действия токена (Unix, секунды) — сервисы могут ограничить им свои кэши
+	if claims.ExpiresAt != nil {
+		header.Set("X-Token-Expires-At", strconv.FormatInt(claims.ExpiresAt.Unix(), 10))
+	}
+	// Права через запятую — сервисы проверяют их своим RequirePermission
+	header.Set("X-User-Permissions", strings.Join(claims.Permissions, ","))
 
 	// 3. Возвращаем 200 OK. Для Nginx это сигнал: "Пропускай!"
 	return c.NoContent(http.StatusOK)
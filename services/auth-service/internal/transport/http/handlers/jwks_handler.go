@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"auth-service/internal/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// JWKSHandler — публикация публичных ключей для локальной проверки токенов
+type JWKSHandler struct {
+	keyRing *utils.KeyRing
+}
+
+// NewJWKSHandler — конструктор
+func NewJWKSHandler(keyRing *utils.KeyRing) *JWKSHandler {
+	return &JWKSHandler{keyRing: keyRing}
+}
+
+// GetJWKS — GET /.well-known/jwks.json
+func (h *JWKSHandler) GetJWKS(c echo.Context) error {
+	// Короткий кэш: после ротации новый ключ появится у клиентов быстро
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, h.keyRing.JWKS())
+}
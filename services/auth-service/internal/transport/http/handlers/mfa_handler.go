@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupMFA — POST /2fa/setup: новый TOTP секрет и otpauth URI
+func (h *AuthHandler) SetupMFA(c echo.Context) error {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		log.Warnw("Invalid user ID in token", "sub", claims.Sub)
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	setup, err := h.service.SetupMFA(c.Request().Context(), userID)
+	if err != nil {
+		log.Errorw("MFA setup failed", "user_id", claims.Sub, "error", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, setup)
+}
+
+// EnableMFA — POST /2fa/enable: подтверждение кодом, в ответе recovery коды
+func (h *AuthHandler) EnableMFA(c echo.Context) error {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	var req models.MFAEnableRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		log.Warnw("Invalid user ID in token", "sub", claims.Sub)
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	codes, err := h.service.EnableMFA(c.Request().Context(), userID, req)
+	if err != nil {
+		logError(log, "MFA enable failed", err, "user_id", claims.Sub)
+		return err
+	}
+
+	log.Infow("MFA enabled", "user_id", claims.Sub)
+	return c.JSON(http.StatusOK, echo.Map{
+		"message":       "two-factor authentication enabled, store the recovery codes in a safe place",
+		"recoveryCodes": codes,
+	})
+}
+
+// VerifyMFA — POST /2fa/verify: challenge из Login + код → пара токенов
+func (h *AuthHandler) VerifyMFA(c echo.Context) error {
+	var req models.MFAVerifyRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	tokens, err := h.service.VerifyMFA(c.Request().Context(), req, clientInfo(c))
+	if err != nil {
+		logError(log, "MFA verification failed", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"message": "login successful",
+		"tokens":  tokens,
+	})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/pkg/apperror"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// oauthStateCookieName — cookie с хэшем state между start и callback
+const oauthStateCookieName = "oauth_state"
+
+// StartOAuth — GET /oauth/:provider/start: редирект на страницу входа провайдера
+func (h *AuthHandler) StartOAuth(c echo.Context) error {
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+	provider := c.Param("provider")
+
+	authorization, err := h.service.StartOAuth(c.Request().Context(), provider)
+	if err != nil {
+		log.Errorw("OAuth start failed", "provider", provider, "error", err)
+		return err
+	}
+
+	c.SetCookie(oauthStateCookie(c, authorization.StateBinding, int(authorization.TTL.Seconds())))
+	return c.Redirect(http.StatusFound, authorization.URL)
+}
+
+// oauthStateCookie — привязка state к браузеру, начавшему вход. SameSite=Lax: cookie
+// должна уйти с редиректом от провайдера, но не с фоновыми запросами чужих сайтов.
+// maxAge < 0 удаляет cookie.
+func oauthStateCookie(c echo.Context, binding string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     oauthStateCookieName,
+		Value:    binding,
+		Path:     "/api/v1/auth/oauth/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   c.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// OAuthCallback — GET /oauth/:provider/callback: обмен кода и выдача пары токенов
+func (h *AuthHandler) OAuthCallback(c echo.Context) error {
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+	provider := c.Param("provider")
+
+	// Привязка одноразовая, как и сам state
+	var binding string
+	if cookie, err := c.Cookie(oauthStateCookieName); err == nil {
+		binding = cookie.Value
+	}
+	c.SetCookie(oauthStateCookie(c, "", -1))
+
+	// Пользователь отказался или провайдер вернул ошибку
+	if providerErr := c.QueryParam("error"); providerErr != "" {
+		log.Infow("OAuth provider returned error", "provider", provider, "error", providerErr)
+		return echo.NewHTTPError(http.StatusBadRequest, "oauth login was not completed: "+providerErr)
+	}
+
+	result, err := h.service.OAuthCallback(c.Request().Context(), provider, c.QueryParam("state"), binding, c.QueryParam("code"), clientInfo(c))
+	if err != nil {
+		// Ошибки домена (неизвестный провайдер, state, занятый email) — middleware.ErrorHandler,
+		// остальное — сбой обмена кода у провайдера
+		if apperror.HTTPStatus(err) != http.StatusInternalServerError {
+			log.Infow("OAuth callback rejected", "provider", provider, "error", err)
+			return err
+		}
+		log.Errorw("OAuth callback failed", "provider", provider, "error", err)
+		return echo.NewHTTPError(http.StatusBadGateway, "oauth login failed")
+	}
+
+	log.Infow("OAuth login first step completed", "provider", provider, "mfa_required", result.Challenge != nil)
+	return loginResponse(c, result)
+}
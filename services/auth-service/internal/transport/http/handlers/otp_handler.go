@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// RequestOTP — POST /otp/request: код для входа по SMS
+func (h *AuthHandler) RequestOTP(c echo.Context) error {
+	var req models.OTPRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	if err := h.service.RequestOTP(c.Request().Context(), req, clientInfo(c)); err != nil {
+		logError(log, "Login code request failed", err)
+		return err
+	}
+
+	// Ответ одинаковый, чтобы не раскрывать, привязан ли номер к аккаунту
+	return c.JSON(http.StatusAccepted, echo.Map{"message": "if the phone is registered and verified, a code has been sent"})
+}
+
+// VerifyOTP — POST /otp/verify: вход по коду из SMS
+func (h *AuthHandler) VerifyOTP(c echo.Context) error {
+	var req models.OTPVerifyRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	result, err := h.service.VerifyOTP(c.Request().Context(), req, clientInfo(c))
+	if err != nil {
+		logError(log, "OTP login failed", err)
+		return err
+	}
+
+	return loginResponse(c, result)
+}
+
+// SetPhone — POST /phone: привязка номера, код подтверждения уходит в SMS
+func (h *AuthHandler) SetPhone(c echo.Context) error {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	var req models.SetPhoneRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		log.Warnw("Invalid user ID in token", "sub", claims.Sub)
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	if err := h.service.SetPhone(c.Request().Context(), userID, req, clientInfo(c)); err != nil {
+		logError(log, "Set phone failed", err, "user_id", claims.Sub)
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, echo.Map{"message": "verification code has been sent"})
+}
+
+// VerifyPhone — POST /phone/verify: подтверждение номера кодом из SMS
+func (h *AuthHandler) VerifyPhone(c echo.Context) error {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	var req models.VerifyPhoneRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		log.Warnw("Invalid user ID in token", "sub", claims.Sub)
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	if err := h.service.VerifyPhone(c.Request().Context(), userID, req); err != nil {
+		logError(log, "Phone verification failed", err, "user_id", claims.Sub)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"message": "phone has been verified"})
+}
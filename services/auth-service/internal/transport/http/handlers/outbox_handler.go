@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/models"
+	"auth-service/internal/service"
+	"auth-service/pkg/apperror"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// OutboxHandler — админские операции с событиями outbox, которые не удалось опубликовать
+type OutboxHandler struct {
+	service   service.OutboxAdminService
+	logger    *zap.SugaredLogger
+	validator *validator.Validate
+}
+
+// NewOutboxHandler — конструктор
+func NewOutboxHandler(service service.OutboxAdminService, logger *zap.SugaredLogger) *OutboxHandler {
+	return &OutboxHandler{
+		service:   service,
+		logger:    logger,
+		validator: validator.New(),
+	}
+}
+
+// ListFailed — GET /admin/outbox/failed?event_type=&limit=&offset=
+func (h *OutboxHandler) ListFailed(c echo.Context) error {
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	var filter models.OutboxFilter
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &filter); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid query parameters")
+	}
+
+	if err := h.validator.Struct(filter); err != nil {
+		log.Warnw("Validation failed", "error", err)
+		return apperror.Validation(err.Error())
+	}
+
+	list, err := h.service.ListFailed(c.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+// Requeue — POST /admin/outbox/:id/requeue
+func (h *OutboxHandler) Requeue(c echo.Context) error {
+	adminID, ok := h.adminID(c)
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event id")
+	}
+
+	if err := h.service.Requeue(c.Request().Context(), adminID, eventID); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"message": "event requeued"})
+}
+
+// RequeueAllFailed — POST /admin/outbox/failed/requeue
+func (h *OutboxHandler) RequeueAllFailed(c echo.Context) error {
+	adminID, ok := h.adminID(c)
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	count, err := h.service.RequeueAllFailed(c.Request().Context(), adminID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"requeued": count})
+}
+
+// adminID — идентификатор администратора из access токена
+func (h *OutboxHandler) adminID(c echo.Context) (uuid.UUID, bool) {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// clientInfo — IP, User-Agent, request_id и traceparent клиента (IP — из X-Real-IP доверенного прокси, см. ipExtractor в router.go)
+func clientInfo(c echo.Context) models.ClientInfo {
+	return models.ClientInfo{
+		IP:          c.RealIP(),
+		UserAgent:   c.Request().UserAgent(),
+		RequestID:   middleware.GetRequestIDFromCtx(c.Request().Context()),
+		Traceparent: middleware.Traceparent(c.Request()),
+	}
+}
+
+// ListSessions — GET /sessions: активные сессии (устройства) пользователя
+func (h *AuthHandler) ListSessions(c echo.Context) error {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		log.Warnw("Invalid user ID in token", "sub", claims.Sub)
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	sessions, err := h.service.ListSessions(c.Request().Context(), userID, claims.JTI)
+	if err != nil {
+		log.Errorw("List sessions failed", "user_id", claims.Sub, "error", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"sessions": sessions})
+}
+
+// RevokeSession — DELETE /sessions/:jti: выход на конкретном устройстве
+func (h *AuthHandler) RevokeSession(c echo.Context) error {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		log.Warnw("Invalid user ID in token", "sub", claims.Sub)
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	jti := c.Param("jti")
+	if err := h.service.RevokeSession(c.Request().Context(), userID, jti); err != nil {
+		log.Errorw("Revoke session failed", "user_id", claims.Sub, "jti", jti, "error", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"message": "session revoked"})
+}
+
+// RevokeAllSessions — DELETE /sessions: выход на всех устройствах, включая текущее
+func (h *AuthHandler) RevokeAllSessions(c echo.Context) error {
+	claims, ok := middleware.GetUserClaims(c.Request().Context())
+	if !ok {
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	userID, err := uuid.Parse(claims.Sub)
+	if err != nil {
+		log.Warnw("Invalid user ID in token", "sub", claims.Sub)
+		return apperror.Unauthorized("unauthorized")
+	}
+
+	if err := h.service.RevokeAllSessions(c.Request().Context(), userID); err != nil {
+		log.Errorw("Revoke all sessions failed", "user_id", claims.Sub, "error", err)
+		return err
+	}
+
+	log.Infow("User logged out everywhere", "user_id", claims.Sub)
+	return c.JSON(http.StatusOK, echo.Map{"message": "all sessions revoked"})
+}
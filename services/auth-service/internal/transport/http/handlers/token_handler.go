@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"auth-service/internal/middleware"
+	"auth-service/internal/models"
+	"auth-service/internal/service"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// TokenHandler — OAuth2 token endpoint для сервисных клиентов
+type TokenHandler struct {
+	service service.ClientCredentialsService
+	logger  *zap.SugaredLogger
+}
+
+// NewTokenHandler — конструктор
+func NewTokenHandler(service service.ClientCredentialsService, logger *zap.SugaredLogger) *TokenHandler {
+	return &TokenHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// IssueToken — POST /api/v1/auth/token (grant_type=client_credentials).
+// Принимает form-urlencoded или JSON; учётные данные — в теле или через HTTP Basic.
+func (h *TokenHandler) IssueToken(c echo.Context) error {
+	var req models.ClientCredentialsRequest
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	if err := c.Bind(&req); err != nil {
+		log.Warnw("Bind failed", "error", err)
+		return oauthError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+	}
+
+	// RFC 6749, 2.3.1: client_id и client_secret в Basic закодированы как form-urlencoded
+	if id, secret, ok := c.Request().BasicAuth(); ok {
+		req.ClientID, _ = url.QueryUnescape(id)
+		req.ClientSecret, _ = url.QueryUnescape(secret)
+	}
+
+	token, err := h.service.IssueToken(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnsupportedGrantType):
+			return oauthError(c, http.StatusBadRequest, "unsupported_grant_type", err.Error())
+		case errors.Is(err, service.ErrInvalidClient):
+			c.Response().Header().Set("WWW-Authenticate", `Basic realm="huddle"`)
+			return oauthError(c, http.StatusUnauthorized, "invalid_client", err.Error())
+		case errors.Is(err, service.ErrInvalidScope):
+			return oauthError(c, http.StatusBadRequest, "invalid_scope", err.Error())
+		case errors.Is(err, service.ErrInvalidTarget):
+			// RFC 8707, 2: целевой сервис не указан или недоступен клиенту
+			return oauthError(c, http.StatusBadRequest, "invalid_target", err.Error())
+		}
+		log.Errorw("Service token request failed", "client_id", req.ClientID, "error", err)
+		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "server_error"})
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, token)
+}
+
+// oauthError — ошибка в формате RFC 6749, 5.2
+func oauthError(c echo.Context, status int, code, description string) error {
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(status, echo.Map{
+		"error":             code,
+		"error_description": description,
+	})
+}
@@ -6,12 +6,14 @@ import (
 	"auth-service/pkg/logger"
 	"context"
 	"fmt"
+	"net"
 
 	"github.com/labstack/echo/v4"
 )
 
 type RouterConfig struct {
-	Port string
+	Port           string
+	TrustedProxies []*net.IPNet
 }
 
 type Router struct {
@@ -20,15 +22,27 @@ type Router struct {
 }
 
 func NewRouterConfig(cfg *config.Config) RouterConfig {
+	proxies := make([]*net.IPNet, 0, len(cfg.HTTPServer.TrustedProxies))
+	for _, cidr := range cfg.HTTPServer.TrustedProxies {
+		// Формат уже проверен валидатором конфига
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			proxies = append(proxies, ipNet)
+		}
+	}
+
 	return RouterConfig{
-		Port: cfg.HTTPServer.Port,
+		Port:           cfg.HTTPServer.Port,
+		TrustedProxies: proxies,
 	}
 }
 
 func NewRouter(rConfig RouterConfig, logger *logger.Logger) *Router {
 	r := echo.New()
+	r.HTTPErrorHandler = middleware.ErrorHandler()
+	r.IPExtractor = ipExtractor(rConfig.TrustedProxies)
 
 	r.Use(middleware.RequestIDMiddleware())
+	r.Use(middleware.ClientInfoMiddleware())
 	r.Use(middleware.LoggingMiddleware(logger.SugaredLogger))
 	r.Use(middleware.RecoverMiddleware(logger.SugaredLogger))
 
@@ -38,6 +52,20 @@ func NewRouter(rConfig RouterConfig, logger *logger.Logger) *Router {
 	}
 }
 
+// ipExtractor — IP клиента из X-Real-IP, только если запрос пришёл от доверенного прокси.
+// Иначе берётся адрес соединения: клиент не может подставить чужой IP и обойти лимиты по IP.
+func ipExtractor(proxies []*net.IPNet) echo.IPExtractor {
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range proxies {
+		options = append(options, echo.TrustIPRange(proxy))
+	}
+	return echo.ExtractIPFromRealIPHeader(options...)
+}
+
 func (r *Router) Run() error {
 	return r.router.Start(fmt.Sprintf(":%s", r.config.Port))
 }
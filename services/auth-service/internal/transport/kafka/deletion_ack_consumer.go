@@ -0,0 +1,68 @@
+package events
+
+import (
+	"auth-service/internal/models"
+	"auth-service/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// DeletionAckConsumer — читает подтверждения сервисов об удалении данных пользователя
+type DeletionAckConsumer struct {
+	reader  *kafka.Reader
+	service service.AccountDeletionService
+	logger  *zap.SugaredLogger
+}
+
+func NewDeletionAckConsumer(brokers []string, topic, groupID string, service service.AccountDeletionService, logger *zap.SugaredLogger) *DeletionAckConsumer {
+	return &DeletionAckConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:  brokers,
+			Topic:    topic,
+			GroupID:  groupID,
+			MinBytes: 1,
+			MaxBytes: 10e6, // 10MB
+		}),
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Start запускает цикл прослушивания сообщений
+func (c *DeletionAckConsumer) Start(ctx context.Context) {
+	c.logger.Infow("Deletion ack consumer started", "topic", c.reader.Config().Topic)
+
+	for {
+		msg, err := c.reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.logger.Errorw("Failed to read message from Kafka", "error", err)
+			continue
+		}
+
+		if err := c.processAck(ctx, msg.Value); err != nil {
+			// Потерянное подтверждение не ломает сагу: UserDeleted будет отправлен повторно
+			c.logger.Errorw("Failed to process deletion ack", "error", err)
+		}
+	}
+}
+
+func (c *DeletionAckConsumer) processAck(ctx context.Context, payload []byte) error {
+	var ack models.UserDeletionAck
+	if err := json.Unmarshal(payload, &ack); err != nil {
+		return fmt.Errorf("failed to unmarshal ack: %w", err)
+	}
+
+	c.logger.Infow("Received deletion ack", "user_id", ack.UserID, "service", ack.Service)
+	return c.service.ConfirmStep(ctx, ack)
+}
+
+func (c *DeletionAckConsumer) Close() error {
+	return c.reader.Close()
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"auth-service/internal/models"
+	"time"
+)
+
+// accessCacheMaxEntries — предельный размер кэша разобранных токенов
+const accessCacheMaxEntries = 10000
+
+// accessClaimsCache — локальный кэш разобранных access токенов по хэшу токена.
+// Снимает с nginx auth_request повторную проверку подписи на каждый проксируемый запрос.
+// Отзыв по-прежнему проверяется при каждом обращении (см. checkAccessRevoked).
+type accessClaimsCache struct {
+	ttl     time.Duration
+	entries *TTLCache[string, models.AccessTokenClaims]
+}
+
+func newAccessClaimsCache(ttl time.Duration) *accessClaimsCache {
+	return &accessClaimsCache{
+		ttl:     ttl,
+		entries: NewTTLCache[string, models.AccessTokenClaims](ttl, accessCacheMaxEntries),
+	}
+}
+
+// get — копия закэшированных claims (ok=false, если записи нет или она истекла)
+func (c *accessClaimsCache) get(tokenHash string) (*models.AccessTokenClaims, bool) {
+	claims, ok := c.entries.Get(tokenHash)
+	if !ok {
+		return nil, false
+	}
+	return &claims, true
+}
+
+// set — запись живёт ttl, но не дольше срока действия самого токена
+func (c *accessClaimsCache) set(tokenHash string, claims *models.AccessTokenClaims) {
+	until := time.Now().Add(c.ttl)
+	if exp := expiresAt(claims.ExpiresAt); !exp.IsZero() && exp.Before(until) {
+		until = exp
+	}
+	c.entries.SetUntil(tokenHash, *claims, until)
+}
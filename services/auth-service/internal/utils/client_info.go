@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"auth-service/internal/models"
+	"context"
+)
+
+type clientInfoKey struct{}
+
+// WithClientInfo — кладёт данные клиента (IP, User-Agent, request_id) в контекст запроса
+func WithClientInfo(ctx context.Context, client models.ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, client)
+}
+
+// ClientInfoFromContext — данные клиента из контекста (пустые, если запрос не из HTTP)
+func ClientInfoFromContext(ctx context.Context) models.ClientInfo {
+	client, _ := ctx.Value(clientInfoKey{}).(models.ClientInfo)
+	return client
+}
@@ -0,0 +1,350 @@
+package utils
+
+import (
+	"auth-service/internal/models"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// AlgRS256 — RSA PKCS#1 v1.5 + SHA-256
+	AlgRS256 = "RS256"
+	// AlgEdDSA — Ed25519
+	AlgEdDSA = "EdDSA"
+
+	activeKeyFile = "active"
+	keyFileExt    = ".pem"
+)
+
+// ErrNoSigningKeys — в каталоге ключей нет ни одного ключа
+var ErrNoSigningKeys = errors.New("no signing keys found")
+
+// SigningKey — ключ подписи JWT
+type SigningKey struct {
+	KID       string
+	Algorithm string
+	Private   crypto.Signer
+	Public    crypto.PublicKey
+}
+
+// KeyRing — активный ключ для подписи + предыдущие ключи, ещё валидные для проверки.
+// Ключи хранятся в каталоге: <kid>.pem (PKCS#8) и файл active с kid активного ключа.
+type KeyRing struct {
+	dir    string
+	mu     sync.RWMutex
+	active *SigningKey
+	keys   map[string]*SigningKey
+	logger *zap.SugaredLogger
+}
+
+// LoadKeyRing — загружает ключи из каталога
+func LoadKeyRing(dir string, logger *zap.SugaredLogger) (*KeyRing, error) {
+	kr := &KeyRing{dir: dir, logger: logger}
+	if err := kr.Reload(); err != nil {
+		return nil, err
+	}
+	return kr, nil
+}
+
+// Reload — перечитывает каталог (подхватывает ротацию без рестарта)
+func (kr *KeyRing) Reload() error {
+	kids, err := ListKeyIDs(kr.dir)
+	if err != nil {
+		return err
+	}
+	if len(kids) == 0 {
+		return ErrNoSigningKeys
+	}
+
+	keys := make(map[string]*SigningKey, len(kids))
+	for _, kid := range kids {
+		key, err := readSigningKey(kr.dir, kid)
+		if err != nil {
+			return err
+		}
+		keys[kid] = key
+	}
+
+	activeKID, err := ActiveKeyID(kr.dir)
+	if err != nil {
+		return err
+	}
+	if activeKID == "" {
+		// Файла active нет — активным считаем самый свежий ключ
+		activeKID = kids[len(kids)-1]
+	}
+	active, ok := keys[activeKID]
+	if !ok {
+		return fmt.Errorf("active key %q not found in %s", activeKID, kr.dir)
+	}
+
+	kr.mu.Lock()
+	changed := kr.active == nil || kr.active.KID != active.KID || len(kr.keys) != len(keys)
+	kr.active = active
+	kr.keys = keys
+	kr.mu.Unlock()
+
+	if changed {
+		kr.logger.Infow("JWT key ring loaded", "active_kid", active.KID, "alg", active.Algorithm, "keys", len(keys))
+	}
+	return nil
+}
+
+// Active — ключ для подписи новых токенов
+func (kr *KeyRing) Active() *SigningKey {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	return kr.active
+}
+
+// Lookup — ключ для проверки по kid
+func (kr *KeyRing) Lookup(kid string) (*SigningKey, bool) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	key, ok := kr.keys[kid]
+	return key, ok
+}
+
+// JWKS — публичные части всех ключей кольца
+func (kr *KeyRing) JWKS() models.JWKSet {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	set := models.JWKSet{Keys: make([]models.JWK, 0, len(kr.keys))}
+	for _, key := range kr.keys {
+		set.Keys = append(set.Keys, key.JWK())
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// JWK — публичная часть ключа в формате RFC 7517
+func (k *SigningKey) JWK() models.JWK {
+	jwk := models.JWK{Kid: k.KID, Use: "sig", Alg: k.Algorithm}
+	switch pub := k.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return jwk
+}
+
+// GenerateSigningKey — создаёт новый ключ в каталоге. Ключ сразу попадает в JWKS и принимается
+// при проверке, но подписывать начинает только после ActivateSigningKey: иначе реплики, ещё не
+// перечитавшие каталог, отклоняли бы токены с новым kid. Первый ключ в пустом каталоге активен сразу.
+func GenerateSigningKey(dir, alg string) (*SigningKey, error) {
+	var signer crypto.Signer
+	switch alg {
+	case AlgRS256:
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, fmt.Errorf("generate rsa key: %w", err)
+		}
+		signer = key
+	case AlgEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("generate ed25519 key: %w", err)
+		}
+		signer = key
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s", alg)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return nil, fmt.Errorf("marshal private key: %w", err)
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("generate kid: %w", err)
+	}
+	// kid начинается с времени создания — лексикографический порядок совпадает с хронологическим
+	kid := time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix)
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create keys dir: %w", err)
+	}
+
+	// Без файла active Reload считает активным самый свежий ключ — закрепляем текущий,
+	// чтобы новый ключ не стал активным раньше времени
+	kids, err := ListKeyIDs(dir)
+	if err != nil {
+		return nil, err
+	}
+	activeKID, err := ActiveKeyID(dir)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(kids) == 0:
+		activeKID = kid
+	case activeKID == "":
+		activeKID = kids[len(kids)-1]
+	}
+
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+keyFileExt), pemBytes, 0o600); err != nil {
+		return nil, fmt.Errorf("write key file: %w", err)
+	}
+	if err := SetActiveKeyID(dir, activeKID); err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{KID: kid, Algorithm: alg, Private: signer, Public: signer.Public()}, nil
+}
+
+// ActivateSigningKey — делает ключ kid активным (пустой kid — самый свежий ключ).
+// Ключ должен пролежать в каталоге не меньше minAge — интервала перечитывания ключей
+// (JWT_KEYS_RELOAD_INTERVAL), чтобы все реплики уже принимали его при проверке.
+func ActivateSigningKey(dir, kid string, minAge time.Duration) (string, error) {
+	if kid == "" {
+		kids, err := ListKeyIDs(dir)
+		if err != nil {
+			return "", err
+		}
+		if len(kids) == 0 {
+			return "", ErrNoSigningKeys
+		}
+		kid = kids[len(kids)-1]
+	}
+
+	info, err := os.Stat(filepath.Join(dir, kid+keyFileExt))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("key %q not found in %s", kid, dir)
+		}
+		return "", fmt.Errorf("stat key %s: %w", kid, err)
+	}
+	if age := time.Since(info.ModTime()); age < minAge {
+		return "", fmt.Errorf("key %s was added %s ago, wait at least %s so all replicas reload it",
+			kid, age.Round(time.Second), minAge)
+	}
+
+	if err := SetActiveKeyID(dir, kid); err != nil {
+		return "", err
+	}
+	return kid, nil
+}
+
+// ListKeyIDs — kid всех ключей каталога в хронологическом порядке
+func ListKeyIDs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read keys dir: %w", err)
+	}
+
+	var kids []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), keyFileExt) {
+			continue
+		}
+		kids = append(kids, strings.TrimSuffix(e.Name(), keyFileExt))
+	}
+	sort.Strings(kids)
+	return kids, nil
+}
+
+// ActiveKeyID — kid активного ключа (пустая строка, если файл active отсутствует)
+func ActiveKeyID(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, activeKeyFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read active key file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SetActiveKeyID — атомарно переключает активный ключ
+func SetActiveKeyID(dir, kid string) error {
+	tmp := filepath.Join(dir, activeKeyFile+".tmp")
+	if err := os.WriteFile(tmp, []byte(kid+"\n"), 0o600); err != nil {
+		return fmt.Errorf("write active key file: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, activeKeyFile)); err != nil {
+		return fmt.Errorf("switch active key: %w", err)
+	}
+	return nil
+}
+
+// PruneSigningKeys — удаляет старые неактивные ключи, оставляя keep самых свежих
+func PruneSigningKeys(dir string, keep int) ([]string, error) {
+	kids, err := ListKeyIDs(dir)
+	if err != nil {
+		return nil, err
+	}
+	activeKID, err := ActiveKeyID(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for i := 0; i < len(kids)-keep; i++ {
+		if kids[i] == activeKID {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, kids[i]+keyFileExt)); err != nil {
+			return removed, fmt.Errorf("remove key %s: %w", kids[i], err)
+		}
+		removed = append(removed, kids[i])
+	}
+	return removed, nil
+}
+
+// readSigningKey — читает PKCS#8 ключ и определяет алгоритм по типу
+func readSigningKey(dir, kid string) (*SigningKey, error) {
+	data, err := os.ReadFile(filepath.Join(dir, kid+keyFileExt))
+	if err != nil {
+		return nil, fmt.Errorf("read key %s: %w", kid, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: invalid PEM", kid)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", kid, err)
+	}
+
+	key := &SigningKey{KID: kid}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Algorithm = AlgRS256
+		key.Private = k
+	case ed25519.PrivateKey:
+		key.Algorithm = AlgEdDSA
+		key.Private = k
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T", kid, parsed)
+	}
+	key.Public = key.Private.Public()
+	return key, nil
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestGenerateSigningKey_DoesNotActivate(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := GenerateSigningKey(dir, AlgEdDSA)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	kr, err := LoadKeyRing(dir, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatalf("LoadKeyRing() error = %v", err)
+	}
+	if got := kr.Active().KID; got != first.KID {
+		t.Fatalf("first key is not active: active = %s, want %s", got, first.KID)
+	}
+
+	second, err := GenerateSigningKey(dir, AlgEdDSA)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	if err := kr.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	// Новый ключ уже проверяется и опубликован, но подписывает по-прежнему старый
+	if got := kr.Active().KID; got != first.KID {
+		t.Errorf("active = %s after rotate, want %s", got, first.KID)
+	}
+	if _, ok := kr.Lookup(second.KID); !ok {
+		t.Errorf("rotated key is not accepted for verification")
+	}
+	if n := len(kr.JWKS().Keys); n != 2 {
+		t.Errorf("JWKS has %d keys, want 2", n)
+	}
+}
+
+func TestGenerateSigningKey_PinsNewestWithoutActiveFile(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := GenerateSigningKey(dir, AlgEdDSA)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	// Каталог от старой версии: ключи есть, файла active нет
+	if err := os.Remove(filepath.Join(dir, activeKeyFile)); err != nil {
+		t.Fatalf("remove active file: %v", err)
+	}
+
+	if _, err := GenerateSigningKey(dir, AlgEdDSA); err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	active, err := ActiveKeyID(dir)
+	if err != nil {
+		t.Fatalf("ActiveKeyID() error = %v", err)
+	}
+	if active != first.KID {
+		t.Errorf("active = %q, want previously newest %q", active, first.KID)
+	}
+}
+
+func TestActivateSigningKey(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := GenerateSigningKey(dir, AlgEdDSA); err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	second, err := GenerateSigningKey(dir, AlgEdDSA)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+
+	// Реплики ещё не перечитали каталог
+	if _, err := ActivateSigningKey(dir, second.KID, time.Minute); err == nil {
+		t.Fatalf("ActivateSigningKey() activated a key younger than min age")
+	}
+	if _, err := ActivateSigningKey(dir, "missing", 0); err == nil {
+		t.Errorf("ActivateSigningKey() accepted unknown kid")
+	}
+
+	old := time.Now().Add(-2 * time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, second.KID+keyFileExt), old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	// kid двух ключей одной секунды упорядочены случайным суффиксом, поэтому kid указан явно
+	kid, err := ActivateSigningKey(dir, second.KID, time.Minute)
+	if err != nil {
+		t.Fatalf("ActivateSigningKey() error = %v", err)
+	}
+	if kid != second.KID {
+		t.Errorf("activated %s, want %s", kid, second.KID)
+	}
+	if active, _ := ActiveKeyID(dir); active != second.KID {
+		t.Errorf("active file = %s, want %s", active, second.KID)
+	}
+}
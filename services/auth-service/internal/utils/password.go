@@ -1,24 +1,173 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// hashPassword — bcrypt хэширование
+// Хэши хранятся в PHC-формате с идентификатором алгоритма:
+//
+//	$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>  — текущий формат
+//	$2a$10$...                                    — bcrypt, только проверка (старые пароли)
+const argon2idPrefix = "$argon2id$"
+
+// Границы параметров argon2id из хэша: нулевые t/p приводят к панике argon2.IDKey,
+// пустой хэш совпал бы с любым паролем, огромный m — выделение памяти на каждый вход
+const (
+	maxArgon2Memory     = 1024 * 1024 // KiB (1 GiB)
+	maxArgon2Iterations = 100
+	minArgon2SaltLength = 8
+	minArgon2KeyLength  = 16
+)
+
+var (
+	// ErrPasswordMismatch — пароль не совпадает с хэшем
+	ErrPasswordMismatch = errors.New("password mismatch")
+	// ErrUnknownHashFormat — хэш в неизвестном формате
+	ErrUnknownHashFormat = errors.New("unknown password hash format")
+)
+
+// Argon2Params — параметры argon2id
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params — параметры новых хэшей (рекомендации OWASP для argon2id).
+// Хэши с другими параметрами пересчитываются при входе.
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// HashPassword — argon2id хэш в PHC-формате
 func HashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", fmt.Errorf("bcrypt failed: %w", err)
+	p := DefaultArgon2Params
+
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
 	}
-	return string(hash), nil
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+
+	return encodeArgon2id(p, salt, key), nil
+}
+
+// DummyPasswordHash — хэш с DefaultArgon2Params, с которым сравнивается пароль при входе
+// по неизвестному email: проверка стоит столько же, сколько для существующего аккаунта,
+// и время ответа не выдаёт наличие аккаунта. Ключ из нулей: подобрать к нему пароль нереально.
+var DummyPasswordHash = encodeArgon2id(DefaultArgon2Params,
+	make([]byte, DefaultArgon2Params.SaltLength),
+	make([]byte, DefaultArgon2Params.KeyLength),
+)
+
+// encodeArgon2id — хэш в PHC-формате: $argon2id$v=19$m=...,t=...,p=...$<salt>$<key>
+func encodeArgon2id(p Argon2Params, salt, key []byte) string {
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
 }
 
-// comparePassword — сравнение хэша
+// ComparePassword — проверка пароля; алгоритм определяется по префиксу хэша
 func ComparePassword(hash, password string) error {
-	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
-		return fmt.Errorf("password mismatch")
+	switch {
+	case strings.HasPrefix(hash, argon2idPrefix):
+		params, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return err
+		}
+		actual := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(actual, key) != 1 {
+			return ErrPasswordMismatch
+		}
+		return nil
+
+	case isBcryptHash(hash):
+		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+			return ErrPasswordMismatch
+		}
+		return nil
 	}
-	return nil
+
+	return ErrUnknownHashFormat
+}
+
+// NeedsRehash — хэш устарел: другой алгоритм или параметры слабее текущих
+func NeedsRehash(hash string) bool {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return true
+	}
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	p := DefaultArgon2Params
+	return params.Memory != p.Memory ||
+		params.Iterations != p.Iterations ||
+		params.Parallelism != p.Parallelism ||
+		uint32(len(salt)) != p.SaltLength ||
+		uint32(len(key)) != p.KeyLength
+}
+
+// decodeArgon2id — разбор $argon2id$v=19$m=..,t=..,p=..$salt$hash
+func decodeArgon2id(hash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, fmt.Errorf("parse argon2 version: %w", err)
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("parse argon2 params: %w", err)
+	}
+
+	if params.Memory == 0 || params.Memory > maxArgon2Memory ||
+		params.Iterations == 0 || params.Iterations > maxArgon2Iterations ||
+		params.Parallelism == 0 {
+		return params, nil, nil, fmt.Errorf("argon2 params out of range: m=%d,t=%d,p=%d", params.Memory, params.Iterations, params.Parallelism)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("decode argon2 salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("decode argon2 hash: %w", err)
+	}
+	if len(salt) < minArgon2SaltLength || len(key) < minArgon2KeyLength {
+		return params, nil, nil, fmt.Errorf("argon2 salt or hash too short: salt=%d, hash=%d bytes", len(salt), len(key))
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
 }
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+//go:embed common_passwords.txt
+var embeddedCommonPasswords string
+
+// LoadCommonPasswords — встроенный список распространённых паролей + необязательный файл
+// (по одному паролю в строке, строки с # — комментарии). Пароли приводятся к нижнему регистру.
+func LoadCommonPasswords(path string) (map[string]struct{}, error) {
+	set := make(map[string]struct{})
+	readPasswordList(strings.NewReader(embeddedCommonPasswords), set)
+
+	if path == "" {
+		return set, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open common password list: %w", err)
+	}
+	defer f.Close()
+
+	if err := readPasswordList(f, set); err != nil {
+		return nil, fmt.Errorf("read common password list: %w", err)
+	}
+	return set, nil
+}
+
+func readPasswordList(r io.Reader, set map[string]struct{}) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		set[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// phc — argon2id хэш из частей: salt и hash задаются в байтах и кодируются как в HashPassword
+func phc(version int, params string, salt, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=%d$%s$%s$%s", version, params,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func TestComparePassword(t *testing.T) {
+	hash, err := HashPassword("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("legacy-password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		wantErr  error
+	}{
+		{name: "argon2id match", hash: hash, password: "correct horse battery staple"},
+		{name: "argon2id mismatch", hash: hash, password: "wrong", wantErr: ErrPasswordMismatch},
+		{name: "bcrypt match", hash: string(bcryptHash), password: "legacy-password"},
+		{name: "bcrypt mismatch", hash: string(bcryptHash), password: "wrong", wantErr: ErrPasswordMismatch},
+		{name: "unknown format", hash: "plaintext", password: "plaintext", wantErr: ErrUnknownHashFormat},
+		{name: "empty hash", hash: "", password: "", wantErr: ErrUnknownHashFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ComparePassword(tt.hash, tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ComparePassword() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeArgon2id(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	tests := []struct {
+		name    string
+		hash    string
+		want    Argon2Params
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			hash: phc(19, "m=65536,t=3,p=2", salt, key),
+			want: Argon2Params{Memory: 65536, Iterations: 3, Parallelism: 2, SaltLength: 16, KeyLength: 32},
+		},
+		{name: "too few segments", hash: "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA", wantErr: true},
+		{name: "too many segments", hash: phc(19, "m=65536,t=3,p=2", salt, key) + "$extra", wantErr: true},
+		{name: "only prefix", hash: "$argon2id$", wantErr: true},
+		{name: "missing version", hash: "$argon2id$$m=65536,t=3,p=2$c2FsdHNhbHQ$aGFzaA", wantErr: true},
+		{name: "wrong version", hash: phc(16, "m=65536,t=3,p=2", salt, key), wantErr: true},
+		{name: "garbage params", hash: phc(19, "memory=lots", salt, key), wantErr: true},
+		{name: "negative memory", hash: phc(19, "m=-1,t=3,p=2", salt, key), wantErr: true},
+		{name: "parallelism overflows uint8", hash: phc(19, "m=65536,t=3,p=300", salt, key), wantErr: true},
+		{name: "zero iterations", hash: phc(19, "m=65536,t=0,p=2", salt, key), wantErr: true},
+		{name: "zero parallelism", hash: phc(19, "m=65536,t=3,p=0", salt, key), wantErr: true},
+		{name: "zero memory", hash: phc(19, "m=0,t=3,p=2", salt, key), wantErr: true},
+		{name: "huge memory", hash: phc(19, "m=4294967295,t=3,p=2", salt, key), wantErr: true},
+		{name: "huge iterations", hash: phc(19, "m=65536,t=4294967295,p=2", salt, key), wantErr: true},
+		{name: "salt not base64", hash: "$argon2id$v=19$m=65536,t=3,p=2$!!!$" + base64.RawStdEncoding.EncodeToString(key), wantErr: true},
+		{name: "hash not base64", hash: "$argon2id$v=19$m=65536,t=3,p=2$" + base64.RawStdEncoding.EncodeToString(salt) + "$!!!", wantErr: true},
+		{name: "padded base64", hash: "$argon2id$v=19$m=65536,t=3,p=2$" + base64.StdEncoding.EncodeToString([]byte("salt-salt!")) + "$" + base64.RawStdEncoding.EncodeToString(key), wantErr: true},
+		{name: "empty hash", hash: phc(19, "m=65536,t=3,p=2", salt, nil), wantErr: true},
+		{name: "short salt", hash: phc(19, "m=65536,t=3,p=2", []byte("salt"), key), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, _, _, err := decodeArgon2id(tt.hash)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeArgon2id() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && params != tt.want {
+				t.Errorf("decodeArgon2id() params = %+v, want %+v", params, tt.want)
+			}
+
+			// Битый хэш из БД — ошибка входа, а не паника и не совпадение с любым паролем
+			if tt.wantErr {
+				if err := ComparePassword(tt.hash, ""); err == nil {
+					t.Errorf("ComparePassword() accepted malformed hash")
+				}
+				if !NeedsRehash(tt.hash) {
+					t.Errorf("NeedsRehash() = false for malformed hash")
+				}
+			}
+		})
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	current, err := HashPassword("password")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	salt := make([]byte, DefaultArgon2Params.SaltLength)
+	key := make([]byte, DefaultArgon2Params.KeyLength)
+
+	tests := []struct {
+		name string
+		hash string
+		want bool
+	}{
+		{name: "current params", hash: current, want: false},
+		{name: "bcrypt", hash: "$2a$10$7EqJtq98hPqEX7fNZaFWoO5N7E1jS1r8bO1H6sGvTvZ0S4Yq1Pp2C", want: true},
+		{name: "unknown format", hash: "md5:5f4dcc3b5aa765d61d8327deb882cf99", want: true},
+		{name: "lower memory", hash: phc(19, "m=19456,t=3,p=2", salt, key), want: true},
+		{name: "fewer iterations", hash: phc(19, "m=65536,t=2,p=2", salt, key), want: true},
+		{name: "other parallelism", hash: phc(19, "m=65536,t=3,p=1", salt, key), want: true},
+		{name: "longer salt", hash: phc(19, "m=65536,t=3,p=2", make([]byte, 32), key), want: true},
+		{name: "shorter key", hash: phc(19, "m=65536,t=3,p=2", salt, make([]byte, 16)), want: true},
+		{name: "same params, explicit", hash: phc(19, "m=65536,t=3,p=2", salt, key), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashPassword_Format(t *testing.T) {
+	hash, err := HashPassword("password")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=65536,t=3,p=2$") {
+		t.Errorf("HashPassword() = %q, want argon2id PHC string with default params", hash)
+	}
+
+	other, err := HashPassword("password")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if hash == other {
+		t.Errorf("two hashes of the same password are equal: salt is not random")
+	}
+}
+
+func TestDummyPasswordHash(t *testing.T) {
+	params, _, _, err := decodeArgon2id(DummyPasswordHash)
+	if err != nil {
+		t.Fatalf("decodeArgon2id(DummyPasswordHash) error = %v", err)
+	}
+	// Параметры как у настоящих хэшей — иначе время проверки отличалось бы
+	if params != DefaultArgon2Params {
+		t.Errorf("dummy params = %+v, want %+v", params, DefaultArgon2Params)
+	}
+	if NeedsRehash(DummyPasswordHash) {
+		t.Errorf("NeedsRehash(DummyPasswordHash) = true, dummy hash is weaker than real ones")
+	}
+	for _, password := range []string{"", "password", "correct horse battery staple"} {
+		if err := ComparePassword(DummyPasswordHash, password); !errors.Is(err, ErrPasswordMismatch) {
+			t.Errorf("ComparePassword(dummy, %q) error = %v, want %v", password, err, ErrPasswordMismatch)
+		}
+	}
+}
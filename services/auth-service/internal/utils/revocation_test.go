@@ -0,0 +1,248 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// fakeRevocationStore — denylist с управляемым ответом и счётчиком обращений
+type fakeRevocationStore struct {
+	revoked bool
+	err     error
+	calls   int
+}
+
+func (f *fakeRevocationStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	f.calls++
+	return f.revoked, f.err
+}
+
+func TestParseAccess(t *testing.T) {
+	errRedisDown := errors.New("dial tcp: connection refused")
+
+	tests := []struct {
+		name           string
+		accessCacheTTL time.Duration
+		revCacheTTL    time.Duration
+		failOpen       bool
+		store          fakeRevocationStore
+		parses         int
+		wantErr        bool
+		wantStoreCalls int
+	}{
+		{
+			name:           "active token",
+			parses:         1,
+			wantStoreCalls: 1,
+		},
+		{
+			name:           "revoked jti",
+			store:          fakeRevocationStore{revoked: true},
+			parses:         1,
+			wantErr:        true,
+			wantStoreCalls: 1,
+		},
+		{
+			name:           "redis down, fail-open",
+			failOpen:       true,
+			store:          fakeRevocationStore{err: errRedisDown},
+			parses:         1,
+			wantStoreCalls: 1,
+		},
+		{
+			name:           "redis down, fail-closed",
+			store:          fakeRevocationStore{err: errRedisDown},
+			parses:         1,
+			wantErr:        true,
+			wantStoreCalls: 1,
+		},
+		{
+			name:           "revocation cache hit skips redis",
+			revCacheTTL:    time.Minute,
+			parses:         3,
+			wantStoreCalls: 1,
+		},
+		{
+			name:           "revoked result is cached",
+			revCacheTTL:    time.Minute,
+			store:          fakeRevocationStore{revoked: true},
+			parses:         3,
+			wantErr:        true,
+			wantStoreCalls: 1,
+		},
+		{
+			name:           "claims cache hit still checks revocation",
+			accessCacheTTL: time.Minute,
+			parses:         3,
+			wantStoreCalls: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := tt.store
+			s, _ := newTestTokenService(t, func(cfg *TokenServiceConfig) {
+				cfg.Denylist = &store
+				cfg.AccessCacheTTL = tt.accessCacheTTL
+				cfg.RevocationCacheTTL = tt.revCacheTTL
+				cfg.RevocationFailOpen = tt.failOpen
+			})
+			pair := generateTestPair(t, s)
+
+			for i := range tt.parses {
+				claims, err := s.ParseAccess(context.Background(), pair.AccessToken)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("parse #%d: error = %v, wantErr %v", i+1, err, tt.wantErr)
+				}
+				if err == nil && claims.JTI != pair.JTI {
+					t.Fatalf("parse #%d: jti = %q, want %q", i+1, claims.JTI, pair.JTI)
+				}
+			}
+			if store.calls != tt.wantStoreCalls {
+				t.Errorf("denylist calls = %d, want %d", store.calls, tt.wantStoreCalls)
+			}
+		})
+	}
+}
+
+func TestParseAccess_CachedClaimsRevoked(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeRevocationStore{}
+	s, _ := newTestTokenService(t, func(cfg *TokenServiceConfig) {
+		cfg.Denylist = store
+		cfg.AccessCacheTTL = time.Minute
+	})
+	pair := generateTestPair(t, s)
+
+	if _, err := s.ParseAccess(ctx, pair.AccessToken); err != nil {
+		t.Fatalf("first parse: %v", err)
+	}
+	if _, ok := s.claimCache.get(HashToken(pair.AccessToken)); !ok {
+		t.Fatalf("claims are not cached after successful parse")
+	}
+
+	// Отзыв после кэширования claims должен действовать сразу
+	store.revoked = true
+	if _, err := s.ParseAccess(ctx, pair.AccessToken); err == nil {
+		t.Fatalf("revoked token accepted from claims cache")
+	}
+}
+
+func TestParseAccess_RejectsServiceToken(t *testing.T) {
+	s, _ := newTestTokenService(t, nil)
+
+	token, err := s.GenerateServiceToken("billing", "event-service", []string{"events:read"})
+	if err != nil {
+		t.Fatalf("generate service token: %v", err)
+	}
+	if _, err := s.ParseAccess(context.Background(), token.AccessToken); err == nil {
+		t.Fatalf("service token accepted as user access token")
+	}
+}
+
+func TestRevocationCache(t *testing.T) {
+	t.Run("disabled cache stores nothing", func(t *testing.T) {
+		c := newRevocationCache(0)
+		c.setActive("jti")
+		c.setRevoked("jti", time.Now().Add(time.Hour))
+		if _, ok := c.get("jti"); ok {
+			t.Fatalf("disabled cache returned an entry")
+		}
+	})
+
+	t.Run("active entry expires after ttl", func(t *testing.T) {
+		c := newRevocationCache(time.Minute)
+		c.setActive("jti")
+		if revoked, ok := c.get("jti"); !ok || revoked {
+			t.Fatalf("get() = (%v, %v), want (false, true)", revoked, ok)
+		}
+
+		c.entries.entries["jti"] = ttlCacheEntry[bool]{expiresAt: time.Now().Add(-time.Second)}
+		if _, ok := c.get("jti"); ok {
+			t.Fatalf("expired entry returned")
+		}
+	})
+
+	t.Run("revoked entry lives until token expiry", func(t *testing.T) {
+		c := newRevocationCache(time.Minute)
+		until := time.Now().Add(time.Hour)
+		c.setRevoked("jti", until)
+		if revoked, ok := c.get("jti"); !ok || !revoked {
+			t.Fatalf("get() = (%v, %v), want (true, true)", revoked, ok)
+		}
+		if got := c.entries.entries["jti"].expiresAt; !got.Equal(until) {
+			t.Errorf("expiresAt = %v, want %v", got, until)
+		}
+	})
+}
+
+func TestAccessClaimsCache(t *testing.T) {
+	newClaims := func(exp time.Duration) *models.AccessTokenClaims {
+		claims := models.NewAccessTokenClaims(uuid.NewString(), "user@example.com", "user", nil, uuid.NewString(), "auth-service", exp)
+		return &claims
+	}
+
+	t.Run("disabled cache stores nothing", func(t *testing.T) {
+		c := newAccessClaimsCache(0)
+		c.set("hash", newClaims(time.Hour))
+		if _, ok := c.get("hash"); ok {
+			t.Fatalf("disabled cache returned an entry")
+		}
+	})
+
+	t.Run("entry returns a copy", func(t *testing.T) {
+		c := newAccessClaimsCache(time.Minute)
+		c.set("hash", newClaims(time.Hour))
+
+		got, ok := c.get("hash")
+		if !ok {
+			t.Fatalf("entry not found")
+		}
+		got.Role = "admin"
+		if again, _ := c.get("hash"); again.Role != "user" {
+			t.Errorf("cached claims were modified through returned pointer")
+		}
+	})
+
+	t.Run("ttl is capped by token expiry", func(t *testing.T) {
+		c := newAccessClaimsCache(time.Hour)
+		claims := newClaims(time.Minute)
+		c.set("hash", claims)
+
+		if got, want := c.entries.entries["hash"].expiresAt, claims.ExpiresAt.Time; !got.Equal(want) {
+			t.Errorf("expiresAt = %v, want token exp %v", got, want)
+		}
+	})
+
+	t.Run("expired entry is removed on get", func(t *testing.T) {
+		c := newAccessClaimsCache(time.Minute)
+		c.set("hash", newClaims(time.Hour))
+		entry := c.entries.entries["hash"]
+		entry.expiresAt = time.Now().Add(-time.Second)
+		c.entries.entries["hash"] = entry
+
+		if _, ok := c.get("hash"); ok {
+			t.Fatalf("expired entry returned")
+		}
+		if _, found := c.entries.entries["hash"]; found {
+			t.Errorf("expired entry was not deleted")
+		}
+	})
+}
+
+// generateTestPair — пара токенов нового пользователя
+func generateTestPair(t *testing.T, s *tokenService) *models.TokenPair {
+	t.Helper()
+
+	pair, err := s.GeneratePair(context.Background(), uuid.New(), "user@example.com", "user", models.ClientInfo{IP: "203.0.113.7"})
+	if err != nil {
+		t.Fatalf("generate pair: %v", err)
+	}
+	return pair
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"auth-service/internal/models"
+	"auth-service/pkg/apperror"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrSessionNotFound — сессия не найдена или принадлежит другому пользователю
+var ErrSessionNotFound = apperror.NotFound("session not found")
+
+// sessionKey — hash с метаданными сессии (user_id, user_agent, ip, created_at, last_refresh_at)
+func sessionKey(jti string) string {
+	return "session:" + jti
+}
+
+// getSession — читает метаданные сессии (nil, если сессии нет)
+func (s *tokenService) getSession(ctx context.Context, jti string) (*models.Session, error) {
+	fields, err := s.redis.HGetAll(ctx, sessionKey(jti)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("load session: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return &models.Session{
+		JTI:           jti,
+		UserAgent:     fields["user_agent"],
+		IP:            fields["ip"],
+		CreatedAt:     parseUnix(fields["created_at"]),
+		LastRefreshAt: parseUnix(fields["last_refresh_at"]),
+	}, nil
+}
+
+// ListSessions — активные сессии пользователя, свежие первыми
+func (s *tokenService) ListSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	key := userJTIsKey(userID.String())
+	jtis, err := s.redis.SMembers(ctx, key).Result()
+	if err != nil {
+		s.logger.Errorw("Failed to list user sessions", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("list user sessions: %w", err)
+	}
+
+	sessions := make([]models.Session, 0, len(jtis))
+	for _, jti := range jtis {
+		session, err := s.getSession(ctx, jti)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil {
+			// Сессия истекла по TTL — чистим индекс
+			s.redis.SRem(ctx, key, jti)
+			continue
+		}
+		sessions = append(sessions, *session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastRefreshAt.After(sessions[j].LastRefreshAt)
+	})
+	return sessions, nil
+}
+
+// RevokeSession — завершает одну сессию пользователя
+func (s *tokenService) RevokeSession(ctx context.Context, userID uuid.UUID, jti string) error {
+	owned, err := s.redis.SIsMember(ctx, userJTIsKey(userID.String()), jti).Result()
+	if err != nil {
+		s.logger.Errorw("Failed to check session owner", "user_id", userID, "jti", jti, "error", err)
+		return fmt.Errorf("check session owner: %w", err)
+	}
+	if !owned {
+		return ErrSessionNotFound
+	}
+	return s.RevokeRefresh(ctx, jti)
+}
+
+func parseUnix(v string) time.Time {
+	sec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
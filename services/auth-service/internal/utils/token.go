@@ -4,6 +4,7 @@ import (
 	"auth-service/internal/models"
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,40 +15,65 @@ import (
 
 // TokenService — безопасное управление JWT-токенами с отзывом через Redis
 type TokenService interface {
-	GeneratePair(ctx context.Context, userID uuid.UUID, email, role string) (*models.TokenPair, error)
-	ParseAccess(tokenStr string) (*models.AccessTokenClaims, error)
+	GeneratePair(ctx context.Context, userID uuid.UUID, email, role string, client models.ClientInfo) (*models.TokenPair, error)
+	ParseAccess(ctx context.Context, tokenStr string) (*models.AccessTokenClaims, error)
 	ParseRefresh(tokenStr string) (*models.RefreshTokenClaims, error)
 	RevokeRefresh(ctx context.Context, jti string) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	RevokeAllExcept(ctx context.Context, userID uuid.UUID, keepJTI string) error
 	IsRevoked(ctx context.Context, jti string) (bool, error)
-	RotateRefresh(ctx context.Context, oldRefreshToken string, userID uuid.UUID, email, role string) (*models.TokenPair, error)
+	RotateRefresh(ctx context.Context, oldRefreshToken string, userID uuid.UUID, email, role string, client models.ClientInfo) (*models.TokenPair, error)
+	ListSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID uuid.UUID, jti string) error
+	GenerateServiceToken(clientID, audience string, scopes []string) (*models.ServiceToken, error)
 }
 
+// ServiceTokenType — заголовок typ машинного токена: такой токен не принимается как access токен пользователя
+const ServiceTokenType = "svc+jwt"
+
 // tokenService — реализация
 type tokenService struct {
-	signingKey []byte
+	keys       *KeyRing
 	accessTTL  time.Duration
 	refreshTTL time.Duration
+	serviceTTL time.Duration
 	issuer     string
 	redis      *redis.Client
+	denylist   RevocationStore
+	revCache   *revocationCache
+	claimCache *accessClaimsCache
+	failOpen   bool
+	perms      PermissionResolver
 	logger     *zap.SugaredLogger
 }
 
+// PermissionResolver — права роли для claim perms access токена
+type PermissionResolver interface {
+	PermissionsForRole(ctx context.Context, role string) ([]string, error)
+}
+
 // TokenServiceConfig — конфигурация
 type TokenServiceConfig struct {
-	SigningKey string
-	AccessTTL  time.Duration
-	RefreshTTL time.Duration
-	Issuer     string
-	Redis      *redis.Client
-	Logger     *zap.SugaredLogger
+	KeyRing            *KeyRing
+	AccessTTL          time.Duration
+	RefreshTTL         time.Duration
+	ServiceTTL         time.Duration // Время жизни машинных токенов (client-credentials)
+	Issuer             string
+	Redis              *redis.Client
+	Denylist           RevocationStore    // Проверка отзыва access токенов; по умолчанию — напрямую через Redis
+	RevocationCacheTTL time.Duration      // Локальный кэш результата проверки отзыва (0 — без кэша)
+	RevocationFailOpen bool               // Пропускать токены, если denylist недоступен
+	AccessCacheTTL     time.Duration      // Локальный кэш разобранных access токенов (0 — без кэша)
+	Permissions        PermissionResolver // Права ролей; без него токены выдаются без perms
+	Logger             *zap.SugaredLogger
 }
 
 // NewTokenService — конструктор с валидацией
 func NewTokenService(cfg TokenServiceConfig) (TokenService, error) {
-	if cfg.SigningKey == "" {
-		return nil, fmt.Errorf("signing key is required")
+	if cfg.KeyRing == nil {
+		return nil, fmt.Errorf("key ring is required")
 	}
-	if cfg.AccessTTL <= 0 || cfg.RefreshTTL <= 0 {
+	if cfg.AccessTTL <= 0 || cfg.RefreshTTL <= 0 || cfg.ServiceTTL <= 0 {
 		return nil, fmt.Errorf("token TTLs must be positive")
 	}
 	if cfg.Redis == nil {
@@ -60,52 +86,132 @@ func NewTokenService(cfg TokenServiceConfig) (TokenService, error) {
 		cfg.Issuer = "auth-service"
 	}
 
-	return &tokenService{
-		signingKey: []byte(cfg.SigningKey),
+	s := &tokenService{
+		keys:       cfg.KeyRing,
 		accessTTL:  cfg.AccessTTL,
 		refreshTTL: cfg.RefreshTTL,
+		serviceTTL: cfg.ServiceTTL,
 		issuer:     cfg.Issuer,
 		redis:      cfg.Redis,
+		denylist:   cfg.Denylist,
+		revCache:   newRevocationCache(cfg.RevocationCacheTTL),
+		claimCache: newAccessClaimsCache(cfg.AccessCacheTTL),
+		failOpen:   cfg.RevocationFailOpen,
+		perms:      cfg.Permissions,
 		logger:     cfg.Logger,
-	}, nil
+	}
+	if s.denylist == nil {
+		s.denylist = s
+	}
+	return s, nil
 }
 
-// keyFunc — возвращает ключ для подписи/проверки
-func (s *tokenService) keyFunc(*jwt.Token) (interface{}, error) {
-	return s.signingKey, nil
+// validMethods — алгоритмы, допустимые при проверке подписи
+var validMethods = []string{AlgRS256, AlgEdDSA}
+
+// keyFunc — выбирает публичный ключ проверки по заголовку kid
+func (s *tokenService) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok || kid == "" {
+		return nil, fmt.Errorf("missing kid header")
+	}
+	key, ok := s.keys.Lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("algorithm %s does not match key %q", token.Method.Alg(), kid)
+	}
+	return key.Public, nil
 }
 
-// GeneratePair — создаёт пару токенов + сохраняет jti в Redis
-func (s *tokenService) GeneratePair(ctx context.Context, userID uuid.UUID, email, role string) (*models.TokenPair, error) {
+// sign — подписывает claims активным ключом и проставляет kid
+func (s *tokenService) sign(claims jwt.Claims) (string, error) {
+	return s.signTyped(claims, "")
+}
+
+// signTyped — как sign, но с заголовком typ (пустой typ оставляет стандартный "JWT")
+func (s *tokenService) signTyped(claims jwt.Claims, typ string) (string, error) {
+	key := s.keys.Active()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.KID
+	if typ != "" {
+		token.Header["typ"] = typ
+	}
+	return token.SignedString(key.Private)
+}
+
+// GeneratePair — создаёт пару токенов для новой сессии + сохраняет jti в Redis
+func (s *tokenService) GeneratePair(ctx context.Context, userID uuid.UUID, email, role string, client models.ClientInfo) (*models.TokenPair, error) {
+	now := time.Now()
 	jti := uuid.New().String()
+	// Новая сессия — новое семейство refresh токенов
+	family := uuid.New().String()
+	if err := s.redis.Set(ctx, familyKey(family), jti, s.refreshTTL).Err(); err != nil {
+		s.logger.Errorw("Failed to create token family", "family", family, "error", err)
+		return nil, fmt.Errorf("failed to create token family: %w", err)
+	}
+	return s.issuePair(ctx, userID, email, role, jti, family, models.Session{
+		UserAgent:     client.UserAgent,
+		IP:            client.IP,
+		CreatedAt:     now,
+		LastRefreshAt: now,
+	})
+}
+
+// issuePair — подписывает пару токенов с заданным jti и сохраняет сессию
+func (s *tokenService) issuePair(ctx context.Context, userID uuid.UUID, email, role, jti, family string, session models.Session) (*models.TokenPair, error) {
 	userIDStr := userID.String()
 
 	s.logger.Infow("Generating token pair",
 		"user_id", userIDStr,
 		"email", email,
 		"jti", jti,
+		"family", family,
 	)
 
+	// Права роли резолвятся при каждой выдаче: после смены роли новые права попадут в токен при refresh
+	var permissions []string
+	if s.perms != nil {
+		perms, err := s.perms.PermissionsForRole(ctx, role)
+		if err != nil {
+			s.logger.Errorw("Failed to resolve role permissions", "role", role, "error", err)
+			return nil, fmt.Errorf("resolve permissions: %w", err)
+		}
+		permissions = perms
+	}
+
 	// Access Token
-	accessClaims := models.NewAccessTokenClaims(userIDStr, email, role, jti, s.issuer, s.accessTTL)
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	signedAccess, err := accessToken.SignedString(s.signingKey)
+	accessClaims := models.NewAccessTokenClaims(userIDStr, email, role, permissions, jti, s.issuer, s.accessTTL)
+	signedAccess, err := s.sign(accessClaims)
 	if err != nil {
 		s.logger.Errorw("Failed to sign access token", "error", err)
 		return nil, fmt.Errorf("sign access token: %w", err)
 	}
 
 	// Refresh Token
-	refreshClaims := models.NewRefreshTokenClaims(userIDStr, jti, s.issuer, s.refreshTTL)
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	signedRefresh, err := refreshToken.SignedString(s.signingKey)
+	refreshClaims := models.NewRefreshTokenClaims(userIDStr, jti, family, s.issuer, s.refreshTTL)
+	signedRefresh, err := s.sign(refreshClaims)
 	if err != nil {
 		s.logger.Errorw("Failed to sign refresh token", "error", err)
 		return nil, fmt.Errorf("sign refresh token: %w", err)
 	}
 
-	// Сохраняем jti в Redis (white list)
-	if err := s.redis.Set(ctx, "jti:"+jti, userIDStr, s.refreshTTL).Err(); err != nil {
+	// Сохраняем jti в Redis (white list), метаданные сессии и индекс выданных jti пользователя
+	pipe := s.redis.TxPipeline()
+	pipe.Set(ctx, "jti:"+jti, userIDStr, s.refreshTTL)
+	pipe.HSet(ctx, sessionKey(jti),
+		"user_id", userIDStr,
+		"family", family,
+		"user_agent", session.UserAgent,
+		"ip", session.IP,
+		"created_at", session.CreatedAt.Unix(),
+		"last_refresh_at", session.LastRefreshAt.Unix(),
+	)
+	pipe.Expire(ctx, sessionKey(jti), s.refreshTTL)
+	pipe.SAdd(ctx, userJTIsKey(userIDStr), jti)
+	pipe.Expire(ctx, userJTIsKey(userIDStr), s.refreshTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
 		s.logger.Errorw("Failed to store jti in Redis", "jti", jti, "error", err)
 		return nil, fmt.Errorf("failed to store jti in redis: %w", err)
 	}
@@ -119,30 +225,88 @@ func (s *tokenService) GeneratePair(ctx context.Context, userID uuid.UUID, email
 		RefreshToken: signedRefresh,
 		ExpiresIn:    exp,
 		TokenType:    "Bearer",
+		JTI:          jti,
 	}, nil
 }
 
-// ParseAccess — парсит и валидирует access токен
-func (s *tokenService) ParseAccess(tokenStr string) (*models.AccessTokenClaims, error) {
+// ParseAccess — парсит и валидирует access токен + проверяет отзыв (logout, смена пароля).
+// Результат проверки подписи кэшируется по хэшу токена; отзыв проверяется всегда.
+func (s *tokenService) ParseAccess(ctx context.Context, tokenStr string) (*models.AccessTokenClaims, error) {
+	tokenHash := HashToken(tokenStr)
+	if claims, ok := s.claimCache.get(tokenHash); ok {
+		if err := s.checkAccessRevoked(ctx, claims); err != nil {
+			return nil, err
+		}
+		return claims, nil
+	}
+
 	claims := &models.AccessTokenClaims{}
-	_, err := jwt.ParseWithClaims(tokenStr, claims, s.keyFunc,
+	token, err := jwt.ParseWithClaims(tokenStr, claims, s.keyFunc,
 		jwt.WithIssuer(s.issuer),
-		jwt.WithValidMethods([]string{"HS256"}),
+		jwt.WithValidMethods(validMethods),
 		jwt.WithLeeway(5*time.Second),
 	)
 	if err != nil {
 		s.logger.Warnw("Invalid access token", "error", err)
 		return nil, fmt.Errorf("invalid access token: %w", err)
 	}
+	// Машинный токен подписан тем же ключом, но не представляет пользователя
+	if typ, _ := token.Header["typ"].(string); typ == ServiceTokenType {
+		s.logger.Warnw("Service token presented as user access token", "client", claims.Subject)
+		return nil, fmt.Errorf("invalid access token: service tokens are not accepted")
+	}
+
+	if err := s.checkAccessRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+	s.claimCache.set(tokenHash, claims)
 	return claims, nil
 }
 
-// ParseRefresh — парсит refresh токен + проверяет отзыв
+// checkAccessRevoked — проверка denylist с локальным кэшем.
+// При недоступности Redis (в т.ч. открытом circuit breaker) поведение задаётся failOpen.
+func (s *tokenService) checkAccessRevoked(ctx context.Context, claims *models.AccessTokenClaims) error {
+	revoked, cached := s.revCache.get(claims.JTI)
+	if !cached {
+		var err error
+		revoked, err = s.denylist.IsRevoked(ctx, claims.JTI)
+		if err != nil {
+			if s.failOpen {
+				s.logger.Warnw("Revocation check unavailable, accepting token (fail-open)", "jti", claims.JTI, "error", err)
+				return nil
+			}
+			s.logger.Errorw("Revocation check unavailable, rejecting token (fail-closed)", "jti", claims.JTI, "error", err)
+			return fmt.Errorf("revocation check failed: %w", err)
+		}
+
+		if revoked {
+			s.revCache.setRevoked(claims.JTI, expiresAt(claims.ExpiresAt))
+		} else {
+			s.revCache.setActive(claims.JTI)
+		}
+	}
+
+	if revoked {
+		s.logger.Infow("Access token is revoked", "jti", claims.JTI, "user_id", claims.Sub)
+		return fmt.Errorf("access token revoked")
+	}
+	return nil
+}
+
+func expiresAt(exp *jwt.NumericDate) time.Time {
+	if exp == nil {
+		return time.Time{}
+	}
+	return exp.Time
+}
+
+// ParseRefresh — парсит refresh токен и проверяет подпись/срок.
+// Отзыв и повторное использование проверяются атомарно в RotateRefresh.
 func (s *tokenService) ParseRefresh(tokenStr string) (*models.RefreshTokenClaims, error) {
 	claims := &models.RefreshTokenClaims{}
 	token, err := jwt.ParseWithClaims(tokenStr, claims, s.keyFunc,
 		jwt.WithIssuer(s.issuer),
-		jwt.WithValidMethods([]string{"HS256"}),
+		jwt.WithValidMethods(validMethods),
 		jwt.WithLeeway(5*time.Second),
 	)
 	if err != nil {
@@ -154,31 +318,81 @@ func (s *tokenService) ParseRefresh(tokenStr string) (*models.RefreshTokenClaims
 		return nil, fmt.Errorf("token is not valid")
 	}
 
-	// Проверка отзыва
-	revoked, err := s.IsRevoked(context.Background(), claims.JTI)
-	if err != nil {
-		s.logger.Errorw("Redis error during revocation check", "jti", claims.JTI, "error", err)
-		return nil, fmt.Errorf("revocation check failed: %w", err)
-	}
-	if revoked {
-		s.logger.Infow("Refresh token is revoked", "jti", claims.JTI, "user_id", claims.Sub)
-		return nil, fmt.Errorf("refresh token revoked")
-	}
-
 	s.logger.Debugw("Refresh token valid", "jti", claims.JTI, "user_id", claims.Sub)
 	return claims, nil
 }
 
-// RevokeRefresh — добавляет jti в blacklist
+// RevokeRefresh — добавляет jti в blacklist и удаляет сессию
 func (s *tokenService) RevokeRefresh(ctx context.Context, jti string) error {
-	if err := s.redis.Set(ctx, "revoked:"+jti, "1", 30*24*time.Hour).Err(); err != nil {
+	userID, err := s.redis.HGet(ctx, sessionKey(jti), "user_id").Result()
+	if err != nil && err != redis.Nil {
+		s.logger.Errorw("Failed to load session for revocation", "jti", jti, "error", err)
+		return err
+	}
+
+	pipe := s.redis.TxPipeline()
+	pipe.Set(ctx, "revoked:"+jti, "1", 30*24*time.Hour)
+	pipe.Del(ctx, "jti:"+jti, sessionKey(jti))
+	if userID != "" {
+		pipe.SRem(ctx, userJTIsKey(userID), jti)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
 		s.logger.Errorw("Failed to revoke token in Redis", "jti", jti, "error", err)
 		return err
 	}
+	s.revCache.setRevoked(jti, time.Now().Add(s.accessTTL))
 	s.logger.Infow("Token revoked successfully", "jti", jti)
 	return nil
 }
 
+// RevokeAllForUser — отзывает все выданные пользователю refresh токены
+func (s *tokenService) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	return s.revokeUserTokens(ctx, userID.String(), "")
+}
+
+// RevokeAllExcept — отзывает все токены пользователя, кроме текущей сессии
+func (s *tokenService) RevokeAllExcept(ctx context.Context, userID uuid.UUID, keepJTI string) error {
+	return s.revokeUserTokens(ctx, userID.String(), keepJTI)
+}
+
+// revokeUserTokens — отзыв по индексу user_jtis (keepJTI остаётся действительным)
+func (s *tokenService) revokeUserTokens(ctx context.Context, userID, keepJTI string) error {
+	jtis, err := s.redis.SMembers(ctx, userJTIsKey(userID)).Result()
+	if err != nil {
+		s.logger.Errorw("Failed to list user jtis", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to list user tokens: %w", err)
+	}
+
+	revoked := 0
+	pipe := s.redis.TxPipeline()
+	for _, jti := range jtis {
+		if jti == keepJTI {
+			continue
+		}
+		pipe.Set(ctx, "revoked:"+jti, "1", 30*24*time.Hour)
+		pipe.Del(ctx, "jti:"+jti, sessionKey(jti))
+		pipe.SRem(ctx, userJTIsKey(userID), jti)
+		revoked++
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Errorw("Failed to revoke user tokens", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	for _, jti := range jtis {
+		if jti != keepJTI {
+			s.revCache.setRevoked(jti, time.Now().Add(s.accessTTL))
+		}
+	}
+
+	s.logger.Infow("User tokens revoked", "user_id", userID, "count", revoked, "kept_jti", keepJTI)
+	return nil
+}
+
+// userJTIsKey — ключ множества jti, выданных пользователю
+func userJTIsKey(userID string) string {
+	return "user_jtis:" + userID
+}
+
 // IsRevoked — проверяет, отозван ли токен
 func (s *tokenService) IsRevoked(ctx context.Context, jti string) (bool, error) {
 	_, err := s.redis.Get(ctx, "revoked:"+jti).Result()
@@ -192,31 +406,86 @@ func (s *tokenService) IsRevoked(ctx context.Context, jti string) (bool, error)
 	return true, nil
 }
 
-// RotateRefresh — ротация: старый → отозван, новый → выдан
-func (s *tokenService) RotateRefresh(ctx context.Context, oldRefreshToken string, userID uuid.UUID, email, role string) (*models.TokenPair, error) {
+// RotateRefresh — ротация внутри семейства: старый → отозван, новый → выдан в рамках той же сессии.
+// Предъявление уже ротированного токена считается кражей: отзывается всё семейство.
+func (s *tokenService) RotateRefresh(ctx context.Context, oldRefreshToken string, userID uuid.UUID, email, role string, client models.ClientInfo) (*models.TokenPair, error) {
 	// 1. Парсим старый
 	oldClaims, err := s.ParseRefresh(oldRefreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	// 2. Отзываем старый
-	if err := s.RevokeRefresh(ctx, oldClaims.JTI); err != nil {
-		s.logger.Warnw("Failed to revoke old token during rotation", "old_jti", oldClaims.JTI, "error", err)
-		// Не фатально — продолжаем
+	// Токены, выданные до появления семейств, начинают семейство со своего jti
+	family := oldClaims.Family
+	legacy := family == ""
+	if legacy {
+		family = oldClaims.JTI
+	}
+
+	// 2. Атомарно переключаем семейство на новый jti
+	newJTI := uuid.New().String()
+	if err := s.advanceFamily(ctx, family, oldClaims.JTI, newJTI, legacy, userID); err != nil {
+		return nil, err
 	}
 
-	// 3. Генерируем новый
-	newPair, err := s.GeneratePair(ctx, userID, email, role)
+	// 3. Переносим метаданные сессии (время создания) на новый jti
+	now := time.Now()
+	session := models.Session{
+		UserAgent:     client.UserAgent,
+		IP:            client.IP,
+		CreatedAt:     now,
+		LastRefreshAt: now,
+	}
+	if prev, err := s.getSession(ctx, oldClaims.JTI); err != nil {
+		s.logger.Warnw("Failed to load session during rotation", "old_jti", oldClaims.JTI, "error", err)
+	} else if prev != nil {
+		session.CreatedAt = prev.CreatedAt
+	}
+
+	// 4. Генерируем и сохраняем новый. Старый пока валиден: при сбое семейство возвращается
+	// на него, и повтор запроса клиентом не считается повторным использованием
+	newPair, err := s.issuePair(ctx, userID, email, role, newJTI, family, session)
 	if err != nil {
+		s.rollbackFamily(ctx, family, oldClaims.JTI, newJTI)
 		return nil, err
 	}
 
+	// 5. Отзываем старый: без этого старый access токен остался бы валидным до exp.
+	// Старый refresh уже не пройдёт проверку семейства, поэтому сбой отзыва не отменяет выдачу.
+	if err := s.RevokeRefresh(ctx, oldClaims.JTI); err != nil {
+		s.logger.Errorw("Failed to revoke old token during rotation", "old_jti", oldClaims.JTI, "error", err)
+	}
+
 	s.logger.Infow("Token rotation successful",
 		"old_jti", oldClaims.JTI,
-		"new_jti", newPair.AccessToken[:16]+"...", // маскируем
+		"new_jti", newJTI,
+		"family", family,
 		"user_id", userID.String(),
 	)
 
 	return newPair, nil
 }
+
+// GenerateServiceToken — машинный токен сервисного клиента (client-credentials).
+// Токен не отзывается: он короткоживущий, а отключение клиента прекращает выдачу новых.
+// audience — сервис, который примет токен: другой сервис отклонит его по aud.
+func (s *tokenService) GenerateServiceToken(clientID, audience string, scopes []string) (*models.ServiceToken, error) {
+	scope := strings.Join(scopes, " ")
+	jti := uuid.New().String()
+
+	claims := models.NewServiceTokenClaims(clientID, audience, scope, jti, s.issuer, s.serviceTTL)
+	signed, err := s.signTyped(claims, ServiceTokenType)
+	if err != nil {
+		s.logger.Errorw("Failed to sign service token", "client_id", clientID, "error", err)
+		return nil, fmt.Errorf("sign service token: %w", err)
+	}
+
+	s.logger.Infow("Service token issued", "client_id", clientID, "audience", audience, "scope", scope, "jti", jti)
+
+	return &models.ServiceToken{
+		AccessToken: signed,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.serviceTTL.Seconds()),
+		Scope:       scope,
+	}, nil
+}
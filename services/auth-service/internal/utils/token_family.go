@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrRefreshTokenRevoked — токен отозван (logout) или семейство больше не существует
+	ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+	// ErrRefreshTokenReused — предъявлен уже ротированный токен, семейство отозвано
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
+// familyKey — текущий (единственный валидный) jti семейства refresh токенов
+func familyKey(family string) string {
+	return "family:" + family
+}
+
+// advanceFamilyScript — compare-and-swap текущего jti семейства.
+//
+//	KEYS[1] = family:<fam>, KEYS[2] = jti:<old>
+//	ARGV[1] = старый jti, ARGV[2] = новый jti, ARGV[3] = TTL (мс), ARGV[4] = "1" для токенов без семейства
+//
+// Возвращает {"ok"}, {"revoked"} или {"reused", <текущий jti>}.
+var advanceFamilyScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current == false then
+	if ARGV[4] == '1' and redis.call('EXISTS', KEYS[2]) == 1 then
+		redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+		return {'ok'}
+	end
+	return {'revoked'}
+end
+if current ~= ARGV[1] then
+	return {'reused', current}
+end
+if redis.call('EXISTS', KEYS[2]) == 0 then
+	return {'revoked'}
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return {'ok'}
+`)
+
+// rollbackFamilyScript — возврат семейства на старый jti, если новый так и не был выдан.
+//
+//	KEYS[1] = family:<fam>
+//	ARGV[1] = старый jti, ARGV[2] = новый jti, ARGV[3] = TTL (мс)
+//
+// Семейство меняется, только если оно всё ещё указывает на новый jti.
+var rollbackFamilyScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[2] then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+	return 1
+end
+return 0
+`)
+
+// advanceFamily — переводит семейство на новый jti или отзывает его при повторном использовании
+func (s *tokenService) advanceFamily(ctx context.Context, family, oldJTI, newJTI string, legacy bool, userID uuid.UUID) error {
+	legacyArg := "0"
+	if legacy {
+		legacyArg = "1"
+	}
+
+	res, err := advanceFamilyScript.Run(ctx, s.redis,
+		[]string{familyKey(family), "jti:" + oldJTI},
+		oldJTI, newJTI, s.refreshTTL.Milliseconds(), legacyArg,
+	).StringSlice()
+	if err != nil {
+		s.logger.Errorw("Failed to advance token family", "family", family, "error", err)
+		return fmt.Errorf("advance token family: %w", err)
+	}
+
+	switch res[0] {
+	case "ok":
+		return nil
+	case "reused":
+		s.logger.Warnw("SECURITY: refresh token reuse detected, revoking token family",
+			"security_event", "refresh_token_reuse",
+			"user_id", userID.String(),
+			"family", family,
+			"reused_jti", oldJTI,
+			"current_jti", res[1],
+		)
+		if err := s.revokeFamily(ctx, family, res[1]); err != nil {
+			return fmt.Errorf("revoke token family: %w", err)
+		}
+		return ErrRefreshTokenReused
+	default:
+		s.logger.Infow("Refresh token is revoked", "jti", oldJTI, "family", family, "user_id", userID.String())
+		return ErrRefreshTokenRevoked
+	}
+}
+
+// revokeFamily — отзывает текущий токен семейства и само семейство
+func (s *tokenService) revokeFamily(ctx context.Context, family, currentJTI string) error {
+	if err := s.RevokeRefresh(ctx, currentJTI); err != nil {
+		return err
+	}
+	if err := s.redis.Del(ctx, familyKey(family)).Err(); err != nil {
+		s.logger.Errorw("Failed to delete token family", "family", family, "error", err)
+		return err
+	}
+	return nil
+}
+
+// rollbackFamily — возвращает семейство на oldJTI после неудачной выдачи newJTI.
+// Ошибка только логируется: вызывающему важнее исходная причина сбоя ротации.
+func (s *tokenService) rollbackFamily(ctx context.Context, family, oldJTI, newJTI string) {
+	if err := rollbackFamilyScript.Run(ctx, s.redis,
+		[]string{familyKey(family)},
+		oldJTI, newJTI, s.refreshTTL.Milliseconds(),
+	).Err(); err != nil {
+		s.logger.Errorw("Failed to roll back token family", "family", family, "old_jti", oldJTI, "error", err)
+		return
+	}
+	s.logger.Infow("Token family rolled back after failed rotation", "family", family, "old_jti", oldJTI)
+}
@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/internal/models"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// newTestTokenService — TokenService поверх miniredis с одноразовым ключом подписи.
+// configure может поменять конфигурацию до создания сервиса.
+func newTestTokenService(t *testing.T, configure func(*TokenServiceConfig)) (*tokenService, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	dir := t.TempDir()
+	if _, err := GenerateSigningKey(dir, AlgEdDSA); err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+	keys, err := LoadKeyRing(dir, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatalf("load key ring: %v", err)
+	}
+
+	cfg := TokenServiceConfig{
+		KeyRing:    keys,
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: 24 * time.Hour,
+		ServiceTTL: 15 * time.Minute,
+		Redis:      rdb,
+		Logger:     zap.NewNop().Sugar(),
+	}
+	if configure != nil {
+		configure(&cfg)
+	}
+
+	svc, err := NewTokenService(cfg)
+	if err != nil {
+		t.Fatalf("new token service: %v", err)
+	}
+	return svc.(*tokenService), mr
+}
+
+func TestRotateRefresh_ReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	s, mr := newTestTokenService(t, nil)
+	userID := uuid.New()
+	client := models.ClientInfo{IP: "203.0.113.7", UserAgent: "test"}
+
+	first, err := s.GeneratePair(ctx, userID, "user@example.com", "user", client)
+	if err != nil {
+		t.Fatalf("generate pair: %v", err)
+	}
+	second, err := s.RotateRefresh(ctx, first.RefreshToken, userID, "user@example.com", "user", client)
+	if err != nil {
+		t.Fatalf("first rotation: %v", err)
+	}
+	claims, err := s.ParseRefresh(second.RefreshToken)
+	if err != nil {
+		t.Fatalf("parse rotated refresh token: %v", err)
+	}
+
+	// Повторное предъявление уже ротированного токена — кража
+	_, err = s.RotateRefresh(ctx, first.RefreshToken, userID, "user@example.com", "user", client)
+	if !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reusing rotated token: got %v, want ErrRefreshTokenReused", err)
+	}
+
+	// Семейство отозвано целиком: легитимный текущий токен тоже больше не работает
+	if mr.Exists(familyKey(claims.Family)) {
+		t.Errorf("family key %s still exists after reuse", familyKey(claims.Family))
+	}
+	if revoked, err := s.IsRevoked(ctx, second.JTI); err != nil || !revoked {
+		t.Errorf("current jti revoked = %v (err %v), want true", revoked, err)
+	}
+	if mr.Exists("jti:" + second.JTI) {
+		t.Errorf("current jti is still whitelisted")
+	}
+	_, err = s.RotateRefresh(ctx, second.RefreshToken, userID, "user@example.com", "user", client)
+	if !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Errorf("rotating current token after reuse: got %v, want ErrRefreshTokenRevoked", err)
+	}
+}
+
+// flakyPermissions — резолвер прав, падающий, пока выставлен err
+type flakyPermissions struct {
+	err error
+}
+
+func (f *flakyPermissions) PermissionsForRole(ctx context.Context, role string) ([]string, error) {
+	return nil, f.err
+}
+
+func TestRotateRefresh_FailedIssueKeepsOldToken(t *testing.T) {
+	ctx := context.Background()
+	perms := &flakyPermissions{}
+	s, mr := newTestTokenService(t, func(cfg *TokenServiceConfig) { cfg.Permissions = perms })
+	userID := uuid.New()
+	client := models.ClientInfo{IP: "203.0.113.7", UserAgent: "test"}
+
+	first, err := s.GeneratePair(ctx, userID, "user@example.com", "user", client)
+	if err != nil {
+		t.Fatalf("generate pair: %v", err)
+	}
+	claims, err := s.ParseRefresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("parse refresh token: %v", err)
+	}
+
+	perms.err = errors.New("postgres is down")
+	if _, err := s.RotateRefresh(ctx, first.RefreshToken, userID, "user@example.com", "user", client); err == nil {
+		t.Fatalf("rotation succeeded while issuing failed")
+	}
+	if got, _ := mr.Get(familyKey(claims.Family)); got != first.JTI {
+		t.Errorf("family points to %q after failed rotation, want old jti %q", got, first.JTI)
+	}
+	if revoked, err := s.IsRevoked(ctx, first.JTI); err != nil || revoked {
+		t.Errorf("old jti revoked = %v (err %v) after failed rotation, want false", revoked, err)
+	}
+
+	// Повтор клиента тем же токеном — не кража
+	perms.err = nil
+	second, err := s.RotateRefresh(ctx, first.RefreshToken, userID, "user@example.com", "user", client)
+	if err != nil {
+		t.Fatalf("retry after failed rotation: %v", err)
+	}
+	if got, _ := mr.Get(familyKey(claims.Family)); got != second.JTI {
+		t.Errorf("family points to %q, want new jti %q", got, second.JTI)
+	}
+	if revoked, err := s.IsRevoked(ctx, first.JTI); err != nil || !revoked {
+		t.Errorf("old jti revoked = %v (err %v) after rotation, want true", revoked, err)
+	}
+}
+
+func TestAdvanceFamily(t *testing.T) {
+	const (
+		family = "fam"
+		oldJTI = "jti-old"
+		newJTI = "jti-new"
+	)
+
+	tests := []struct {
+		name       string
+		setup      func(mr *miniredis.Miniredis)
+		legacy     bool
+		wantErr    error
+		wantFamily string // Ожидаемый jti семейства после вызова ("" — ключа нет)
+	}{
+		{
+			name: "current token advances family",
+			setup: func(mr *miniredis.Miniredis) {
+				mr.Set(familyKey(family), oldJTI)
+				mr.Set("jti:"+oldJTI, "user")
+			},
+			wantFamily: newJTI,
+		},
+		{
+			name: "rotated token revokes family",
+			setup: func(mr *miniredis.Miniredis) {
+				mr.Set(familyKey(family), "jti-current")
+				mr.Set("jti:jti-current", "user")
+			},
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name:    "missing family is revoked",
+			setup:   func(mr *miniredis.Miniredis) {},
+			wantErr: ErrRefreshTokenRevoked,
+		},
+		{
+			name: "logged out token is revoked",
+			setup: func(mr *miniredis.Miniredis) {
+				mr.Set(familyKey(family), oldJTI)
+			},
+			wantErr:    ErrRefreshTokenRevoked,
+			wantFamily: oldJTI,
+		},
+		{
+			name: "legacy token starts family",
+			setup: func(mr *miniredis.Miniredis) {
+				mr.Set("jti:"+oldJTI, "user")
+			},
+			legacy:     true,
+			wantFamily: newJTI,
+		},
+		{
+			name:    "revoked legacy token is not upgraded",
+			setup:   func(mr *miniredis.Miniredis) {},
+			legacy:  true,
+			wantErr: ErrRefreshTokenRevoked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mr := newTestTokenService(t, nil)
+			tt.setup(mr)
+
+			err := s.advanceFamily(context.Background(), family, oldJTI, newJTI, tt.legacy, uuid.New())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("advanceFamily() error = %v, want %v", err, tt.wantErr)
+			}
+
+			got, _ := mr.Get(familyKey(family))
+			if got != tt.wantFamily {
+				t.Errorf("family jti = %q, want %q", got, tt.wantFamily)
+			}
+		})
+	}
+}
+
+func TestGenerateServiceToken_Audience(t *testing.T) {
+	s, _ := newTestTokenService(t, nil)
+
+	token, err := s.GenerateServiceToken("billing", "event-service", []string{"events:read"})
+	if err != nil {
+		t.Fatalf("generate service token: %v", err)
+	}
+
+	claims := &models.ServiceTokenClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token.AccessToken, claims); err != nil {
+		t.Fatalf("parse service token: %v", err)
+	}
+	if got := []string(claims.Audience); len(got) != 1 || got[0] != "event-service" {
+		t.Errorf("aud = %v, want [event-service]", got)
+	}
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+type ttlCacheEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// TTLCache — локальный потокобезопасный кэш с истечением записей и жёстким лимитом размера.
+// Когда кэш заполнен, сначала удаляются истёкшие записи; если их не хватило — случайные
+// живые, пока не освободится десятая часть лимита. Промах кэша лишь ведёт к обращению
+// к источнику, поэтому вытеснение живых записей безопасно, а память ограничена при любом
+// потоке уникальных ключей. ttl <= 0 отключает кэш.
+type TTLCache[K comparable, V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[K]ttlCacheEntry[V]
+}
+
+// NewTTLCache — конструктор; maxEntries — предельное число записей
+func NewTTLCache[K comparable, V any](ttl time.Duration, maxEntries int) *TTLCache[K, V] {
+	return &TTLCache[K, V]{
+		ttl:        ttl,
+		maxEntries: max(maxEntries, 1),
+		entries:    make(map[K]ttlCacheEntry[V]),
+	}
+}
+
+// Enabled — кэш включён (ttl > 0)
+func (c *TTLCache[K, V]) Enabled() bool {
+	return c.ttl > 0
+}
+
+// Get — закэшированное значение (ok=false, если записи нет или она истекла)
+func (c *TTLCache[K, V]) Get(key K) (value V, ok bool) {
+	if !c.Enabled() {
+		return value, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, found := c.entries[key]
+	if !found {
+		return value, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return value, false
+	}
+	return entry.value, true
+}
+
+// Set — запись живёт ttl
+func (c *TTLCache[K, V]) Set(key K, value V) {
+	c.SetUntil(key, value, time.Now().Add(c.ttl))
+}
+
+// SetUntil — запись живёт до until (нулевое until — ttl)
+func (c *TTLCache[K, V]) SetUntil(key K, value V, until time.Time) {
+	if !c.Enabled() {
+		return
+	}
+	if until.IsZero() {
+		until = time.Now().Add(c.ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, found := c.entries[key]; !found && len(c.entries) >= c.maxEntries {
+		c.evict()
+	}
+	c.entries[key] = ttlCacheEntry[V]{value: value, expiresAt: until}
+}
+
+// evict — освобождает место под новые записи; вызывается под c.mu
+func (c *TTLCache[K, V]) evict() {
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+
+	// Запас в десятую часть лимита, чтобы не обходить карту на каждой вставке.
+	// Порядок обхода map в Go случаен — это и есть выбор случайных жертв.
+	target := c.maxEntries - max(c.maxEntries/10, 1)
+	for k := range c.entries {
+		if len(c.entries) <= target {
+			break
+		}
+		delete(c.entries, k)
+	}
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTTLCache(t *testing.T) {
+	t.Run("disabled cache stores nothing", func(t *testing.T) {
+		c := NewTTLCache[string, int](0, 10)
+		c.Set("key", 1)
+		if _, ok := c.Get("key"); ok {
+			t.Fatalf("disabled cache returned an entry")
+		}
+	})
+
+	t.Run("zero until falls back to ttl", func(t *testing.T) {
+		c := NewTTLCache[string, int](time.Minute, 10)
+		c.SetUntil("key", 1, time.Time{})
+		if got := c.entries["key"].expiresAt; got.Before(time.Now().Add(59 * time.Second)) {
+			t.Errorf("expiresAt = %v, want about now+ttl", got)
+		}
+	})
+
+	t.Run("expired entry is removed on get", func(t *testing.T) {
+		c := NewTTLCache[string, int](time.Minute, 10)
+		c.SetUntil("key", 1, time.Now().Add(-time.Second))
+		if _, ok := c.Get("key"); ok {
+			t.Fatalf("expired entry returned")
+		}
+		if _, found := c.entries["key"]; found {
+			t.Errorf("expired entry was not deleted")
+		}
+	})
+
+	t.Run("expired entries are evicted first", func(t *testing.T) {
+		const limit = 100
+		c := NewTTLCache[string, int](time.Minute, limit)
+		c.Set("live", 1)
+		for i := range limit - 1 {
+			c.SetUntil("expired-"+strconv.Itoa(i), i, time.Now().Add(-time.Second))
+		}
+		c.Set("new", 2)
+
+		if len(c.entries) != 2 {
+			t.Fatalf("entries after eviction = %d, want 2", len(c.entries))
+		}
+		if _, ok := c.Get("live"); !ok {
+			t.Errorf("live entry was evicted while expired ones were available")
+		}
+	})
+
+	t.Run("size never exceeds the limit", func(t *testing.T) {
+		const limit = 100
+		c := NewTTLCache[string, int](time.Hour, limit)
+		for i := range 10 * limit {
+			c.Set("key-"+strconv.Itoa(i), i)
+			if len(c.entries) > limit {
+				t.Fatalf("entries = %d after %d inserts, limit %d", len(c.entries), i+1, limit)
+			}
+		}
+		if _, ok := c.Get("key-" + strconv.Itoa(10*limit-1)); !ok {
+			t.Errorf("latest entry was evicted")
+		}
+	})
+
+	t.Run("overwriting a key at the limit evicts nothing", func(t *testing.T) {
+		const limit = 10
+		c := NewTTLCache[string, int](time.Hour, limit)
+		for i := range limit {
+			c.Set("key-"+strconv.Itoa(i), i)
+		}
+		c.Set("key-0", 42)
+
+		if len(c.entries) != limit {
+			t.Fatalf("entries = %d, want %d", len(c.entries), limit)
+		}
+		if got, _ := c.Get("key-0"); got != 42 {
+			t.Errorf("Get() = %d, want 42", got)
+		}
+	})
+}
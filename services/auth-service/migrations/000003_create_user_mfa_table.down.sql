@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS set_updated_at ON auth.user_mfa;
+DROP TABLE IF EXISTS auth.user_mfa;
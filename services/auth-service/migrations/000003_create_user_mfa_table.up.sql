@@ -0,0 +1,15 @@
+CREATE TABLE auth.user_mfa (
+    user_id              UUID PRIMARY KEY REFERENCES auth.users(id) ON DELETE CASCADE,
+    secret_encrypted     TEXT NOT NULL,                    -- AES-256-GCM, base64(nonce || ciphertext)
+    enabled              BOOLEAN NOT NULL DEFAULT FALSE,
+    enabled_at           TIMESTAMPTZ,
+    recovery_codes       TEXT[] NOT NULL DEFAULT '{}',     -- SHA-256 хэши неиспользованных кодов
+    last_used_step       BIGINT NOT NULL DEFAULT 0,        -- защита от повторного использования TOTP кода
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER set_updated_at
+    BEFORE UPDATE ON auth.user_mfa
+    FOR EACH ROW
+    EXECUTE FUNCTION auth.trigger_set_updated_at();
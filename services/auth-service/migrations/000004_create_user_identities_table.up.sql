@@ -0,0 +1,12 @@
+CREATE TABLE auth.user_identities (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id        UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    provider       VARCHAR(50) NOT NULL,      -- google, yandex, vk, mock
+    subject        VARCHAR(255) NOT NULL,     -- ID пользователя у провайдера
+    email          VARCHAR(255),
+    last_login_at  TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON auth.user_identities(user_id);
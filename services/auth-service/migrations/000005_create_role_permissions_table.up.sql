@@ -0,0 +1,17 @@
+-- Права ролей: попадают в access токен (claim perms) и в заголовок X-User-Permissions
+CREATE TABLE auth.role_permissions (
+    role        VARCHAR(20) NOT NULL,      -- user, admin
+    permission  VARCHAR(100) NOT NULL,     -- <ресурс>:<действие>, например events:moderate
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO auth.role_permissions (role, permission) VALUES
+('user',  'profiles:read'),
+('admin', 'profiles:read'),
+('admin', 'events:moderate'),
+('admin', 'categories:write'),
+('admin', 'users:read'),
+('admin', 'users:ban'),
+('admin', 'users:manage-roles')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS auth.account_deletion_acks;
+DROP TABLE IF EXISTS auth.account_deletions;
+
+UPDATE auth.users SET status = 'blocked' WHERE status = 'deleted';
+ALTER TABLE auth.users DROP CONSTRAINT users_status_check;
+ALTER TABLE auth.users ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'blocked', 'banned'));
+
+ALTER TABLE auth.users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,23 @@
+-- Мягкое удаление: пользователь помечается deleted, строка удаляется после подтверждения всех сервисов
+ALTER TABLE auth.users ADD COLUMN deleted_at TIMESTAMPTZ;
+
+ALTER TABLE auth.users DROP CONSTRAINT users_status_check;
+ALTER TABLE auth.users ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'blocked', 'banned', 'deleted'));
+
+-- Сага удаления аккаунта. Без FK на users: запись переживает окончательное удаление пользователя
+CREATE TABLE auth.account_deletions (
+    user_id          UUID PRIMARY KEY,
+    requested_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_emitted_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),  -- Когда UserDeleted последний раз записан в outbox
+    completed_at     TIMESTAMPTZ                          -- Все сервисы подтвердили, пользователь удалён
+);
+
+CREATE INDEX idx_account_deletions_pending ON auth.account_deletions(last_emitted_at) WHERE completed_at IS NULL;
+
+-- Подтверждения сервисов (profile-service, event-service, ...)
+CREATE TABLE auth.account_deletion_acks (
+    user_id   UUID NOT NULL REFERENCES auth.account_deletions(user_id) ON DELETE CASCADE,
+    service   VARCHAR(100) NOT NULL,
+    acked_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, service)
+);
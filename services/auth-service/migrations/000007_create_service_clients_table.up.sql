@@ -0,0 +1,10 @@
+-- Сервисные клиенты: получают машинные токены через client-credentials (POST /api/v1/auth/token)
+CREATE TABLE auth.service_clients (
+    client_id    VARCHAR(100) PRIMARY KEY,        -- например event-service
+    name         VARCHAR(255) NOT NULL,
+    secret_hash  TEXT NOT NULL,                   -- argon2id, как у паролей
+    scopes       TEXT[] NOT NULL DEFAULT '{}',    -- <ресурс>:<действие>, те же строки, что и права ролей
+    disabled     BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DELETE FROM auth.role_permissions WHERE permission = 'audit:read';
+DROP TABLE IF EXISTS auth.audit_log;
@@ -0,0 +1,24 @@
+-- Журнал аутентификации: кто, когда и откуда входил, что менял.
+-- Без внешнего ключа на users: история остаётся и после удаления аккаунта.
+CREATE TABLE auth.audit_log (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     UUID,                          -- NULL, если email не зарегистрирован (неудачный вход)
+    email       VARCHAR(255),
+    event_type  VARCHAR(50) NOT NULL,          -- register, login_success, login_failure, token_refresh, ...
+    success     BOOLEAN NOT NULL DEFAULT TRUE,
+    ip          VARCHAR(64),
+    user_agent  TEXT,
+    request_id  VARCHAR(64),
+    jti         VARCHAR(64),
+    metadata    JSONB,                         -- Подробности: причина отказа, кто изменил статус и т.п.
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_user_created  ON auth.audit_log(user_id, created_at DESC);
+CREATE INDEX idx_audit_log_type_created  ON auth.audit_log(event_type, created_at DESC);
+CREATE INDEX idx_audit_log_ip            ON auth.audit_log(ip);
+CREATE INDEX idx_audit_log_created       ON auth.audit_log(created_at DESC);
+
+INSERT INTO auth.role_permissions (role, permission) VALUES
+('admin', 'audit:read')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+ALTER TABLE auth.users DROP CONSTRAINT IF EXISTS users_phone_key;
+ALTER TABLE auth.users DROP COLUMN IF EXISTS phone_verified;
+ALTER TABLE auth.users DROP COLUMN IF EXISTS phone;
@@ -0,0 +1,5 @@
+-- Телефон в формате E.164. Подтверждается кодом из SMS; для входа по OTP нужен подтверждённый номер
+ALTER TABLE auth.users ADD COLUMN phone VARCHAR(16);
+ALTER TABLE auth.users ADD COLUMN phone_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE auth.users ADD CONSTRAINT users_phone_key UNIQUE (phone);
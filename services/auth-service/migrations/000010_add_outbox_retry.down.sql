@@ -0,0 +1,10 @@
+DELETE FROM auth.role_permissions WHERE permission = 'outbox:manage';
+
+DROP INDEX IF EXISTS auth.idx_outbox_failed;
+DROP INDEX IF EXISTS auth.idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON auth.outbox(status, published_at) WHERE status = 'pending';
+
+ALTER TABLE auth.outbox
+    DROP COLUMN IF EXISTS failed_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS next_attempt_at;
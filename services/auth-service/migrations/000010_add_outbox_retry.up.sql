@@ -0,0 +1,14 @@
+-- Повторы публикации с экспоненциальной задержкой: событие берётся в работу не раньше next_attempt_at.
+-- После OUTBOX_MAX_ATTEMPTS неудач событие получает status = 'failed' и ждёт ручного requeue.
+ALTER TABLE auth.outbox
+    ADD COLUMN next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN last_error      TEXT,
+    ADD COLUMN failed_at       TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS auth.idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON auth.outbox(next_attempt_at, created_at) WHERE status = 'pending';
+CREATE INDEX idx_outbox_failed  ON auth.outbox(failed_at DESC) WHERE status = 'failed';
+
+INSERT INTO auth.role_permissions (role, permission) VALUES
+('admin', 'outbox:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS auth.idx_outbox_pending_aggregate;
+DROP INDEX IF EXISTS auth.idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON auth.outbox(next_attempt_at, created_at) WHERE status = 'pending';
+
+ALTER TABLE auth.outbox
+    DROP COLUMN IF EXISTS request_id,
+    DROP COLUMN IF EXISTS schema_version,
+    DROP COLUMN IF EXISTS seq,
+    DROP COLUMN IF EXISTS aggregate_id;
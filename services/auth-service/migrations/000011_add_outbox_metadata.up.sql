@@ -0,0 +1,18 @@
+-- aggregate_id — ключ Kafka-сообщения: события одного пользователя попадают в одну партицию.
+-- seq — порядок вставки; события одного агрегата публикуются строго по нему
+-- (NOW() одинаков для всех событий одной транзакции и порядок не задаёт).
+-- schema_version и request_id уходят в заголовки сообщения.
+ALTER TABLE auth.outbox
+    ADD COLUMN aggregate_id   UUID,
+    ADD COLUMN seq            BIGINT GENERATED ALWAYS AS IDENTITY,
+    ADD COLUMN schema_version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN request_id     TEXT;
+
+-- Все события auth-service — события пользователя с user_id в payload
+UPDATE auth.outbox SET aggregate_id = COALESCE((payload->>'user_id')::uuid, id);
+
+ALTER TABLE auth.outbox ALTER COLUMN aggregate_id SET NOT NULL;
+
+DROP INDEX IF EXISTS auth.idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON auth.outbox(seq) WHERE status = 'pending';
+CREATE INDEX idx_outbox_pending_aggregate ON auth.outbox(aggregate_id, seq) WHERE status = 'pending';
@@ -0,0 +1 @@
+ALTER TABLE auth.outbox DROP COLUMN IF EXISTS traceparent;
@@ -0,0 +1,3 @@
+-- traceparent (W3C Trace Context) запроса, породившего событие, уходит в заголовок сообщения
+-- рядом с request_id: потребитель продолжает ту же трассу.
+ALTER TABLE auth.outbox ADD COLUMN traceparent TEXT;
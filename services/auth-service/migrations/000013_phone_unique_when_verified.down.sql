@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS auth.idx_users_phone;
+DROP INDEX IF EXISTS auth.users_phone_verified_key;
+
+-- Неподтверждённые дубли номера снимаются, иначе ограничение не создать
+UPDATE auth.users u SET phone = NULL
+WHERE NOT u.phone_verified
+  AND EXISTS (
+      SELECT 1 FROM auth.users o
+      WHERE o.phone = u.phone AND o.id <> u.id AND (o.phone_verified OR o.id < u.id)
+  );
+
+ALTER TABLE auth.users ADD CONSTRAINT users_phone_key UNIQUE (phone);
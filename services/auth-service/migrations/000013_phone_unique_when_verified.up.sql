@@ -0,0 +1,6 @@
+-- Номер закрепляется за аккаунтом только после подтверждения кодом из SMS:
+-- неподтверждённый номер может указать кто угодно, и он не мешает настоящему владельцу.
+ALTER TABLE auth.users DROP CONSTRAINT IF EXISTS users_phone_key;
+
+CREATE UNIQUE INDEX users_phone_verified_key ON auth.users(phone) WHERE phone_verified;
+CREATE INDEX idx_users_phone ON auth.users(phone);
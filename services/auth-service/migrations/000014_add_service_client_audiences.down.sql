@@ -0,0 +1 @@
+ALTER TABLE auth.service_clients DROP COLUMN IF EXISTS audiences;
@@ -0,0 +1,13 @@
+-- Сервисы, для которых клиент может получить машинный токен (claim aud).
+-- Токен выдаётся для одного сервиса, и тот принимает только токены со своим aud.
+ALTER TABLE auth.service_clients ADD COLUMN audiences TEXT[] NOT NULL DEFAULT '{}';
+
+-- Существующим клиентам — сервисы, к ресурсам которых относятся их scopes
+UPDATE auth.service_clients SET audiences = ARRAY(
+    SELECT DISTINCT CASE split_part(scope, ':', 1)
+        WHEN 'events' THEN 'event-service'
+        WHEN 'profiles' THEN 'profile-service'
+    END
+    FROM unnest(scopes) AS scope
+    WHERE split_part(scope, ':', 1) IN ('events', 'profiles')
+);
@@ -2,11 +2,14 @@ package postgres
 
 import (
 	"auth-service/internal/config"
+	"auth-service/pkg/apperror"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sony/gobreaker"
 	"go.uber.org/zap"
@@ -84,7 +87,7 @@ func (db *DB) Ping(ctx context.Context) error {
 	_, err := db.cb.Execute(func() (interface{}, error) {
 		return nil, db.Pool.Ping(ctx)
 	})
-	return err
+	return unavailableError(err)
 }
 
 func (db *DB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
@@ -94,7 +97,7 @@ func (db *DB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx
 
 	if err != nil {
 		db.logger.Errorf("Circuit Breaker rejected QueryRow: %v", err)
-		return &errorRow{err: err}
+		return &errorRow{err: unavailableError(err)}
 	}
 
 	return result.(pgx.Row)
@@ -106,6 +109,16 @@ func (db *DB) Exec(ctx context.Context, sql string, args ...interface{}) error {
 		return nil, err
 	})
 
+	return unavailableError(err)
+}
+
+// unavailableError — отказ circuit breaker и ошибки подключения означают, что БД недоступна (503).
+// Остальные ошибки возвращаются как есть.
+func unavailableError(err error) error {
+	var connErr *pgconn.ConnectError
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) || errors.As(err, &connErr) {
+		return apperror.Unavailable("database is unavailable", err)
+	}
 	return err
 }
 
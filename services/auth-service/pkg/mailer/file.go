@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"auth-service/internal/config"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fileMailer — заглушка для локальной разработки: пишет письма в файл и в лог
+type fileMailer struct {
+	path   string
+	mu     sync.Mutex
+	logger *zap.SugaredLogger
+}
+
+func newFileMailer(cfg *config.MailerConfig, logger *zap.SugaredLogger) *fileMailer {
+	return &fileMailer{
+		path:   cfg.FilePath,
+		logger: logger,
+	}
+}
+
+// Send — дописывает письмо в файл (если путь задан) и логирует его
+func (m *fileMailer) Send(ctx context.Context, msg Message) error {
+	m.logger.Infow("Email captured by file mailer",
+		"to", msg.To,
+		"subject", msg.Subject,
+		"body", msg.Body,
+	)
+
+	if m.path == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		m.logger.Errorw("Failed to open mail file", "path", m.path, "error", err)
+		return fmt.Errorf("open mail file: %w", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "=== %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+	if err != nil {
+		return fmt.Errorf("write mail file: %w", err)
+	}
+	return nil
+}
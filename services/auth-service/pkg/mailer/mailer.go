@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"auth-service/internal/config"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Message — письмо для отправки
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer — абстракция отправки писем (SMTP в проде, файл для локальной разработки)
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New — создаёт Mailer по драйверу из конфигурации
+func New(cfg *config.MailerConfig, logger *zap.SugaredLogger) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return newSMTPMailer(cfg, logger)
+	case "file":
+		return newFileMailer(cfg, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver: %s", cfg.Driver)
+	}
+}
@@ -0,0 +1,190 @@
+package oauth
+
+import (
+	"auth-service/internal/config"
+	"auth-service/pkg/apperror"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrUnknownProvider — провайдер не настроен
+	ErrUnknownProvider = apperror.NotFound("unknown oauth provider")
+	// ErrInvalidState — state не найден, истёк, уже использован или выдан другому провайдеру
+	ErrInvalidState = apperror.Validation("invalid or expired oauth state")
+)
+
+// Authorization — начало входа через провайдера.
+// StateBinding кладётся в cookie браузера, начавшего вход, и сверяется в Complete:
+// без неё чужой callback URL залогинил бы жертву в аккаунт атакующего (login CSRF).
+type Authorization struct {
+	URL          string
+	StateBinding string
+	TTL          time.Duration
+}
+
+// pendingAuth — данные между start и callback (хранятся в Redis по state)
+type pendingAuth struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+// Manager — реестр провайдеров + state/PKCE для authorization code flow
+type Manager struct {
+	providers map[string]IdentityProvider
+	redis     *redis.Client
+	stateTTL  time.Duration
+	logger    *zap.SugaredLogger
+}
+
+// New — регистрирует провайдеров, для которых задан client id (и mock, если включён)
+func New(cfg *config.OAuthConfig, redis *redis.Client, logger *zap.SugaredLogger) (*Manager, error) {
+	m := &Manager{
+		providers: make(map[string]IdentityProvider),
+		redis:     redis,
+		stateTTL:  cfg.StateTTL,
+		logger:    logger,
+	}
+
+	base := strings.TrimRight(cfg.RedirectBaseURL, "/")
+	redirectURL := func(name string) string {
+		return fmt.Sprintf("%s/api/v1/auth/oauth/%s/callback", base, name)
+	}
+
+	if cfg.Google.ClientID != "" {
+		m.register(newGoogleProvider(cfg.Google.ClientID, cfg.Google.ClientSecret, redirectURL("google")))
+	}
+	if cfg.Yandex.ClientID != "" {
+		m.register(newYandexProvider(cfg.Yandex.ClientID, cfg.Yandex.ClientSecret, redirectURL("yandex")))
+	}
+	if cfg.VK.ClientID != "" {
+		m.register(newVKProvider(cfg.VK.ClientID, cfg.VK.ClientSecret, redirectURL("vk")))
+	}
+	if cfg.MockEnabled {
+		internalURL := cfg.MockInternalURL
+		if internalURL == "" {
+			internalURL = cfg.MockPublicURL
+		}
+		m.register(newMockProvider(strings.TrimRight(cfg.MockPublicURL, "/"), strings.TrimRight(internalURL, "/"), redirectURL("mock")))
+	}
+
+	logger.Infow("OAuth providers configured", "providers", m.Providers())
+	return m, nil
+}
+
+func (m *Manager) register(p IdentityProvider) {
+	m.providers[p.Name()] = p
+}
+
+// Providers — имена настроенных провайдеров
+func (m *Manager) Providers() []string {
+	names := make([]string, 0, len(m.providers))
+	for name := range m.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start — создаёт state и PKCE verifier, возвращает URL авторизации у провайдера
+// и привязку state к браузеру
+func (m *Manager) Start(ctx context.Context, provider string) (*Authorization, error) {
+	p, ok := m.providers[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	state, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(pendingAuth{Provider: provider, CodeVerifier: verifier})
+	if err != nil {
+		return nil, fmt.Errorf("marshal oauth state: %w", err)
+	}
+	if err := m.redis.Set(ctx, stateKey(state), data, m.stateTTL).Err(); err != nil {
+		m.logger.Errorw("Failed to store OAuth state", "provider", provider, "error", err)
+		return nil, fmt.Errorf("store oauth state: %w", err)
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	return &Authorization{
+		URL:          p.AuthCodeURL(state, base64.RawURLEncoding.EncodeToString(challenge[:])),
+		StateBinding: stateBinding(state),
+		TTL:          m.stateTTL,
+	}, nil
+}
+
+// Complete — проверяет state (одноразово) и его привязку к браузеру,
+// обменивает code и возвращает профиль пользователя
+func (m *Manager) Complete(ctx context.Context, provider, state, binding, code string) (*ExternalUser, error) {
+	p, ok := m.providers[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	if state == "" || code == "" {
+		return nil, ErrInvalidState
+	}
+	// Сверяем до GetDel: запрос из чужого браузера не расходует state
+	if subtle.ConstantTimeCompare([]byte(stateBinding(state)), []byte(binding)) != 1 {
+		m.logger.Warnw("OAuth state is not bound to this browser", "provider", provider)
+		return nil, ErrInvalidState
+	}
+
+	raw, err := m.redis.GetDel(ctx, stateKey(state)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrInvalidState
+	}
+	if err != nil {
+		m.logger.Errorw("Failed to load OAuth state", "provider", provider, "error", err)
+		return nil, fmt.Errorf("load oauth state: %w", err)
+	}
+
+	var pending pendingAuth
+	if err := json.Unmarshal(raw, &pending); err != nil {
+		return nil, fmt.Errorf("decode oauth state: %w", err)
+	}
+	if pending.Provider != provider {
+		return nil, ErrInvalidState
+	}
+
+	token, err := p.Exchange(ctx, code, pending.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	return p.UserInfo(ctx, token)
+}
+
+// stateBinding — хэш state для cookie: сам state в cookie не хранится
+func stateBinding(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func stateKey(state string) string {
+	return "oauth_state:" + state
+}
+
+func randomString(nBytes int) (string, error) {
+	b := make([]byte, nBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate random string: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,125 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// fakeProvider — провайдер без сети: code принимается любой, verifier запоминается
+type fakeProvider struct {
+	verifier string
+}
+
+func (p *fakeProvider) Name() string { return "fake" }
+
+func (p *fakeProvider) AuthCodeURL(state, codeChallenge string) string {
+	return "https://idp.example/auth?" + url.Values{"state": {state}, "code_challenge": {codeChallenge}}.Encode()
+}
+
+func (p *fakeProvider) Exchange(_ context.Context, _, codeVerifier string) (*Token, error) {
+	p.verifier = codeVerifier
+	return &Token{}, nil
+}
+
+func (p *fakeProvider) UserInfo(context.Context, *Token) (*ExternalUser, error) {
+	return &ExternalUser{Provider: "fake", Subject: "42"}, nil
+}
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	m := &Manager{
+		providers: make(map[string]IdentityProvider),
+		redis:     rdb,
+		stateTTL:  time.Minute,
+		logger:    zap.NewNop().Sugar(),
+	}
+	m.register(&fakeProvider{})
+	return m
+}
+
+// startState — state из URL авторизации, как его вернёт провайдер в callback
+func startState(t *testing.T, authorization *Authorization) string {
+	t.Helper()
+	u, err := url.Parse(authorization.URL)
+	if err != nil {
+		t.Fatalf("parse auth url: %v", err)
+	}
+	return u.Query().Get("state")
+}
+
+func TestComplete_StateBinding(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		binding func(own *Authorization, other *Authorization) string
+		wantErr error
+	}{
+		{name: "same browser", binding: func(own, _ *Authorization) string { return own.StateBinding }},
+		{name: "no cookie", binding: func(_, _ *Authorization) string { return "" }, wantErr: ErrInvalidState},
+		// Атакующий начал вход сам и подсунул жертве свой callback: у жертвы cookie от другого входа
+		{name: "cookie from another flow", binding: func(_, other *Authorization) string { return other.StateBinding }, wantErr: ErrInvalidState},
+		{name: "raw state instead of hash", binding: func(own, _ *Authorization) string { return startState(t, own) }, wantErr: ErrInvalidState},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+
+			own, err := m.Start(ctx, "fake")
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			other, err := m.Start(ctx, "fake")
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			if own.TTL != time.Minute {
+				t.Errorf("TTL = %v, want %v", own.TTL, time.Minute)
+			}
+
+			state := startState(t, own)
+			_, err = m.Complete(ctx, "fake", state, tt.binding(own, other), "code")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Complete() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// Отклонённый запрос не расходует state: владелец ещё может завершить вход
+			if tt.wantErr != nil {
+				if _, err := m.Complete(ctx, "fake", state, own.StateBinding, "code"); err != nil {
+					t.Errorf("Complete() after rejected attempt error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestComplete_StateIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t)
+
+	authorization, err := m.Start(ctx, "fake")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	state := startState(t, authorization)
+
+	if _, err := m.Complete(ctx, "fake", state, authorization.StateBinding, "code"); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if _, err := m.Complete(ctx, "fake", state, authorization.StateBinding, "code"); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("second Complete() error = %v, want %v", err, ErrInvalidState)
+	}
+}
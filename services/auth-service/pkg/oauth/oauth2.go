@@ -0,0 +1,197 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// endpointConfig — параметры типового OAuth2 провайдера
+type endpointConfig struct {
+	name         string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	authURL      string
+	tokenURL     string
+	userInfoURL  string
+	scopes       []string
+	// authScheme — схема заголовка Authorization для userinfo (Bearer, у Яндекса — OAuth)
+	authScheme string
+	// userInfoForm — userinfo запрашивается POST формой с access_token (VK ID)
+	userInfoForm bool
+	// mapUser — разбор ответа userinfo конкретного провайдера
+	mapUser func(data map[string]any) (*ExternalUser, error)
+}
+
+// oauth2Provider — реализация IdentityProvider поверх стандартного authorization code flow
+type oauth2Provider struct {
+	cfg    endpointConfig
+	client *http.Client
+}
+
+func newOAuth2Provider(cfg endpointConfig) IdentityProvider {
+	if cfg.authScheme == "" {
+		cfg.authScheme = "Bearer"
+	}
+	return &oauth2Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *oauth2Provider) Name() string {
+	return p.cfg.name
+}
+
+func (p *oauth2Provider) AuthCodeURL(state, codeChallenge string) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.cfg.clientID)
+	params.Set("redirect_uri", p.cfg.redirectURL)
+	params.Set("state", state)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+	if len(p.cfg.scopes) > 0 {
+		params.Set("scope", strings.Join(p.cfg.scopes, " "))
+	}
+
+	sep := "?"
+	if strings.Contains(p.cfg.authURL, "?") {
+		sep = "&"
+	}
+	return p.cfg.authURL + sep + params.Encode()
+}
+
+func (p *oauth2Provider) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.redirectURL)
+	form.Set("client_id", p.cfg.clientID)
+	form.Set("code_verifier", codeVerifier)
+	if p.cfg.clientSecret != "" {
+		form.Set("client_secret", p.cfg.clientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	data, err := p.doJSON(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s token exchange: %w", p.cfg.name, err)
+	}
+
+	token := &Token{
+		AccessToken: stringField(data, "access_token"),
+		TokenType:   stringField(data, "token_type"),
+		IDToken:     stringField(data, "id_token"),
+		Raw:         data,
+	}
+	if expires, ok := data["expires_in"].(float64); ok {
+		token.ExpiresIn = time.Duration(expires) * time.Second
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("%s token exchange: empty access token", p.cfg.name)
+	}
+	return token, nil
+}
+
+func (p *oauth2Provider) UserInfo(ctx context.Context, token *Token) (*ExternalUser, error) {
+	var (
+		req *http.Request
+		err error
+	)
+	if p.cfg.userInfoForm {
+		form := url.Values{}
+		form.Set("access_token", token.AccessToken)
+		form.Set("client_id", p.cfg.clientID)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.userInfoURL, strings.NewReader(form.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.userInfoURL, nil)
+		if err == nil {
+			req.Header.Set("Authorization", p.cfg.authScheme+" "+token.AccessToken)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("build userinfo request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	data, err := p.doJSON(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s userinfo: %w", p.cfg.name, err)
+	}
+
+	user, err := p.cfg.mapUser(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s userinfo: %w", p.cfg.name, err)
+	}
+	if user.Subject == "" {
+		return nil, fmt.Errorf("%s userinfo: missing subject", p.cfg.name)
+	}
+	user.Provider = p.cfg.name
+	return user, nil
+}
+
+// doJSON — выполняет запрос и разбирает JSON-ответ; ошибки провайдера возвращаются с телом
+func (p *oauth2Provider) doJSON(req *http.Request) (map[string]any, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if errCode := stringField(data, "error"); errCode != "" {
+		return nil, fmt.Errorf("provider error: %s %s", errCode, stringField(data, "error_description"))
+	}
+	return data, nil
+}
+
+// stringField — строковое поле JSON (числовые ID приводятся к строке)
+func stringField(data map[string]any, key string) string {
+	switch v := data[key].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	case json.Number:
+		return v.String()
+	}
+	return ""
+}
+
+// boolField — bool или строка "true"
+func boolField(data map[string]any, key string) bool {
+	switch v := data[key].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package oauth
+
+import (
+	"context"
+	"time"
+)
+
+// ExternalUser — пользователь внешнего провайдера, приведённый к общему виду
+type ExternalUser struct {
+	Provider      string
+	Subject       string // Стабильный ID пользователя у провайдера (sub)
+	Email         string
+	EmailVerified bool // Провайдер подтвердил владение email — только тогда аккаунты связываются по email
+	FirstName     string
+	LastName      string
+}
+
+// Token — ответ token endpoint
+type Token struct {
+	AccessToken string
+	TokenType   string
+	IDToken     string
+	ExpiresIn   time.Duration
+	Raw         map[string]any
+}
+
+// IdentityProvider — внешний OAuth2/OIDC провайдер (Google, Yandex, VK, mock)
+type IdentityProvider interface {
+	// Name — имя провайдера в URL: /auth/oauth/:provider/...
+	Name() string
+	// AuthCodeURL — куда отправить браузер для входа (state + PKCE S256)
+	AuthCodeURL(state, codeChallenge string) string
+	// Exchange — обмен authorization code на токен провайдера
+	Exchange(ctx context.Context, code, codeVerifier string) (*Token, error)
+	// UserInfo — профиль пользователя по токену провайдера
+	UserInfo(ctx context.Context, token *Token) (*ExternalUser, error)
+}
@@ -0,0 +1,100 @@
+package oauth
+
+import "fmt"
+
+// mapOIDCUser — стандартные claims OpenID Connect userinfo
+func mapOIDCUser(data map[string]any) (*ExternalUser, error) {
+	return &ExternalUser{
+		Subject:       stringField(data, "sub"),
+		Email:         stringField(data, "email"),
+		EmailVerified: boolField(data, "email_verified"),
+		FirstName:     stringField(data, "given_name"),
+		LastName:      stringField(data, "family_name"),
+	}, nil
+}
+
+// mapYandexUser — https://login.yandex.ru/info (адреса Яндекса всегда подтверждены)
+func mapYandexUser(data map[string]any) (*ExternalUser, error) {
+	email := stringField(data, "default_email")
+	return &ExternalUser{
+		Subject:       stringField(data, "id"),
+		Email:         email,
+		EmailVerified: email != "",
+		FirstName:     stringField(data, "first_name"),
+		LastName:      stringField(data, "last_name"),
+	}, nil
+}
+
+// mapVKUser — VK ID user_info: профиль вложен в объект user.
+// Подтверждённость email VK не сообщает, поэтому по email аккаунты не связываются.
+func mapVKUser(data map[string]any) (*ExternalUser, error) {
+	user, ok := data["user"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("missing user object")
+	}
+	return &ExternalUser{
+		Subject:   stringField(user, "user_id"),
+		Email:     stringField(user, "email"),
+		FirstName: stringField(user, "first_name"),
+		LastName:  stringField(user, "last_name"),
+	}, nil
+}
+
+func newGoogleProvider(clientID, clientSecret, redirectURL string) IdentityProvider {
+	return newOAuth2Provider(endpointConfig{
+		name:         "google",
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		authURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+		tokenURL:     "https://oauth2.googleapis.com/token",
+		userInfoURL:  "https://openidconnect.googleapis.com/v1/userinfo",
+		scopes:       []string{"openid", "email", "profile"},
+		mapUser:      mapOIDCUser,
+	})
+}
+
+func newYandexProvider(clientID, clientSecret, redirectURL string) IdentityProvider {
+	return newOAuth2Provider(endpointConfig{
+		name:         "yandex",
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		authURL:      "https://oauth.yandex.ru/authorize",
+		tokenURL:     "https://oauth.yandex.ru/token",
+		userInfoURL:  "https://login.yandex.ru/info?format=json",
+		scopes:       []string{"login:email", "login:info"},
+		authScheme:   "OAuth",
+		mapUser:      mapYandexUser,
+	})
+}
+
+func newVKProvider(clientID, clientSecret, redirectURL string) IdentityProvider {
+	return newOAuth2Provider(endpointConfig{
+		name:         "vk",
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		authURL:      "https://id.vk.com/authorize",
+		tokenURL:     "https://id.vk.com/oauth2/auth",
+		userInfoURL:  "https://id.vk.com/oauth2/user_info",
+		scopes:       []string{"email"},
+		userInfoForm: true,
+		mapUser:      mapVKUser,
+	})
+}
+
+// newMockProvider — локальный OIDC провайдер (cmd/mock-oidc) для разработки и тестов.
+// publicURL открывается в браузере, internalURL используется сервисом для обмена кода.
+func newMockProvider(publicURL, internalURL, redirectURL string) IdentityProvider {
+	return newOAuth2Provider(endpointConfig{
+		name:        "mock",
+		clientID:    "huddle-mock-client",
+		redirectURL: redirectURL,
+		authURL:     publicURL + "/authorize",
+		tokenURL:    internalURL + "/token",
+		userInfoURL: internalURL + "/userinfo",
+		scopes:      []string{"openid", "email", "profile"},
+		mapUser:     mapOIDCUser,
+	})
+}
@@ -0,0 +1,46 @@
+package sms
+
+import (
+	"auth-service/internal/config"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fileSender — заглушка для локальной разработки: дописывает SMS в файл и в лог
+type fileSender struct {
+	path   string
+	mu     sync.Mutex
+	logger *zap.SugaredLogger
+}
+
+func newFileSender(cfg *config.SMSConfig, logger *zap.SugaredLogger) *fileSender {
+	return &fileSender{
+		path:   cfg.FilePath,
+		logger: logger,
+	}
+}
+
+// Send — дописывает SMS в файл и логирует его
+func (s *fileSender) Send(ctx context.Context, msg Message) error {
+	s.logger.Infow("SMS captured by file sender", "to", msg.To, "text", msg.Text)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		s.logger.Errorw("Failed to open sms file", "path", s.path, "error", err)
+		return fmt.Errorf("open sms file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "=== %s\nTo: %s\n\n%s\n\n", time.Now().Format(time.RFC3339), msg.To, msg.Text); err != nil {
+		return fmt.Errorf("write sms file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package sms
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// logSender — заглушка: SMS только пишутся в лог
+type logSender struct {
+	logger *zap.SugaredLogger
+}
+
+func newLogSender(logger *zap.SugaredLogger) *logSender {
+	return &logSender{logger: logger}
+}
+
+// Send — логирует SMS
+func (s *logSender) Send(ctx context.Context, msg Message) error {
+	s.logger.Infow("SMS captured by log sender", "to", msg.To, "text", msg.Text)
+	return nil
+}
@@ -0,0 +1,33 @@
+package sms
+
+import (
+	"auth-service/internal/config"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Message — SMS для отправки
+type Message struct {
+	To   string // Номер в формате E.164
+	Text string
+}
+
+// SMSSender — абстракция отправки SMS (лог или файл для локальной разработки;
+// реальный провайдер подключается новым драйвером)
+type SMSSender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New — создаёт SMSSender по драйверу из конфигурации
+func New(cfg *config.SMSConfig, logger *zap.SugaredLogger) (SMSSender, error) {
+	switch cfg.Driver {
+	case "log":
+		return newLogSender(logger), nil
+	case "file":
+		return newFileSender(cfg, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown sms driver: %s", cfg.Driver)
+	}
+}
@@ -14,14 +14,19 @@ import (
 	"event-service/internal/service"
 	http_transport "event-service/internal/transport/http"
 	"event-service/internal/transport/http/handlers"
+	events "event-service/internal/transport/kafka"
 	"event-service/pkg/db/postgres"
 	"event-service/pkg/db/redis"
 	"event-service/pkg/logger"
+	"event-service/pkg/servicetoken"
 
 	"go.uber.org/zap"
 )
 
 func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	cfg, err := config.New()
 	if err != nil {
 		panic(err)
@@ -54,10 +59,32 @@ func main() {
 	eventHandler := handlers.NewEventHandler(eventSvc)
 	categoryHandler := handlers.NewCategoryHandler(categoryRepo)
 
+	// События пользователей из auth-service (удаление аккаунта)
+	userConsumer := events.NewUserConsumer(
+		cfg.Kafka.Brokers,
+		cfg.Kafka.Topic,
+		cfg.Kafka.GroupID,
+		cfg.Kafka.DeletionAckTopic,
+		eventSvc,
+		log.SugaredLogger,
+	)
+	go func() {
+		log.Infow("Starting Kafka Consumer")
+		userConsumer.Start(ctx)
+	}()
+
 	routerCfg := http_transport.NewRouterConfig(cfg)
 	router := http_transport.NewRouter(routerCfg, log)
 
-	routes.SetupEventRoutes(router.Echo(), eventHandler, categoryHandler)
+	// Проверка машинных токенов для вызовов от других сервисов
+	serviceTokens := servicetoken.NewVerifier(servicetoken.Config{
+		JWKSURL:  cfg.Auth.JWKSURL,
+		Issuer:   cfg.Auth.Issuer,
+		Audience: cfg.Auth.Audience,
+		CacheTTL: cfg.Auth.JWKSCacheTTL,
+	}, log.SugaredLogger)
+
+	routes.SetupEventRoutes(router.Echo(), eventHandler, categoryHandler, serviceTokens, cfg.Auth.GatewaySecret)
 
 	go runServerWithRetry(router, cfg, log.SugaredLogger)
 	go runExpiredEventsWorker(eventRepo, log.SugaredLogger)
@@ -71,6 +98,11 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer shutdownCancel()
 
+	cancel()
+	if err := userConsumer.Close(); err != nil {
+		log.Errorw("Failed to close Kafka consumer", "error", err)
+	}
+
 	if err := router.ShuttingDown(shutdownCtx); err != nil {
 		log.Errorw("Server forced to shutdown", "error", err)
 	}
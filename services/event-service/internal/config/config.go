@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/ilyakaznacheev/cleanenv"
@@ -47,12 +48,33 @@ type RedisConfig struct {
 	Timeout    int    `env:"REDIS_TIMEOUT" env-default:"5" validate:"gte=1"`
 }
 
+type KafkaConfig struct {
+	Brokers []string `env:"KAFKA_BROKERS" env-default:"localhost:9092" env-separator:"," validate:"required,dive,hostname_port"`
+	Topic   string   `env:"KAFKA_TOPIC" env-default:"user-events" validate:"required"`
+	GroupID string   `env:"KAFKA_GROUP_ID" env-default:"event-service-group" validate:"required"`
+	// Топик подтверждений для саги удаления аккаунта (читает auth-service)
+	DeletionAckTopic string `env:"KAFKA_DELETION_ACK_TOPIC" env-default:"user-deletion-acks" validate:"required"`
+}
+
+// AuthConfig — проверка машинных токенов auth-service (вызовы от других сервисов)
+// и запросов пользователей, пришедших через Nginx
+type AuthConfig struct {
+	JWKSURL      string        `env:"AUTH_JWKS_URL" env-default:"http://auth-service:8080/.well-known/jwks.json" validate:"required,url"`
+	Issuer       string        `env:"AUTH_TOKEN_ISSUER" env-default:"auth-service" validate:"required"`
+	Audience     string        `env:"AUTH_TOKEN_AUDIENCE" env-default:"event-service" validate:"required"` // aud машинных токенов для этого сервиса
+	JWKSCacheTTL time.Duration `env:"AUTH_JWKS_CACHE_TTL" env-default:"5m" validate:"gt=0"`
+	// Общий секрет с Nginx (X-Gateway-Secret): без него заголовкам пользователя не верим
+	GatewaySecret string `env:"AUTH_GATEWAY_SECRET" validate:"required,min=32"`
+}
+
 type Config struct {
 	Env        string `env:"ENV" env-default:"development" validate:"oneof=development production"`
 	HTTPServer HTTPServerConfig
 	Postgres   PostgresConfig
 	Redis      RedisConfig
+	Kafka      KafkaConfig
 	Logger     LoggerConfig
+	Auth       AuthConfig
 }
 
 func New() (*Config, error) {
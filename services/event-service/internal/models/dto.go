@@ -29,3 +29,12 @@ type EventFilter struct {
 type UpdateParticipantStatusRequest struct {
 	Status ParticipantStatus `json:"status" validate:"required,oneof=accepted rejected"`
 }
+
+// CreateCategoryRequest - новая категория (нужно право categories:write)
+type CreateCategoryRequest struct {
+	ParentID  *int   `json:"parent_id"`
+	Name      string `json:"name" validate:"required,max=50"`
+	Slug      string `json:"slug" validate:"required,max=50"`
+	IconURL   string `json:"icon_url" validate:"omitempty,url"`
+	ColorCode string `json:"color_code" validate:"omitempty,hexcolor"`
+}
@@ -38,3 +38,16 @@ type Event struct {
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// UserDeleted — событие auth-service: пользователь удалил аккаунт
+type UserDeleted struct {
+	UserID    uuid.UUID `json:"user_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// UserDeletionAck — подтверждение для auth-service, что данные пользователя удалены
+type UserDeletionAck struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Service     string    `json:"service"`
+	CompletedAt time.Time `json:"completed_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// Права доступа, выдаваемые auth-service (заголовок X-User-Permissions)
+const (
+	PermEventsModerate  = "events:moderate"
+	PermCategoriesWrite = "categories:write"
+)
+
+// Scopes машинных токенов auth-service (вызовы от других сервисов)
+const (
+	ScopeEventsRead = "events:read"
+)
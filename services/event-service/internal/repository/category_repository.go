@@ -2,14 +2,25 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"event-service/internal/models"
+	"event-service/pkg/apperror"
 	"event-service/pkg/db/postgres"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.uber.org/zap"
 )
 
+var (
+	// ErrCategoryExists — категория с таким slug уже есть
+	ErrCategoryExists = apperror.Conflict("category with this slug already exists")
+	// ErrParentCategoryNotFound — указан несуществующий parent_id
+	ErrParentCategoryNotFound = apperror.Validation("parent category does not exist")
+)
+
 type CategoryRepository interface {
 	List(ctx context.Context) ([]models.Category, error)
+	Create(ctx context.Context, category *models.Category) error
 }
 
 type categoryRepository struct {
@@ -44,3 +55,27 @@ func (r *categoryRepository) List(ctx context.Context) ([]models.Category, error
 	}
 	return list, rows.Err()
 }
+
+func (r *categoryRepository) Create(ctx context.Context, category *models.Category) error {
+	query := `
+		INSERT INTO categories (parent_id, name, slug, icon_url, color_code)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''))
+		RETURNING id
+	`
+	if err := r.db.QueryRow(ctx, query,
+		category.ParentID, category.Name, category.Slug, category.IconURL, category.ColorCode,
+	).Scan(&category.ID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return ErrCategoryExists
+			case "23503":
+				return ErrParentCategoryNotFound
+			}
+		}
+		r.logger.Errorw("Failed to create category", "slug", category.Slug, "error", err)
+		return err
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"event-service/internal/models"
+	"event-service/pkg/apperror"
 	"event-service/pkg/db/postgres"
 	"fmt"
 
@@ -12,6 +13,13 @@ import (
 	"go.uber.org/zap"
 )
 
+var (
+	// ErrEventNotFound — события нет
+	ErrEventNotFound = apperror.NotFound("event not found")
+	// ErrParticipantNotFound — пользователь не участвует в событии и не подавал заявку
+	ErrParticipantNotFound = apperror.NotFound("not a participant")
+)
+
 // EventRepository — интерфейс для работы с событиями
 type EventRepository interface {
 	Create(ctx context.Context, event *models.Event) error
@@ -19,6 +27,7 @@ type EventRepository interface {
 	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Event, error)
 	ListNearby(ctx context.Context, filter models.EventFilter) ([]*models.Event, error)
 	Delete(ctx context.Context, eventID, creatorID uuid.UUID) error
+	DeleteByID(ctx context.Context, eventID uuid.UUID) error
 	UpdateStatus(ctx context.Context, eventID uuid.UUID, status models.EventStatus) error
 	MarkExpiredAsFinished(ctx context.Context) (int64, error)
 
@@ -31,6 +40,9 @@ type EventRepository interface {
 	ListParticipants(ctx context.Context, eventID uuid.UUID) ([]models.EventParticipant, error)
 	GetUserEventIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 
+	// Удаление аккаунта: участия удаляются, созданные события отменяются
+	EraseUser(ctx context.Context, userID uuid.UUID) (removed, cancelled int64, err error)
+
 	// Вспомогательные
 	CategoryExists(ctx context.Context, categoryID int) (bool, error)
 }
@@ -88,7 +100,7 @@ func (r *eventRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Ev
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
+			return nil, ErrEventNotFound
 		}
 		r.logger.Errorw("Failed to get event", "event_id", id, "error", err)
 		return nil, fmt.Errorf("failed to get event: %w", err)
@@ -178,6 +190,7 @@ func (r *eventRepository) ListNearby(ctx context.Context, filter models.EventFil
 	return events, rows.Err()
 }
 
+// Delete — удаление события автором; ErrEventNotFound, если события нет или автор другой
 func (r *eventRepository) Delete(ctx context.Context, eventID, creatorID uuid.UUID) error {
 	result, err := r.db.Pool.Exec(ctx, `DELETE FROM events WHERE id = $1 AND creator_id = $2`, eventID, creatorID)
 	if err != nil {
@@ -185,7 +198,20 @@ func (r *eventRepository) Delete(ctx context.Context, eventID, creatorID uuid.UU
 		return fmt.Errorf("failed to delete event: %w", err)
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("event not found or not authorized to delete")
+		return ErrEventNotFound
+	}
+	return nil
+}
+
+// DeleteByID - удаление без проверки автора (модерация)
+func (r *eventRepository) DeleteByID(ctx context.Context, eventID uuid.UUID) error {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM events WHERE id = $1`, eventID)
+	if err != nil {
+		r.logger.Errorw("Failed to delete event", "event_id", eventID, "error", err)
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrEventNotFound
 	}
 	return nil
 }
@@ -228,9 +254,9 @@ func (r *eventRepository) GetParticipant(ctx context.Context, eventID, userID uu
 	).Scan(&p.EventID, &p.UserID, &p.Status, &p.JoinedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
+			return nil, ErrParticipantNotFound
 		}
-		return nil, err
+		return nil, fmt.Errorf("get participant: %w", err)
 	}
 	return &p, nil
 }
@@ -316,3 +342,35 @@ func (r *eventRepository) CategoryExists(ctx context.Context, categoryID int) (b
 	}
 	return exists, nil
 }
+
+func (r *eventRepository) EraseUser(ctx context.Context, userID uuid.UUID) (removed, cancelled int64, err error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `DELETE FROM event_participants WHERE user_id = $1`, userID)
+	if err != nil {
+		r.logger.Errorw("Failed to remove user participations", "user_id", userID, "error", err)
+		return 0, 0, fmt.Errorf("remove participations: %w", err)
+	}
+	removed = result.RowsAffected()
+
+	result, err = tx.Exec(ctx, `
+		UPDATE events SET status = $2, updated_at = NOW()
+		WHERE creator_id = $1 AND status IN ($3, $4, $5)`,
+		userID, models.EventStatusCancelled,
+		models.EventStatusOpen, models.EventStatusFull, models.EventStatusStarted,
+	)
+	if err != nil {
+		r.logger.Errorw("Failed to cancel user events", "user_id", userID, "error", err)
+		return 0, 0, fmt.Errorf("cancel events: %w", err)
+	}
+	cancelled = result.RowsAffected()
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, fmt.Errorf("commit: %w", err)
+	}
+	return removed, cancelled, nil
+}
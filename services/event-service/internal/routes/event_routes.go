@@ -2,14 +2,16 @@ package routes
 
 import (
 	"event-service/internal/middleware"
+	"event-service/internal/models"
 	"event-service/internal/transport/http/handlers"
+	"event-service/pkg/servicetoken"
 
 	"github.com/labstack/echo/v4"
 )
 
-func SetupEventRoutes(router *echo.Echo, eventHandler *handlers.EventHandler, categoryHandler *handlers.CategoryHandler) {
+func SetupEventRoutes(router *echo.Echo, eventHandler *handlers.EventHandler, categoryHandler *handlers.CategoryHandler, serviceTokens *servicetoken.Verifier, gatewaySecret string) {
 	// Группа с авторизацией (Nginx уже проверил JWT, нам нужно просто вытащить ID)
-	api := router.Group("/api/v1", middleware.AuthMiddleware())
+	api := router.Group("/api/v1", middleware.AuthMiddleware(gatewaySecret))
 
 	events := api.Group("/events")
 	{
@@ -19,13 +21,14 @@ func SetupEventRoutes(router *echo.Echo, eventHandler *handlers.EventHandler, ca
 		// Поиск на карте: GET /api/v1/events?lat=55.75&lon=37.61&radius=1000
 		events.GET("", eventHandler.ListEvents)
 
-		events.GET("/:id", eventHandler.GetEvent)
 		events.DELETE("/:id", eventHandler.DeleteEvent)
 
+		// Модерация: удалить чужое событие
+		events.DELETE("/:id/moderate", eventHandler.ModerateDeleteEvent, middleware.RequirePermission(models.PermEventsModerate))
+
 		// Работа с участниками
 		participation := events.Group("/:id/participants")
 		{
-			participation.GET("", eventHandler.GetEventParticipants)
 			participation.POST("", eventHandler.JoinEvent)
 			participation.DELETE("", eventHandler.LeaveEvent)
 
@@ -39,8 +42,18 @@ func SetupEventRoutes(router *echo.Echo, eventHandler *handlers.EventHandler, ca
 		userEvents.GET("", eventHandler.GetMyEvents)
 	}
 
+	// Чтение событий доступно и другим сервисам: машинный токен со scope events:read
+	shared := router.Group("/api/v1/events")
+	{
+		readEvents := middleware.UserOrService(serviceTokens, gatewaySecret, models.ScopeEventsRead)
+
+		shared.GET("/:id", eventHandler.GetEvent, readEvents)
+		shared.GET("/:id/participants", eventHandler.GetEventParticipants, readEvents)
+	}
+
 	categories := api.Group("/categories")
 	{
 		categories.GET("", categoryHandler.ListCategories)
+		categories.POST("", categoryHandler.CreateCategory, middleware.RequirePermission(models.PermCategoriesWrite))
 	}
 }
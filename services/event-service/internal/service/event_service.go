@@ -2,25 +2,50 @@ package service
 
 import (
 	"context"
+	"errors"
 	"event-service/internal/models"
 	"event-service/internal/repository"
+	"event-service/pkg/apperror"
 	"fmt"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+var (
+	// ErrCategoryNotFound — событие ссылается на несуществующую категорию
+	ErrCategoryNotFound = apperror.Validation("category does not exist")
+	// ErrNotEventCreator — действие доступно только автору события
+	ErrNotEventCreator = apperror.Forbidden("only event creator can perform this action")
+	// ErrEventNotOpen — событие заполнено, началось, завершено или отменено
+	ErrEventNotOpen = apperror.Conflict("event is not open for joining")
+	// ErrEventFull — нет свободных мест
+	ErrEventFull = apperror.Conflict("event is full")
+	// ErrAlreadyParticipant — пользователь уже участник
+	ErrAlreadyParticipant = apperror.Conflict("already a participant")
+	// ErrRequestPending — заявка уже подана и ждёт решения автора
+	ErrRequestPending = apperror.Conflict("already requested, waiting for approval")
+	// ErrCreatorCannotLeave — автор не выходит из события, а удаляет его
+	ErrCreatorCannotLeave = apperror.Validation("creator cannot leave, use delete instead")
+	// ErrApprovalNotRequired — в событие вступают без одобрения
+	ErrApprovalNotRequired = apperror.Validation("event does not require approval")
+	// ErrNoPendingRequest — от пользователя нет заявки, ожидающей решения
+	ErrNoPendingRequest = apperror.NotFound("no pending request from this user")
+)
+
 // EventService — бизнес-логика событий Huddle
 type EventService interface {
 	Create(ctx context.Context, event *models.Event) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Event, error)
 	List(ctx context.Context, filter models.EventFilter) ([]*models.Event, error)
 	Delete(ctx context.Context, eventID, userID uuid.UUID) error
+	ModerateDelete(ctx context.Context, eventID, moderatorID uuid.UUID) error
 	Join(ctx context.Context, eventID, userID uuid.UUID) error
 	Leave(ctx context.Context, eventID, userID uuid.UUID) error
 	UpdateParticipantStatus(ctx context.Context, eventID, targetUserID, creatorID uuid.UUID, status models.ParticipantStatus) error
 	GetEventParticipants(ctx context.Context, eventID uuid.UUID) ([]models.EventParticipant, error)
 	GetUsersEvents(ctx context.Context, userID uuid.UUID) ([]*models.Event, error)
+	EraseUser(ctx context.Context, userID uuid.UUID) error
 }
 
 type eventService struct {
@@ -39,7 +64,7 @@ func (s *eventService) Create(ctx context.Context, event *models.Event) error {
 		return fmt.Errorf("failed to create event: %w", err)
 	}
 	if !ok {
-		return fmt.Errorf("category %d does not exist", event.CategoryID)
+		return ErrCategoryNotFound
 	}
 
 	event.Status = models.EventStatusOpen
@@ -52,15 +77,7 @@ func (s *eventService) Create(ctx context.Context, event *models.Event) error {
 }
 
 func (s *eventService) GetByID(ctx context.Context, id uuid.UUID) (*models.Event, error) {
-	event, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	if event == nil {
-		return nil, fmt.Errorf("event not found")
-	}
-
-	return event, nil
+	return s.repo.GetByID(ctx, id)
 }
 
 func (s *eventService) List(ctx context.Context, filter models.EventFilter) ([]*models.Event, error) {
@@ -73,39 +90,58 @@ func (s *eventService) List(ctx context.Context, filter models.EventFilter) ([]*
 }
 
 func (s *eventService) Delete(ctx context.Context, eventID, userID uuid.UUID) error {
+	// Сначала отличаем «нет события» (404) от «событие чужое» (403)
+	event, err := s.repo.GetByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if event.CreatorID != userID {
+		return ErrNotEventCreator
+	}
+
 	if err := s.repo.Delete(ctx, eventID, userID); err != nil {
-		if err.Error() == "event not found or not authorized to delete" {
-			return fmt.Errorf("only event creator can perform this action")
-		}
 		return err
 	}
 	s.logger.Infow("Event deleted", "event_id", eventID, "user_id", userID)
 	return nil
 }
 
+// ModerateDelete - удаление чужого события модератором (право events:moderate проверяет роут)
+func (s *eventService) ModerateDelete(ctx context.Context, eventID, moderatorID uuid.UUID) error {
+	if err := s.repo.DeleteByID(ctx, eventID); err != nil {
+		return err
+	}
+	s.logger.Infow("Event deleted by moderator", "event_id", eventID, "moderator_id", moderatorID)
+	return nil
+}
+
 func (s *eventService) Join(ctx context.Context, eventID, userID uuid.UUID) error {
 	event, err := s.repo.GetByID(ctx, eventID)
-	if err != nil || event == nil {
-		return fmt.Errorf("event not found")
+	if err != nil {
+		return err
 	}
 	if event.Status != models.EventStatusOpen {
-		return fmt.Errorf("event has expired")
+		return ErrEventNotOpen
 	}
 
-	participant, _ := s.repo.GetParticipant(ctx, eventID, userID)
-	if participant != nil {
-		if participant.Status == models.ParticipantStatusAccepted {
-			return fmt.Errorf("already a participant")
-		}
-		if participant.Status == models.ParticipantStatusPending {
-			return fmt.Errorf("already requested, waiting for approval")
-		}
-		// rejected — можно попробовать снова, обновим запись
+	participant, err := s.repo.GetParticipant(ctx, eventID, userID)
+	switch {
+	case errors.Is(err, repository.ErrParticipantNotFound):
+	case err != nil:
+		return fmt.Errorf("failed to check participant: %w", err)
+	case participant.Status == models.ParticipantStatusAccepted:
+		return ErrAlreadyParticipant
+	case participant.Status == models.ParticipantStatusPending:
+		return ErrRequestPending
 	}
+	// rejected — можно попробовать снова, обновим запись
 
-	count, _ := s.repo.CountAcceptedParticipants(ctx, eventID)
+	count, err := s.repo.CountAcceptedParticipants(ctx, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to count participants: %w", err)
+	}
 	if count >= event.MaxParticipants {
-		return fmt.Errorf("event is full")
+		return ErrEventFull
 	}
 
 	status := models.ParticipantStatusAccepted
@@ -124,16 +160,15 @@ func (s *eventService) Join(ctx context.Context, eventID, userID uuid.UUID) erro
 
 func (s *eventService) Leave(ctx context.Context, eventID, userID uuid.UUID) error {
 	event, err := s.repo.GetByID(ctx, eventID)
-	if err != nil || event == nil {
-		return fmt.Errorf("event not found")
+	if err != nil {
+		return err
 	}
 	if event.CreatorID == userID {
-		return fmt.Errorf("creator cannot leave, use delete instead")
+		return ErrCreatorCannotLeave
 	}
 
-	participant, _ := s.repo.GetParticipant(ctx, eventID, userID)
-	if participant == nil {
-		return fmt.Errorf("not a participant")
+	if _, err := s.repo.GetParticipant(ctx, eventID, userID); err != nil {
+		return err
 	}
 
 	if err := s.repo.RemoveParticipant(ctx, eventID, userID); err != nil {
@@ -146,19 +181,22 @@ func (s *eventService) Leave(ctx context.Context, eventID, userID uuid.UUID) err
 
 func (s *eventService) UpdateParticipantStatus(ctx context.Context, eventID, targetUserID, creatorID uuid.UUID, status models.ParticipantStatus) error {
 	event, err := s.repo.GetByID(ctx, eventID)
-	if err != nil || event == nil {
-		return fmt.Errorf("event not found")
+	if err != nil {
+		return err
 	}
 	if event.CreatorID != creatorID {
-		return fmt.Errorf("only event creator can perform this action")
+		return ErrNotEventCreator
 	}
 	if !event.RequiresApproval {
-		return fmt.Errorf("event does not require approval")
+		return ErrApprovalNotRequired
 	}
 
-	participant, _ := s.repo.GetParticipant(ctx, eventID, targetUserID)
+	participant, err := s.repo.GetParticipant(ctx, eventID, targetUserID)
+	if err != nil && !errors.Is(err, repository.ErrParticipantNotFound) {
+		return fmt.Errorf("failed to get participant: %w", err)
+	}
 	if participant == nil || participant.Status != models.ParticipantStatusPending {
-		return fmt.Errorf("no pending request from this user")
+		return ErrNoPendingRequest
 	}
 
 	if err := s.repo.UpdateParticipantStatus(ctx, eventID, targetUserID, status); err != nil {
@@ -178,9 +216,8 @@ func (s *eventService) UpdateParticipantStatus(ctx context.Context, eventID, tar
 }
 
 func (s *eventService) GetEventParticipants(ctx context.Context, eventID uuid.UUID) ([]models.EventParticipant, error) {
-	event, err := s.repo.GetByID(ctx, eventID)
-	if err != nil || event == nil {
-		return nil, fmt.Errorf("event not found")
+	if _, err := s.repo.GetByID(ctx, eventID); err != nil {
+		return nil, err
 	}
 	return s.repo.ListParticipants(ctx, eventID)
 }
@@ -207,3 +244,14 @@ func (s *eventService) GetUsersEvents(ctx context.Context, userID uuid.UUID) ([]
 	}
 	return active, nil
 }
+
+// EraseUser - удаление аккаунта: пользователь выходит из всех событий, его события отменяются
+func (s *eventService) EraseUser(ctx context.Context, userID uuid.UUID) error {
+	removed, cancelled, err := s.repo.EraseUser(ctx, userID)
+	if err != nil {
+		s.logger.Errorw("Failed to erase user data", "user_id", userID, "error", err)
+		return err
+	}
+	s.logger.Infow("User data erased", "user_id", userID, "participations_removed", removed, "events_cancelled", cancelled)
+	return nil
+}
@@ -4,17 +4,21 @@ import (
 	"net/http"
 
 	"event-service/internal/middleware"
+	"event-service/internal/models"
 	"event-service/internal/repository"
+	"event-service/pkg/apperror"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 )
 
 type CategoryHandler struct {
-	repo repository.CategoryRepository
+	repo      repository.CategoryRepository
+	validator *validator.Validate
 }
 
 func NewCategoryHandler(repo repository.CategoryRepository) *CategoryHandler {
-	return &CategoryHandler{repo: repo}
+	return &CategoryHandler{repo: repo, validator: validator.New()}
 }
 
 func (h *CategoryHandler) ListCategories(c echo.Context) error {
@@ -23,7 +27,35 @@ func (h *CategoryHandler) ListCategories(c echo.Context) error {
 	categories, err := h.repo.List(c.Request().Context())
 	if err != nil {
 		log.Errorw("Failed to list categories", "error", err)
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "failed to fetch categories"})
+		return err
 	}
 	return c.JSON(http.StatusOK, categories)
 }
+
+// CreateCategory - новая категория (право categories:write)
+func (h *CategoryHandler) CreateCategory(c echo.Context) error {
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	var req models.CreateCategoryRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return apperror.Validation(err.Error())
+	}
+
+	category := &models.Category{
+		ParentID:  req.ParentID,
+		Name:      req.Name,
+		Slug:      req.Slug,
+		IconURL:   req.IconURL,
+		ColorCode: req.ColorCode,
+	}
+	if err := h.repo.Create(c.Request().Context(), category); err != nil {
+		log.Warnw("Failed to create category", "slug", req.Slug, "error", err)
+		return err
+	}
+
+	log.Infow("Category created", "category_id", category.ID, "slug", category.Slug, "user_id", c.Get("user_id"))
+	return c.JSON(http.StatusCreated, category)
+}
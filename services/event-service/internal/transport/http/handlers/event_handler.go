@@ -4,11 +4,11 @@ import (
 	"context"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"event-service/internal/middleware"
 	"event-service/internal/models"
+	"event-service/pkg/apperror"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -20,6 +20,7 @@ type EventService interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Event, error)
 	List(ctx context.Context, filter models.EventFilter) ([]*models.Event, error)
 	Delete(ctx context.Context, eventID, userID uuid.UUID) error
+	ModerateDelete(ctx context.Context, eventID, moderatorID uuid.UUID) error
 	Join(ctx context.Context, eventID, userID uuid.UUID) error
 	Leave(ctx context.Context, eventID, userID uuid.UUID) error
 	UpdateParticipantStatus(ctx context.Context, eventID, targetUserID, creatorID uuid.UUID, status models.ParticipantStatus) error
@@ -49,17 +50,17 @@ func (h *EventHandler) CreateEvent(c echo.Context) error {
 	var req models.CreateEventRequest
 	if err := c.Bind(&req); err != nil {
 		log.Error("Failed to bind create event request", "error", err)
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request body"})
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
 	}
 	if err := h.validator.Struct(req); err != nil {
 		log.Warn("Validation failed", "error", err)
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return apperror.Validation(err.Error())
 	}
 	if req.StartTime.Before(time.Now()) {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "start_time must be in the future"})
+		return apperror.Validation("start_time must be in the future")
 	}
 	if req.Price < 0 {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "price cannot be negative"})
+		return apperror.Validation("price cannot be negative")
 	}
 
 	event := &models.Event{
@@ -78,10 +79,7 @@ func (h *EventHandler) CreateEvent(c echo.Context) error {
 
 	if err := h.service.Create(c.Request().Context(), event); err != nil {
 		log.Error("Failed to create event in service", "error", err)
-		if strings.Contains(err.Error(), "does not exist") {
-			return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
-		}
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+		return err
 	}
 
 	log.Info("Event created successfully", "event_id", event.ID)
@@ -108,7 +106,7 @@ func (h *EventHandler) ListEvents(c echo.Context) error {
 	events, err := h.service.List(c.Request().Context(), filter)
 	if err != nil {
 		log.Error("Failed to list events", "error", err)
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "failed to fetch events"})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, events)
@@ -119,15 +117,15 @@ func (h *EventHandler) GetEvent(c echo.Context) error {
 	log := middleware.GetLoggerFromCtx(c.Request().Context())
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid event id"})
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event id")
 	}
 
 	log.Info("Fetching event details", "event_id", id)
 
 	event, err := h.service.GetByID(c.Request().Context(), id)
 	if err != nil {
-		log.Warn("Event not found", "event_id", id)
-		return c.JSON(http.StatusNotFound, echo.Map{"error": "event not found"})
+		log.Warn("Failed to fetch event", "event_id", id, "error", err)
+		return err
 	}
 
 	return c.JSON(http.StatusOK, event)
@@ -143,7 +141,26 @@ func (h *EventHandler) DeleteEvent(c echo.Context) error {
 
 	if err := h.service.Delete(c.Request().Context(), eventID, userID); err != nil {
 		log.Error("Failed to delete event", "error", err)
-		return c.JSON(http.StatusForbidden, echo.Map{"error": "could not delete event"})
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Удаление события модератором (право events:moderate)
+func (h *EventHandler) ModerateDeleteEvent(c echo.Context) error {
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event id")
+	}
+	moderatorID := uuid.MustParse(c.Request().Header.Get("X-User-ID"))
+
+	log.Infow("Moderator deleting event", "event_id", eventID, "moderator_id", moderatorID)
+
+	if err := h.service.ModerateDelete(c.Request().Context(), eventID, moderatorID); err != nil {
+		log.Errorw("Failed to delete event", "error", err)
+		return err
 	}
 
 	return c.NoContent(http.StatusNoContent)
@@ -159,7 +176,7 @@ func (h *EventHandler) JoinEvent(c echo.Context) error {
 
 	if err := h.service.Join(c.Request().Context(), eventID, userID); err != nil {
 		log.Error("Failed to join event", "error", err)
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, echo.Map{"message": "request sent"})
@@ -175,7 +192,7 @@ func (h *EventHandler) LeaveEvent(c echo.Context) error {
 
 	if err := h.service.Leave(c.Request().Context(), eventID, userID); err != nil {
 		log.Error("Failed to leave event", "error", err)
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "failed to leave event"})
+		return err
 	}
 
 	return c.NoContent(http.StatusNoContent)
@@ -190,14 +207,17 @@ func (h *EventHandler) UpdateParticipantStatus(c echo.Context) error {
 
 	var req models.UpdateParticipantStatusRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid status"})
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid status")
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return apperror.Validation(err.Error())
 	}
 
 	log.Info("Updating participant status", "event_id", eventID, "target_user", targetUserID, "status", req.Status)
 
 	if err := h.service.UpdateParticipantStatus(c.Request().Context(), eventID, targetUserID, creatorID, req.Status); err != nil {
 		log.Error("Failed to update status", "error", err)
-		return c.JSON(http.StatusForbidden, echo.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, echo.Map{"status": "updated"})
@@ -208,16 +228,13 @@ func (h *EventHandler) GetEventParticipants(c echo.Context) error {
 	log := middleware.GetLoggerFromCtx(c.Request().Context())
 	eventID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid event id"})
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event id")
 	}
 
 	participants, err := h.service.GetEventParticipants(c.Request().Context(), eventID)
 	if err != nil {
 		log.Errorw("Failed to get participants", "event_id", eventID, "error", err)
-		if err.Error() == "event not found" {
-			return c.JSON(http.StatusNotFound, echo.Map{"error": "event not found"})
-		}
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "failed to fetch participants"})
+		return err
 	}
 	return c.JSON(http.StatusOK, participants)
 }
@@ -232,7 +249,7 @@ func (h *EventHandler) GetMyEvents(c echo.Context) error {
 	events, err := h.service.GetUsersEvents(c.Request().Context(), userID)
 	if err != nil {
 		log.Error("Failed to fetch user events", "error", err)
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "failed to fetch events"})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, events)
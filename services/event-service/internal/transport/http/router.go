@@ -27,6 +27,7 @@ func NewRouterConfig(cfg *config.Config) RouterConfig {
 
 func NewRouter(rConfig RouterConfig, logger *logger.Logger) *Router {
 	r := echo.New()
+	r.HTTPErrorHandler = middleware.ErrorHandler()
 
 	r.Use(middleware.RequestIDMiddleware())
 	r.Use(middleware.LoggingMiddleware(logger.SugaredLogger))
@@ -0,0 +1,114 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"event-service/internal/models"
+	"event-service/internal/service"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// serviceName — имя сервиса в подтверждениях саги удаления аккаунта
+const serviceName = "event-service"
+
+// UserConsumer — события пользователей из auth-service
+type UserConsumer struct {
+	reader  *kafka.Reader
+	acks    *kafka.Writer
+	service service.EventService
+	logger  *zap.SugaredLogger
+}
+
+func NewUserConsumer(brokers []string, topic, groupID, ackTopic string, service service.EventService, logger *zap.SugaredLogger) *UserConsumer {
+	return &UserConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:  brokers,
+			Topic:    topic,
+			GroupID:  groupID,
+			MinBytes: 10e3, // 10KB
+			MaxBytes: 10e6, // 10MB
+		}),
+		acks: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        ackTopic,
+			Balancer:     &kafka.Hash{},
+			BatchTimeout: 5 * time.Millisecond, // Подтверждения шлются по одному — не ждать добора пачки 1с
+			WriteTimeout: 10 * time.Second,
+		},
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Start запускает цикл прослушивания сообщений
+func (c *UserConsumer) Start(ctx context.Context) {
+	c.logger.Infow("Kafka consumer started", "topic", c.reader.Config().Topic)
+
+	for {
+		msg, err := c.reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.logger.Errorw("Failed to read message from Kafka", "error", err)
+			continue
+		}
+
+		if err := c.processEvent(ctx, msg); err != nil {
+			c.logger.Errorw("Failed to process event", "error", err)
+		}
+	}
+}
+
+func (c *UserConsumer) processEvent(ctx context.Context, msg kafka.Message) error {
+	// Остальные события пользователей event-service не интересуют
+	if headerValue(msg, "event_type") != "UserDeleted" {
+		return nil
+	}
+
+	var event models.UserDeleted
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	c.logger.Infow("Received UserDeleted event", "user_id", event.UserID)
+
+	if err := c.service.EraseUser(ctx, event.UserID); err != nil {
+		return err
+	}
+
+	// Подтверждаем auth-service, что данные удалены
+	ack, err := json.Marshal(models.UserDeletionAck{
+		UserID:      event.UserID,
+		Service:     serviceName,
+		CompletedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal deletion ack: %w", err)
+	}
+	if err := c.acks.WriteMessages(ctx, kafka.Message{Key: []byte(event.UserID.String()), Value: ack}); err != nil {
+		return fmt.Errorf("failed to publish deletion ack: %w", err)
+	}
+	return nil
+}
+
+// headerValue — значение заголовка Kafka-сообщения
+func headerValue(msg kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c *UserConsumer) Close() error {
+	if err := c.acks.Close(); err != nil {
+		c.logger.Errorw("Failed to close Kafka writer", "error", err)
+	}
+	return c.reader.Close()
+}
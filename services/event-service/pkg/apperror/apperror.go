@@ -0,0 +1,150 @@
+// Package apperror — типизированные ошибки домена.
+// Категория ошибки определяет HTTP-статус ответа, сообщение безопасно отдавать клиенту.
+package apperror
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Категории ошибок: errors.Is(err, apperror.ErrNotFound) срабатывает для любой ошибки категории
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrForbidden       = errors.New("forbidden")
+	ErrValidation      = errors.New("validation failed")
+	ErrUnavailable     = errors.New("service unavailable")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// Error — ошибка домена с категорией.
+// Сравнивается по указателю, поэтому значения из var-блоков работают как обычные sentinel-ошибки.
+type Error struct {
+	kind       error
+	message    string
+	cause      error
+	retryAfter time.Duration
+}
+
+// NotFound — сущность не найдена (404)
+func NotFound(message string) *Error {
+	return &Error{kind: ErrNotFound, message: message}
+}
+
+// Conflict — конфликт с текущим состоянием: дубликат, повторное действие (409)
+func Conflict(message string) *Error {
+	return &Error{kind: ErrConflict, message: message}
+}
+
+// Forbidden — действие запрещено для этого пользователя (403)
+func Forbidden(message string) *Error {
+	return &Error{kind: ErrForbidden, message: message}
+}
+
+// Validation — запрос корректен по форме, но нарушает правила домена (422)
+func Validation(message string) *Error {
+	return &Error{kind: ErrValidation, message: message}
+}
+
+// Unavailable — зависимость недоступна, запрос можно повторить позже (503).
+// cause в ответ не попадает, но доступна через errors.Is/As.
+func Unavailable(message string, cause error) *Error {
+	return &Error{kind: ErrUnavailable, message: message, cause: cause}
+}
+
+// Unauthorized — учётные данные или одноразовый код не подошли (401)
+func Unauthorized(message string) *Error {
+	return &Error{kind: ErrUnauthorized, message: message}
+}
+
+// TooManyRequests — превышен лимит запросов (429).
+// retryAfter > 0 уходит клиенту в заголовке Retry-After.
+func TooManyRequests(message string, retryAfter time.Duration) *Error {
+	return &Error{kind: ErrTooManyRequests, message: message, retryAfter: retryAfter}
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.message + ": " + e.cause.Error()
+	}
+	return e.message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is — совпадение с категорией ошибки
+func (e *Error) Is(target error) bool {
+	return target == e.kind
+}
+
+// Message — текст для клиента
+func (e *Error) Message() string {
+	return e.message
+}
+
+// HTTPStatus — статус ответа для ошибки; ошибки без категории — 500
+func HTTPStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Code — машиночитаемый код категории для тела ответа
+func Code(err error) string {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrConflict):
+		return "conflict"
+	case errors.Is(err, ErrForbidden):
+		return "forbidden"
+	case errors.Is(err, ErrValidation):
+		return "validation_failed"
+	case errors.Is(err, ErrUnavailable):
+		return "unavailable"
+	case errors.Is(err, ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, ErrTooManyRequests):
+		return "too_many_requests"
+	default:
+		return "internal"
+	}
+}
+
+// PublicMessage — текст для клиента: сообщение ошибки домена или общий текст для остальных,
+// чтобы детали инфраструктурных ошибок не утекали в ответ
+func PublicMessage(err error) string {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.message
+	}
+	return "internal server error"
+}
+
+// RetryAfter — через сколько клиенту можно повторить запрос; 0 — не указано
+func RetryAfter(err error) time.Duration {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.retryAfter
+	}
+	return 0
+}
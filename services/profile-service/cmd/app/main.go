@@ -18,6 +18,7 @@ import (
 	"profile-service/pkg/db/postgres"
 	"profile-service/pkg/db/redis"
 	"profile-service/pkg/logger"
+	"profile-service/pkg/servicetoken"
 
 	"go.uber.org/zap"
 )
@@ -67,6 +68,7 @@ func main() {
 	userConsumer := events.NewUserConsumer(
 		cfg.Kafka.Brokers,
 		"user-events",
+		cfg.Kafka.DeletionAckTopic,
 		profileSvc,
 		log.SugaredLogger,
 	)
@@ -86,7 +88,15 @@ func main() {
 	// Примечание: Здесь можно добавить AuthMiddleware, если профилю нужно валидировать токены от Auth-service
 
 	// Регистрация маршрутов
-	routes.SetupProfileRoutes(router.Echo(), profileHandler)
+	// Проверка машинных токенов для вызовов от других сервисов
+	serviceTokens := servicetoken.NewVerifier(servicetoken.Config{
+		JWKSURL:  cfg.Auth.JWKSURL,
+		Issuer:   cfg.Auth.Issuer,
+		Audience: cfg.Auth.Audience,
+		CacheTTL: cfg.Auth.JWKSCacheTTL,
+	}, log.SugaredLogger)
+
+	routes.SetupProfileRoutes(router.Echo(), profileHandler, serviceTokens, cfg.Auth.GatewaySecret)
 
 	// Запуск HTTP сервера в отдельной горутине
 	go runServerWithRetry(router, cfg, log.SugaredLogger)
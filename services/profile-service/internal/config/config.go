@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/ilyakaznacheev/cleanenv"
@@ -55,6 +56,19 @@ type KafkaConfig struct {
 	RetryDelay   int      `env:"KAFKA_RETRY_DELAY" env-default:"2" validate:"gte=1"`
 	BatchSize    int      `env:"KAFKA_BATCH_SIZE" env-default:"100" validate:"gte=1,lte=1000"`
 	PollInterval int      `env:"KAFKA_POLL_INTERVAL" env-default:"5" validate:"gte=1"`
+	// Топик подтверждений для саги удаления аккаунта (читает auth-service)
+	DeletionAckTopic string `env:"KAFKA_DELETION_ACK_TOPIC" env-default:"user-deletion-acks" validate:"required"`
+}
+
+// AuthConfig — проверка машинных токенов auth-service (вызовы от других сервисов)
+// и запросов пользователей, пришедших через Nginx
+type AuthConfig struct {
+	JWKSURL      string        `env:"AUTH_JWKS_URL" env-default:"http://auth-service:8080/.well-known/jwks.json" validate:"required,url"`
+	Issuer       string        `env:"AUTH_TOKEN_ISSUER" env-default:"auth-service" validate:"required"`
+	Audience     string        `env:"AUTH_TOKEN_AUDIENCE" env-default:"profile-service" validate:"required"` // aud машинных токенов для этого сервиса
+	JWKSCacheTTL time.Duration `env:"AUTH_JWKS_CACHE_TTL" env-default:"5m" validate:"gt=0"`
+	// Общий секрет с Nginx (X-Gateway-Secret): без него заголовкам пользователя не верим
+	GatewaySecret string `env:"AUTH_GATEWAY_SECRET" validate:"required,min=32"`
 }
 
 type Config struct {
@@ -64,6 +78,7 @@ type Config struct {
 	Redis      RedisConfig
 	Kafka      KafkaConfig
 	Logger     LoggerConfig
+	Auth       AuthConfig
 }
 
 func New() (*Config, error) {
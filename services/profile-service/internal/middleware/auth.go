@@ -1,21 +1,100 @@
 package middleware
 
 import (
-	"net/http"
+	"crypto/subtle"
+	"profile-service/pkg/apperror"
+	"profile-service/pkg/servicetoken"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
 
-func AuthMiddleware() echo.MiddlewareFunc {
+// serviceIDKey — ключ echo.Context с client_id сервиса, вызвавшего эндпоинт напрямую
+const serviceIDKey = "service_id"
+
+// gatewaySecretHeader — общий секрет Nginx и сервиса (AUTH_GATEWAY_SECRET)
+const gatewaySecretHeader = "X-Gateway-Secret"
+
+// identityHeaders — заголовки пользователя, которые выставляет Nginx по ответу /auth/validate
+var identityHeaders = []string{
+	"X-User-ID", "X-User-Role", "X-User-Email", "X-User-Permissions", "X-Token-JTI", "X-Token-Expires-At",
+}
+
+func AuthMiddleware(gatewaySecret string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			userID := c.Request().Header.Get("X-User-ID")
+			// Заголовкам пользователя верим, только если запрос пришёл через Nginx
+			if !fromGateway(c, gatewaySecret) {
+				return apperror.Forbidden("direct access forbidden")
+			}
 
+			// Nginx прислал нам это в заголовке
+			userID := c.Request().Header.Get("X-User-ID")
 			if userID == "" {
-				return c.JSON(http.StatusForbidden, echo.Map{"error": "header X-User-ID is empty"})
+				return apperror.Forbidden("header X-User-ID is empty")
 			}
 
+			// Кладем ID пользователя в контекст для хендлеров
 			c.Set("user_id", userID)
+			// Роль и email из /auth/validate — без повторного разбора токена
+			c.Set("user_role", c.Request().Header.Get("X-User-Role"))
+			c.Set("user_email", c.Request().Header.Get("X-User-Email"))
+			c.Set(userPermissionsKey, parsePermissions(c.Request().Header.Get("X-User-Permissions")))
+			return next(c)
+		}
+	}
+}
+
+// fromGateway — запрос пришёл через Nginx: только он знает общий секрет и выставляет X-Gateway-Secret.
+// Без этой проверки любой, кто достучался до сервиса внутри сети, мог бы подставить X-User-ID и права.
+func fromGateway(c echo.Context, gatewaySecret string) bool {
+	got := c.Request().Header.Get(gatewaySecretHeader)
+	return got != "" && subtle.ConstantTimeCompare([]byte(got), []byte(gatewaySecret)) == 1
+}
+
+// UserOrService — пускает сервис с машинным токеном auth-service (Authorization: Bearer,
+// client-credentials), у которого есть scope, или пользователя (X-User-ID от Nginx).
+// Сначала проверяется машинный токен: заголовки пользователя в вызове сервиса игнорируются
+// и удаляются, чтобы хендлеры не прочитали подставленный X-User-ID.
+// Права пользователя проверяются дальше по цепочке (RequirePermission); scopes сервиса
+// кладутся туда же, поэтому RequirePermission с тем же именем права пропустит и сервис.
+func UserOrService(verifier *servicetoken.Verifier, gatewaySecret, scope string) echo.MiddlewareFunc {
+	userAuth := AuthMiddleware(gatewaySecret)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		asUser := userAuth(next)
+
+		return func(c echo.Context) error {
+			log := GetLoggerFromCtx(c.Request().Context())
+
+			parts := strings.SplitN(c.Request().Header.Get("Authorization"), " ", 2)
+			hasBearer := len(parts) == 2 && strings.EqualFold(parts[0], "Bearer")
+			if !hasBearer {
+				if fromGateway(c, gatewaySecret) {
+					return asUser(c)
+				}
+				return apperror.Unauthorized("missing user context or service token")
+			}
+
+			principal, err := verifier.Verify(c.Request().Context(), parts[1])
+			if err != nil {
+				// Nginx пробрасывает access токен пользователя — это не машинный токен
+				if fromGateway(c, gatewaySecret) {
+					return asUser(c)
+				}
+				log.Infow("Service token rejected", "error", err)
+				return apperror.Unauthorized("invalid or expired service token")
+			}
+			if !principal.HasScope(scope) {
+				log.Warnw("Access denied: missing scope", "client_id", principal.ClientID, "required", scope, "path", c.Path())
+				return apperror.Forbidden("insufficient scope")
+			}
+
+			for _, h := range identityHeaders {
+				c.Request().Header.Del(h)
+			}
+			c.Set(serviceIDKey, principal.ClientID)
+			c.Set(userPermissionsKey, principal.Scopes)
 			return next(c)
 		}
 	}
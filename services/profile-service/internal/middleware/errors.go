@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"profile-service/pkg/apperror"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ErrorHandler — единый ответ на ошибки, возвращённые хендлерами.
+// Ошибки домена (apperror) отображаются в 404/409/403/422/503/401/429 со своим сообщением,
+// echo.HTTPError — в свой статус, остальные — в 500 без подробностей.
+// Тело всегда {"error": ..., "code": ..., "request_id": ...}.
+func ErrorHandler() echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		// Ошибку логирует хендлер, здесь формируется только ответ
+		status, message, code := errorResponse(err)
+		if retryAfter := apperror.RetryAfter(err); retryAfter > 0 {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		}
+
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(status)
+		} else {
+			err = c.JSON(status, echo.Map{
+				"error":      message,
+				"code":       code,
+				"request_id": GetRequestIDFromCtx(c.Request().Context()),
+			})
+		}
+		if err != nil {
+			GetLoggerFromCtx(c.Request().Context()).Errorw("Failed to write error response", "error", err)
+		}
+	}
+}
+
+func errorResponse(err error) (status int, message, code string) {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		message := http.StatusText(httpErr.Code)
+		if m, ok := httpErr.Message.(string); ok {
+			message = m
+		} else if httpErr.Message != nil {
+			message = fmt.Sprint(httpErr.Message)
+		}
+		return httpErr.Code, message, strings.ReplaceAll(strings.ToLower(http.StatusText(httpErr.Code)), " ", "_")
+	}
+
+	return apperror.HTTPStatus(err), apperror.PublicMessage(err), apperror.Code(err)
+}
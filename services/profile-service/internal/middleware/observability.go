@@ -40,7 +40,10 @@ func LoggingMiddleware(logger *zap.SugaredLogger) echo.MiddlewareFunc {
 			ctx := context.WithValue(c.Request().Context(), loggerKey, reqLogger)
 			c.SetRequest(c.Request().WithContext(ctx))
 
-			err := next(c)
+			// Ошибку сразу отдаём в ErrorHandler, чтобы в лог попал итоговый статус
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
 
 			duration := time.Since(start)
 			status := c.Response().Status
@@ -49,7 +52,7 @@ func LoggingMiddleware(logger *zap.SugaredLogger) echo.MiddlewareFunc {
 				"status", status,
 				"duration_ms", duration.Milliseconds(),
 			)
-			return err
+			return nil
 		}
 	}
 }
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"profile-service/pkg/apperror"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// userPermissionsKey — ключ echo.Context с правами пользователя из X-User-Permissions
+const userPermissionsKey = "user_permissions"
+
+// parsePermissions — разбирает заголовок X-User-Permissions ("events:moderate,categories:write")
+func parsePermissions(header string) []string {
+	var perms []string
+	for _, p := range strings.Split(header, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			perms = append(perms, p)
+		}
+	}
+	return perms
+}
+
+// HasPermission — есть ли у пользователя право (AuthMiddleware уже разобрал заголовок)
+func HasPermission(c echo.Context, permission string) bool {
+	perms, _ := c.Get(userPermissionsKey).([]string)
+	return slices.Contains(perms, permission)
+}
+
+// RequirePermission — пропускает только пользователей со всеми перечисленными правами.
+// Права выдаёт auth-service, Nginx пробрасывает их из /auth/validate в X-User-Permissions.
+func RequirePermission(permissions ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			for _, permission := range permissions {
+				if !HasPermission(c, permission) {
+					GetLoggerFromCtx(c.Request().Context()).Warnw("Access denied: missing permission",
+						"user_id", c.Get("user_id"),
+						"required", permission,
+						"path", c.Path(),
+					)
+					return apperror.Forbidden("forbidden")
+				}
+			}
+			return next(c)
+		}
+	}
+}
@@ -15,3 +15,16 @@ type UserRegistered struct {
 	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// UserDeleted — пользователь удалил аккаунт
+type UserDeleted struct {
+	UserID    uuid.UUID `json:"user_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// UserDeletionAck — подтверждение для auth-service, что профиль удалён
+type UserDeletionAck struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Service     string    `json:"service"`
+	CompletedAt time.Time `json:"completed_at"`
+}
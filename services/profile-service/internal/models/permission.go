@@ -0,0 +1,6 @@
+package models
+
+// Права доступа, выдаваемые auth-service (заголовок X-User-Permissions)
+const (
+	PermProfilesRead = "profiles:read"
+)
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"profile-service/internal/models"
+	"profile-service/pkg/apperror"
 	"profile-service/pkg/db/postgres"
 
 	"github.com/google/uuid"
@@ -12,10 +13,14 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrProfileNotFound — профиля пользователя нет
+var ErrProfileNotFound = apperror.NotFound("profile not found")
+
 // ProfileRepository — интерфейс для работы с БД профилей
 type ProfileRepository interface {
 	Create(ctx context.Context, profile *models.Profile) error
 	GetByUserID(ctx context.Context, userID uuid.UUID) (*models.Profile, error)
+	Delete(ctx context.Context, userID uuid.UUID) error
 }
 
 type profileRepository struct {
@@ -75,7 +80,7 @@ func (r *profileRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("profile not found")
+			return nil, ErrProfileNotFound
 		}
 		r.logger.Errorw("Database error on GetByUserID", "user_id", userID, "error", err)
 		return nil, fmt.Errorf("failed to query profile: %w", err)
@@ -83,3 +88,15 @@ func (r *profileRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 
 	return profile, nil
 }
+
+// Delete — удаление профиля (повторное удаление не ошибка)
+func (r *profileRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	query := `DELETE FROM profile.profiles WHERE user_id = $1`
+
+	if err := r.db.Exec(ctx, query, userID); err != nil {
+		r.logger.Errorw("Failed to delete profile", "user_id", userID, "error", err)
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+
+	return nil
+}
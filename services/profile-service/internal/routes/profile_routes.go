@@ -2,26 +2,34 @@ package routes
 
 import (
 	"profile-service/internal/middleware"
+	"profile-service/internal/models"
 	"profile-service/internal/transport/http/handlers"
+	"profile-service/pkg/servicetoken"
 
 	"github.com/labstack/echo/v4"
 )
 
-func SetupProfileRoutes(router *echo.Echo, profileHandler *handlers.ProfileHandler) {
+func SetupProfileRoutes(router *echo.Echo, profileHandler *handlers.ProfileHandler, serviceTokens *servicetoken.Verifier, gatewaySecret string) {
 	// Базовая группа API с версией
 	api := router.Group("/api/v1")
 
 	// Группа для авторизации
-	profiles := api.Group("/profiles", middleware.AuthMiddleware())
+	profiles := api.Group("/profiles", middleware.AuthMiddleware(gatewaySecret))
 	{
 		// GET /api/v1/profiles/me -> Получить СВОЙ профиль
 		profiles.GET("/me", profileHandler.GetProfile)
 
-		// GET /api/v1/profiles/:id -> Посмотреть ЧУЖОЙ профиль
-		// profiles.GET("/:id", profileHandler.GetProfileByID)
-
 		// PUT /api/v1/profiles/me -> Обновить свой профиль
 		// profiles.PUT("/me", profileHandler.UpdateProfile)
 	}
 
+	// GET /api/v1/profiles/:id -> Посмотреть ЧУЖОЙ профиль.
+	// Доступно пользователю с правом profiles:read и сервису с машинным токеном со scope profiles:read.
+	shared := api.Group("/profiles")
+	{
+		shared.GET("/:id", profileHandler.GetProfileByID,
+			middleware.UserOrService(serviceTokens, gatewaySecret, models.PermProfilesRead),
+			middleware.RequirePermission(models.PermProfilesRead),
+		)
+	}
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"profile-service/internal/models"
 	"profile-service/internal/repository"
@@ -15,6 +16,7 @@ import (
 type ProfileService interface {
 	GetProfile(ctx context.Context, userID uuid.UUID) (*models.Profile, error)
 	CreateProfile(ctx context.Context, profileData models.UserRegistered) error
+	DeleteProfile(ctx context.Context, userID uuid.UUID) error
 }
 
 type profileService struct {
@@ -36,7 +38,9 @@ func NewProfileService(
 func (s *profileService) GetProfile(ctx context.Context, userID uuid.UUID) (*models.Profile, error) {
 	profile, err := s.profileRepo.GetByUserID(ctx, userID)
 	if err != nil {
-		s.logger.Errorw("Failed to get profile", "user_id", userID, "error", err)
+		if !errors.Is(err, repository.ErrProfileNotFound) {
+			s.logger.Errorw("Failed to get profile", "user_id", userID, "error", err)
+		}
 		return nil, fmt.Errorf("failed to get profile: %w", err)
 	}
 	return profile, nil
@@ -67,3 +71,13 @@ func (s *profileService) CreateProfile(ctx context.Context, profileData models.U
 	s.logger.Infow("Profile created successfully", "user_id", profileData.UserID)
 	return nil
 }
+
+// DeleteProfile — удаление профиля по событию UserDeleted
+func (s *profileService) DeleteProfile(ctx context.Context, userID uuid.UUID) error {
+	if err := s.profileRepo.Delete(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+
+	s.logger.Infow("Profile deleted", "user_id", userID)
+	return nil
+}
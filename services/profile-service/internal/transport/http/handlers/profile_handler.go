@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"profile-service/internal/middleware"
 	"profile-service/internal/service"
+	"profile-service/pkg/apperror"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -33,19 +34,38 @@ func (h *ProfileHandler) GetProfile(c echo.Context) error {
 	userIDStr, ok := c.Get("user_id").(string)
 	if !ok {
 		log.Error("failed to extract userID from context (not a string)")
-		return c.JSON(http.StatusUnauthorized, echo.Map{"error": "unauthorized"})
+		return apperror.Unauthorized("unauthorized")
 	}
 
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
 		log.Errorw("failed to parse userID string to UUID", "str", userIDStr, "error", err)
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid user id format"})
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid user id format")
 	}
 
 	profile, err := h.service.GetProfile(c.Request().Context(), userID)
 	if err != nil {
-		log.Errorw("Error retrieving profile", "UserID", userID, "error", err)
-		return c.JSON(http.StatusNotFound, echo.Map{"error": "failed to get profile"})
+		// 404 для отсутствующего профиля, 503/500 для сбоев БД — см. middleware.ErrorHandler
+		log.Infow("Error retrieving profile", "UserID", userID, "error", err)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, profile)
+}
+
+// GetProfileByID - просмотр чужого профиля (право profiles:read)
+func (h *ProfileHandler) GetProfileByID(c echo.Context) error {
+	log := middleware.GetLoggerFromCtx(c.Request().Context())
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid user id format")
+	}
+
+	profile, err := h.service.GetProfile(c.Request().Context(), userID)
+	if err != nil {
+		log.Infow("Error retrieving profile", "UserID", userID, "error", err)
+		return err
 	}
 
 	return c.JSON(http.StatusOK, profile)
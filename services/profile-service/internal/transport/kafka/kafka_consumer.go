@@ -6,18 +6,26 @@ import (
 	"fmt"
 	"profile-service/internal/models"
 	"profile-service/internal/service"
+	"time"
 
 	"github.com/segmentio/kafka-go"
 	"go.uber.org/zap"
 )
 
+// serviceName — имя сервиса в подтверждениях саги удаления аккаунта
+const serviceName = "profile-service"
+
+// supportedSchemaVersion — версия схемы событий auth-service, которую понимает сервис
+const supportedSchemaVersion = "1"
+
 type UserConsumer struct {
 	reader  *kafka.Reader
+	acks    *kafka.Writer
 	service service.ProfileService
 	logger  *zap.SugaredLogger
 }
 
-func NewUserConsumer(brokers []string, topic string, service service.ProfileService, logger *zap.SugaredLogger) *UserConsumer {
+func NewUserConsumer(brokers []string, topic, ackTopic string, service service.ProfileService, logger *zap.SugaredLogger) *UserConsumer {
 	return &UserConsumer{
 		reader: kafka.NewReader(kafka.ReaderConfig{
 			Brokers:  brokers,
@@ -26,6 +34,13 @@ func NewUserConsumer(brokers []string, topic string, service service.ProfileServ
 			MinBytes: 10e3,                    // 10KB
 			MaxBytes: 10e6,                    // 10MB
 		}),
+		acks: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        ackTopic,
+			Balancer:     &kafka.Hash{},
+			BatchTimeout: 5 * time.Millisecond, // Подтверждения шлются по одному — не ждать добора пачки 1с
+			WriteTimeout: 10 * time.Second,
+		},
 		service: service,
 		logger:  logger,
 	}
@@ -47,26 +62,97 @@ func (c *UserConsumer) Start(ctx context.Context) {
 		}
 
 		// Обрабатываем сообщение
-		if err := c.processEvent(ctx, msg.Value); err != nil {
-			c.logger.Errorw("Failed to process event", "error", err)
+		if err := c.processEvent(ctx, msg); err != nil {
+			c.logger.Errorw("Failed to process event",
+				"event_type", headerValue(msg, "event_type"),
+				"event_id", headerValue(msg, "event_id"),
+				"error", err)
 			// TODO Retry или отправку в DLQ (Dead Letter Queue)
 		}
 	}
 }
 
-func (c *UserConsumer) processEvent(ctx context.Context, payload []byte) error {
+// processEvent — выбор обработчика по заголовкам; payload разбирается только нужным обработчиком
+func (c *UserConsumer) processEvent(ctx context.Context, msg kafka.Message) error {
+	eventType := headerValue(msg, "event_type")
+	log := c.logger.With(
+		"event_type", eventType,
+		"event_id", headerValue(msg, "event_id"),
+		"request_id", headerValue(msg, "request_id"),
+		"traceparent", headerValue(msg, "traceparent"),
+	)
+
+	// Без schema_version — сообщения старых версий auth-service, это версия 1
+	if version := headerValue(msg, "schema_version"); version != "" && version != supportedSchemaVersion {
+		log.Warnw("Skipping event with unsupported schema version", "schema_version", version)
+		return nil
+	}
+
+	switch eventType {
+	// Сообщения без заголовка — от старых версий auth-service, там был только UserRegistered
+	case "UserRegistered", "":
+		return c.handleUserRegistered(ctx, log, msg.Value)
+	case "UserDeleted":
+		return c.handleUserDeleted(ctx, log, msg.Value)
+	default:
+		log.Debugw("Skipping event")
+		return nil
+	}
+}
+
+func (c *UserConsumer) handleUserRegistered(ctx context.Context, log *zap.SugaredLogger, payload []byte) error {
 	// Распаковываем JSON в структуру события
 	var event models.UserRegistered
 	if err := json.Unmarshal(payload, &event); err != nil {
 		return fmt.Errorf("failed to unmarshal event: %w", err)
 	}
 
-	c.logger.Infow("Received UserRegistered event", "user_id", event.UserID)
+	log.Infow("Received UserRegistered event", "user_id", event.UserID)
 
 	// Вызываем наш сервис для создания профиля
 	return c.service.CreateProfile(ctx, event)
 }
 
+func (c *UserConsumer) handleUserDeleted(ctx context.Context, log *zap.SugaredLogger, payload []byte) error {
+	var event models.UserDeleted
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	log.Infow("Received UserDeleted event", "user_id", event.UserID)
+
+	if err := c.service.DeleteProfile(ctx, event.UserID); err != nil {
+		return err
+	}
+
+	// Подтверждаем auth-service, что профиль удалён
+	ack, err := json.Marshal(models.UserDeletionAck{
+		UserID:      event.UserID,
+		Service:     serviceName,
+		CompletedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal deletion ack: %w", err)
+	}
+	if err := c.acks.WriteMessages(ctx, kafka.Message{Key: []byte(event.UserID.String()), Value: ack}); err != nil {
+		return fmt.Errorf("failed to publish deletion ack: %w", err)
+	}
+	return nil
+}
+
+// headerValue — значение заголовка Kafka-сообщения
+func headerValue(msg kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
 func (c *UserConsumer) Close() error {
+	if err := c.acks.Close(); err != nil {
+		c.logger.Errorw("Failed to close Kafka writer", "error", err)
+	}
 	return c.reader.Close()
 }
@@ -0,0 +1,236 @@
+package servicetoken
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// TokenType — заголовок typ машинного токена auth-service
+const TokenType = "svc+jwt"
+
+// minRefreshInterval — не чаще этого JWKS перечитывается из-за неизвестного kid
+const minRefreshInterval = 30 * time.Second
+
+// ErrInvalidToken — токен не прошёл проверку
+var ErrInvalidToken = errors.New("invalid service token")
+
+// Principal — сервис, предъявивший машинный токен
+type Principal struct {
+	ClientID string
+	Scopes   []string
+}
+
+// HasScope — выдан ли токену scope
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// Config — откуда брать ключи и чей токен принимать
+type Config struct {
+	JWKSURL  string        // http://auth-service:8080/.well-known/jwks.json
+	Issuer   string        // iss токенов auth-service
+	Audience string        // aud, который обязан быть в токене: имя этого сервиса
+	CacheTTL time.Duration // Как долго ключи считаются актуальными
+}
+
+// claims — claims машинного токена auth-service
+type claims struct {
+	jwt.RegisteredClaims
+
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope"`
+}
+
+// jwk — публичный ключ из JWKS (RSA или Ed25519)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+// Verifier — локальная проверка машинных токенов по JWKS auth-service.
+// Ключи загружаются лениво и перечитываются по истечении CacheTTL или при неизвестном kid.
+type Verifier struct {
+	cfg    Config
+	client *http.Client
+	logger *zap.SugaredLogger
+
+	mu        sync.RWMutex
+	keys      map[string]verificationKey
+	fetchedAt time.Time
+}
+
+type verificationKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+// NewVerifier — конструктор
+func NewVerifier(cfg Config, logger *zap.SugaredLogger) *Verifier {
+	return &Verifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 5 * time.Second},
+		logger: logger,
+	}
+}
+
+// Verify — проверяет подпись, срок, issuer, audience и typ; возвращает сервис-принципала
+func (v *Verifier) Verify(ctx context.Context, tokenStr string) (*Principal, error) {
+	c := &claims{}
+	token, err := jwt.ParseWithClaims(tokenStr, c,
+		func(t *jwt.Token) (interface{}, error) { return v.keyFor(ctx, t) },
+		jwt.WithIssuer(v.cfg.Issuer),
+		jwt.WithAudience(v.cfg.Audience), // Токен, выданный для другого сервиса, здесь не действует
+		jwt.WithValidMethods([]string{"RS256", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(5*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	// Access токен пользователя подписан тем же ключом — отличаем по typ
+	if typ, _ := token.Header["typ"].(string); typ != TokenType {
+		return nil, fmt.Errorf("%w: not a service token", ErrInvalidToken)
+	}
+	if c.ClientID == "" {
+		return nil, fmt.Errorf("%w: missing client_id", ErrInvalidToken)
+	}
+
+	return &Principal{ClientID: c.ClientID, Scopes: strings.Fields(c.Scope)}, nil
+}
+
+// keyFor — ключ проверки по kid из заголовка
+func (v *Verifier) keyFor(ctx context.Context, t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		return nil, fmt.Errorf("missing kid header")
+	}
+
+	key, ok, stale := v.lookup(kid)
+	if !ok || stale {
+		if err := v.refresh(ctx, !ok); err != nil {
+			if !ok {
+				return nil, err
+			}
+			// Старые ключи лучше, чем отказ всем сервисам при недоступном auth-service
+			v.logger.Warnw("JWKS refresh failed, using cached keys", "error", err)
+		}
+		if key, ok, _ = v.lookup(kid); !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+	}
+
+	if t.Method.Alg() != key.alg {
+		return nil, fmt.Errorf("algorithm %s does not match key %q", t.Method.Alg(), kid)
+	}
+	return key.key, nil
+}
+
+// lookup — ключ из кэша и признак устаревания кэша
+func (v *Verifier) lookup(kid string) (verificationKey, bool, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	key, ok := v.keys[kid]
+	return key, ok, time.Since(v.fetchedAt) > v.cfg.CacheTTL
+}
+
+// refresh — перечитывает JWKS; из-за неизвестного kid — не чаще minRefreshInterval
+func (v *Verifier) refresh(ctx context.Context, unknownKID bool) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if unknownKID && time.Since(v.fetchedAt) < minRefreshInterval {
+		return fmt.Errorf("jwks refreshed recently")
+	}
+	// Пока ждали блокировку, ключи мог обновить другой запрос
+	if !unknownKID && time.Since(v.fetchedAt) <= v.cfg.CacheTTL {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.cfg.JWKSURL, nil)
+	if err != nil {
+		return fmt.Errorf("build jwks request: %w", err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		v.fetchedAt = time.Now()
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		v.fetchedAt = time.Now()
+		return fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]verificationKey, len(set.Keys))
+	for _, k := range set.Keys {
+		pub, err := k.publicKey()
+		if err != nil {
+			v.logger.Warnw("Skipping invalid JWK", "kid", k.Kid, "error", err)
+			continue
+		}
+		keys[k.Kid] = verificationKey{alg: k.Alg, key: pub}
+	}
+
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.logger.Infow("JWKS loaded", "keys", len(keys))
+	return nil
+}
+
+// publicKey — публичный ключ из JWK
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode n: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode e: %w", err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode x: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}